@@ -1,17 +1,32 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"go-crud-api/internal/config"
 	"go-crud-api/internal/database"
+	"go-crud-api/internal/domain/carts"
+	"go-crud-api/internal/domain/categories"
+	"go-crud-api/internal/domain/orders"
 	"go-crud-api/internal/domain/products"
+	"go-crud-api/internal/domain/reviews"
+	"go-crud-api/internal/domain/transfers"
 	"go-crud-api/internal/domain/users"
+	"go-crud-api/internal/domain/warehouses"
 	customhttp "go-crud-api/internal/http"
 	"go-crud-api/internal/logger"
 	"go-crud-api/internal/repository"
+	"go-crud-api/internal/worker"
+	"go-crud-api/pkg/blobstore"
+	"go-crud-api/pkg/notify"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -54,11 +69,91 @@ func main() {
 	authHandler := users.NewAuthHandler(userService)
 
 	productRepo := repository.NewGormProductRepository(db)
-	productService := products.NewService(productRepo)
-	productHandler := products.NewProductHandler(productService)
+	productService := products.NewService(productRepo, cfg)
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not create blob store")
+	}
+	urlSecret := cfg.BlobURLSecret
+	if urlSecret == "" {
+		urlSecret = cfg.JWTSecret
+	}
+	imageService := products.NewImageService(productRepo, blobStore, blobstore.NewURLSigner(urlSecret, "/v1/images"),
+		worker.ParseInterval(cfg.BlobURLTTL, 15*time.Minute))
+	productHandler := products.NewProductHandler(productService, imageService)
+	notifier, err := newNotifier(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not create notifier")
+	}
+	stockAlertService := products.NewStockAlertService(productRepo, notifier)
+
+	reviewRepo := repository.NewGormReviewRepository(db)
+	reviewService := reviews.NewService(reviewRepo)
+	reviewHandler := reviews.NewReviewHandler(reviewService)
+
+	categoryRepo := repository.NewGormCategoryRepository(db)
+	categoryService := categories.NewService(categoryRepo)
+	categoryHandler := categories.NewCategoryHandler(categoryService)
+
+	orderRepo := repository.NewGormOrderRepository(db)
+	orderService := orders.NewService(orderRepo)
+	orderHandler := orders.NewOrderHandler(orderService)
+
+	cartRepo := repository.NewGormCartRepository(db)
+	cartService := carts.NewService(cartRepo, worker.ParseInterval(cfg.CartReservationTTL, 15*time.Minute))
+	cartHandler := carts.NewCartHandler(cartService)
+
+	transferRepo := repository.NewGormTransferRepository(db)
+	transferService := transfers.NewService(transferRepo)
+	transferHandler := transfers.NewTransferHandler(transferService)
+
+	warehouseRepo := repository.NewGormWarehouseRepository(db)
+	warehouseService := warehouses.NewService(warehouseRepo)
+	warehouseHandler := warehouses.NewWarehouseHandler(warehouseService)
+
+	// Background jobs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go worker.RunEvery(ctx, "apply-price-schedules", worker.ParseInterval(cfg.PriceScheduleInterval, time.Minute), func(ctx context.Context) error {
+		processed, err := productService.ApplyDuePriceSchedules(ctx, time.Now())
+		if processed > 0 {
+			log.Info().Int("count", processed).Msg("Processed due price schedules")
+		}
+		return err
+	})
+
+	go worker.RunEvery(ctx, "deliver-stock-alerts", worker.ParseInterval(cfg.StockAlertInterval, time.Minute), func(ctx context.Context) error {
+		delivered, err := stockAlertService.DeliverPending(ctx)
+		if delivered > 0 {
+			log.Info().Int("count", delivered).Msg("Delivered low-stock alerts")
+		}
+		return err
+	})
+
+	go worker.RunEvery(ctx, "release-cart-reservations", worker.ParseInterval(cfg.CartSweepInterval, time.Minute), func(ctx context.Context) error {
+		released, err := cartService.ReleaseExpiredReservations(ctx)
+		if released > 0 {
+			log.Info().Int64("count", released).Msg("Released expired cart reservations")
+		}
+		return err
+	})
+
+	go worker.RunEvery(ctx, "purge-product-trash", worker.ParseInterval(cfg.TrashPurgeInterval, time.Hour), func(ctx context.Context) error {
+		// Image files are deleted first; their rows go with the purged products.
+		if err := imageService.DeleteExpiredTrashBlobs(ctx, productService.TrashRetention()); err != nil {
+			return err
+		}
+
+		purged, err := productService.PurgeExpiredTrash(ctx)
+		if purged > 0 {
+			log.Info().Int64("count", purged).Msg("Purged expired products from trash")
+		}
+		return err
+	})
 
 	// Initialize Router
-	router := customhttp.InitRouter(cfg, db, authHandler, productHandler)
+	router := customhttp.InitRouter(cfg, db, authHandler, productHandler, categoryHandler, orderHandler, cartHandler, reviewHandler, transferHandler, warehouseHandler)
 
 	// Start server
 	serverAddr := fmt.Sprintf(":%s", cfg.HTTPPort)
@@ -69,6 +164,28 @@ func main() {
 	}
 }
 
+// newBlobStore creates the blob store selected by BLOB_STORE, defaulting to the local filesystem.
+func newBlobStore(cfg config.Config) (blobstore.BlobStore, error) {
+	switch cfg.BlobStore {
+	case "s3":
+		return blobstore.NewS3Store(blobstore.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		})
+	case "", "local":
+		dir := cfg.BlobLocalDir
+		if dir == "" {
+			dir = "./data/blobs"
+		}
+		return blobstore.NewLocalStore(dir)
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q", cfg.BlobStore)
+	}
+}
+
 func runMigrations(db *sql.DB) {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
@@ -90,3 +207,36 @@ func runMigrations(db *sql.DB) {
 
 	log.Info().Msg("Database migrations applied successfully.")
 }
+
+// newNotifier creates a notifier sending through each channel listed in NOTIFY_CHANNELS, defaulting to the log.
+func newNotifier(cfg config.Config) (notify.Notifier, error) {
+	channels := cfg.NotifyChannels
+	if channels == "" {
+		channels = "log"
+	}
+
+	var notifier notify.Multi
+	for _, channel := range strings.Split(channels, ",") {
+		switch strings.TrimSpace(channel) {
+		case "log":
+			notifier = append(notifier, notify.NewLogNotifier())
+		case "email":
+			notifier = append(notifier, notify.NewEmailNotifier(notify.SMTPConfig{
+				Host:     cfg.SMTPHost,
+				Port:     cfg.SMTPPort,
+				Username: cfg.SMTPUsername,
+				Password: cfg.SMTPPassword,
+				From:     cfg.SMTPFrom,
+			}))
+		case "webhook":
+			if cfg.WebhookURL == "" {
+				return nil, fmt.Errorf("WEBHOOK_URL is required for the webhook channel")
+			}
+			notifier = append(notifier, notify.NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookSecret))
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown notification channel %q", channel)
+		}
+	}
+	return notifier, nil
+}
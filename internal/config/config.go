@@ -7,17 +7,42 @@ import (
 // Config stores all configuration of the application.
 // The values are read by viper from a config file or environment variable.
 type Config struct {
-	AppEnv            string `mapstructure:"APP_ENV"`
-	HTTPPort          string `mapstructure:"HTTP_PORT"`
-	JWTSecret         string `mapstructure:"JWT_SECRET"`
-	AccessTokenTTL    string `mapstructure:"ACCESS_TOKEN_TTL"`
-	RefreshTokenTTL   string `mapstructure:"REFRESH_TOKEN_TTL"`
-	DBHost            string `mapstructure:"DB_HOST"`
-	DBPort            string `mapstructure:"DB_PORT"`
-	DBUser            string `mapstructure:"DB_USER"`
-	DBPassword        string `mapstructure:"DB_PASSWORD"`
-	DBName            string `mapstructure:"DB_NAME"`
-	DBSslMode         string `mapstructure:"DB_SSLMODE"`
+	AppEnv                string `mapstructure:"APP_ENV"`
+	HTTPPort              string `mapstructure:"HTTP_PORT"`
+	JWTSecret             string `mapstructure:"JWT_SECRET"`
+	AccessTokenTTL        string `mapstructure:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL       string `mapstructure:"REFRESH_TOKEN_TTL"`
+	DBHost                string `mapstructure:"DB_HOST"`
+	DBPort                string `mapstructure:"DB_PORT"`
+	DBUser                string `mapstructure:"DB_USER"`
+	DBPassword            string `mapstructure:"DB_PASSWORD"`
+	DBName                string `mapstructure:"DB_NAME"`
+	DBSslMode             string `mapstructure:"DB_SSLMODE"`
+	TrashRetention        string `mapstructure:"TRASH_RETENTION"`
+	TrashPurgeInterval    string `mapstructure:"TRASH_PURGE_INTERVAL"`
+	DefaultCurrency       string `mapstructure:"DEFAULT_CURRENCY"`
+	AllowedCurrencies     string `mapstructure:"ALLOWED_CURRENCIES"`
+	PriceScheduleInterval string `mapstructure:"PRICE_SCHEDULE_INTERVAL"`
+	BlobStore             string `mapstructure:"BLOB_STORE"`
+	BlobLocalDir          string `mapstructure:"BLOB_LOCAL_DIR"`
+	BlobURLSecret         string `mapstructure:"BLOB_URL_SECRET"`
+	BlobURLTTL            string `mapstructure:"BLOB_URL_TTL"`
+	S3Endpoint            string `mapstructure:"S3_ENDPOINT"`
+	S3Region              string `mapstructure:"S3_REGION"`
+	S3Bucket              string `mapstructure:"S3_BUCKET"`
+	S3AccessKeyID         string `mapstructure:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey     string `mapstructure:"S3_SECRET_ACCESS_KEY"`
+	NotifyChannels        string `mapstructure:"NOTIFY_CHANNELS"`
+	StockAlertInterval    string `mapstructure:"STOCK_ALERT_INTERVAL"`
+	SMTPHost              string `mapstructure:"SMTP_HOST"`
+	SMTPPort              string `mapstructure:"SMTP_PORT"`
+	SMTPUsername          string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword          string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom              string `mapstructure:"SMTP_FROM"`
+	WebhookURL            string `mapstructure:"WEBHOOK_URL"`
+	WebhookSecret         string `mapstructure:"WEBHOOK_SECRET"`
+	CartReservationTTL    string `mapstructure:"CART_RESERVATION_TTL"`
+	CartSweepInterval     string `mapstructure:"CART_SWEEP_INTERVAL"`
 }
 
 // LoadConfig reads configuration from file or environment variables.
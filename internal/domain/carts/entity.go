@@ -0,0 +1,41 @@
+package carts
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Item is a line of a user's cart. ReservedUntil is set while the item holds its
+// quantity of the product's stock. The product fields are read from the product
+// and reflect its current name and price.
+type Item struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID        uuid.UUID       `gorm:"type:uuid;not null" json:"-"`
+	ProductID     uuid.UUID       `gorm:"type:uuid;not null" json:"product_id"`
+	Quantity      int             `gorm:"type:integer;not null" json:"quantity" example:"2"`
+	ReservedUntil *time.Time      `json:"reserved_until"`
+	ProductName   string          `gorm:"->;-:migration" json:"product_name"`
+	UnitPrice     decimal.Decimal `gorm:"->;-:migration" json:"unit_price" swaggertype:"string" example:"19.90"`
+	Currency      string          `gorm:"->;-:migration" json:"currency" example:"USD"`
+	CreatedAt     time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName overrides the table name used by Item.
+func (Item) TableName() string {
+	return "cart_items"
+}
+
+// Cart is the content of a user's cart.
+type Cart struct {
+	Items []Item `json:"items"`
+}
+
+// ItemInput sets the quantity of a product in the cart and whether it is reserved.
+type ItemInput struct {
+	ProductID uuid.UUID
+	Quantity  int
+	Reserve   bool
+}
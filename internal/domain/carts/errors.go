@@ -0,0 +1,14 @@
+package carts
+
+import "errors"
+
+var (
+	// ErrEmptyCart is returned when checking out a cart without items.
+	ErrEmptyCart = errors.New("cart is empty")
+	// ErrInvalidQuantity is returned when an item quantity is not positive.
+	ErrInvalidQuantity = errors.New("quantity must be positive")
+	// ErrItemNotFound is returned when the product is not in the cart.
+	ErrItemNotFound = errors.New("product is not in the cart")
+	// ErrProductNotFound is returned when adding a product that does not exist.
+	ErrProductNotFound = errors.New("product not found")
+)
@@ -0,0 +1,292 @@
+package carts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go-crud-api/internal/domain/orders"
+	"go-crud-api/internal/domain/products"
+	"go-crud-api/internal/http/middleware"
+	"go-crud-api/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CartHandler handles cart-related requests.
+type CartHandler struct {
+	service  *Service
+	validate *validator.Validate
+}
+
+// NewCartHandler creates a new CartHandler.
+func NewCartHandler(service *Service) *CartHandler {
+	return &CartHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// AddCartItemRequest is the request payload for adding a product to the cart.
+// With reserve set, the item's quantity is held in stock for a limited time.
+type AddCartItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required" swaggertype:"string" format:"uuid"`
+	Quantity  int       `json:"quantity" validate:"required,gt=0" example:"2"`
+	Reserve   bool      `json:"reserve" example:"true"`
+}
+
+// UpdateCartItemRequest is the request payload for changing the quantity of a product in the cart.
+// An item updated without reserve releases its reservation.
+type UpdateCartItemRequest struct {
+	Quantity int  `json:"quantity" validate:"required,gt=0" example:"3"`
+	Reserve  bool `json:"reserve" example:"true"`
+}
+
+// GetCart handles fetching the cart.
+// @Summary Get the cart
+// @Description Get the authenticated user's cart with the current name and price of each product
+// @Tags Cart
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} web.Response{data=Cart} "Cart"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/cart [get]
+func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	cart, err := h.service.Get(r.Context(), userID)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch cart")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: cart})
+}
+
+// AddCartItem handles adding a product to the cart.
+// @Summary Add a product to the cart
+// @Description Add a quantity of a product to the cart, on top of any quantity already in it. With reserve, the item's whole quantity is held in stock until reserved_until; the reservation fails if not enough stock is left unreserved.
+// @Tags Cart
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param item body AddCartItemRequest true "Cart item"
+// @Success 200 {object} web.Response{data=Item} "Cart item"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Insufficient stock to reserve or product not for sale"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/cart/items [post]
+func (h *CartHandler) AddCartItem(w http.ResponseWriter, r *http.Request) {
+	var req AddCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := userFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := h.service.AddItem(r.Context(), userID, ItemInput(req))
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not add product to cart")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: item})
+}
+
+// UpdateCartItem handles changing the quantity of a product in the cart.
+// @Summary Update a cart item
+// @Description Set the quantity of a product in the cart. With reserve, the reservation is renewed for the new quantity; without it, any reservation is released.
+// @Tags Cart
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param item body UpdateCartItemRequest true "Cart item"
+// @Success 200 {object} web.Response{data=Item} "Cart item"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found or not in the cart"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Insufficient stock to reserve or product not for sale"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/cart/items/{productID} [put]
+func (h *CartHandler) UpdateCartItem(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := userFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := h.service.SetItem(r.Context(), userID, ItemInput{ProductID: productID, Quantity: req.Quantity, Reserve: req.Reserve})
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update cart")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: item})
+}
+
+// RemoveCartItem handles removing a product from the cart.
+// @Summary Remove a product from the cart
+// @Description Remove a product from the cart, releasing its reservation
+// @Tags Cart
+// @Security BearerAuth
+// @Param productID path string true "Product ID"
+// @Success 204 "Product removed from the cart"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not in the cart"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/cart/items/{productID} [delete]
+func (h *CartHandler) RemoveCartItem(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := userFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveItem(r.Context(), userID, productID); err != nil {
+		respondWithWriteError(w, r, err, "Could not update cart")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// ClearCart handles emptying the cart.
+// @Summary Empty the cart
+// @Description Remove every product from the cart, releasing their reservations
+// @Tags Cart
+// @Security BearerAuth
+// @Success 204 "Cart emptied"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/cart [delete]
+func (h *CartHandler) ClearCart(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Clear(r.Context(), userID); err != nil {
+		web.RespondWithServerError(w, r, err, "Could not empty cart")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// Checkout handles turning the cart into an order.
+// @Summary Check out the cart
+// @Description Place an order for the items in the cart and empty it, in one transaction. Stock is decremented and prices are snapshotted as when placing an order; reserved items can use the stock they hold.
+// @Tags Cart
+// @Security BearerAuth
+// @Produce json
+// @Success 201 {object} web.Response{data=orders.Order} "Order placed successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Cart is empty or mixes currencies"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Insufficient stock or product not for sale"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/cart/checkout [post]
+func (h *CartHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.Checkout(r.Context(), userID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not check out cart")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: order})
+}
+
+// userFromContext reads the authenticated user ID from the request context.
+// It writes a 401 response and returns false when it is missing.
+func userFromContext(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, ok := r.Context().Value(middleware.ContextKeyUserID).(uuid.UUID)
+	if !ok {
+		web.RespondWithError(w, "unauthorized", "User ID not found in context", http.StatusUnauthorized)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// respondWithWriteError maps service errors to HTTP responses.
+func respondWithWriteError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	apiErr, status := writeError(err, message)
+	if status == http.StatusInternalServerError {
+		web.RespondWithServerError(w, r, err, message)
+		return
+	}
+	web.RespondWithError(w, apiErr.Code, apiErr.Message, status)
+}
+
+// writeError maps a service error to the API error and status code reported for it.
+func writeError(err error, message string) (*web.ApiError, int) {
+	var lineErr *orders.LineError
+	var shortage *products.InsufficientStockError
+	switch {
+	case errors.As(err, &lineErr) && errors.Is(err, products.ErrInsufficientStock):
+		return &web.ApiError{Code: "insufficient_stock", Message: fmt.Sprintf("Not enough stock for product %s: requested %d, available %d",
+			lineErr.ProductID, lineErr.Requested, lineErr.Available)}, http.StatusConflict
+	case errors.As(err, &shortage):
+		return &web.ApiError{Code: "insufficient_stock", Message: fmt.Sprintf("Not enough stock to reserve: available %d", shortage.Available)}, http.StatusConflict
+	case errors.As(err, &lineErr) && errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrProductNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Product not found"}, http.StatusNotFound
+	case errors.As(err, &lineErr) && errors.Is(err, products.ErrNotForSale):
+		return &web.ApiError{Code: "not_for_sale", Message: fmt.Sprintf("Product %s is not for sale", lineErr.ProductID)}, http.StatusConflict
+	case errors.Is(err, products.ErrNotForSale):
+		return &web.ApiError{Code: "not_for_sale", Message: "Product is not for sale"}, http.StatusConflict
+	case errors.Is(err, ErrItemNotFound):
+		return &web.ApiError{Code: "not_found", Message: err.Error()}, http.StatusNotFound
+	case errors.Is(err, products.ErrStockManagedByVariants):
+		return &web.ApiError{Code: "stock_managed_by_variants", Message: err.Error()}, http.StatusConflict
+	case errors.Is(err, ErrEmptyCart), errors.Is(err, ErrInvalidQuantity), errors.Is(err, orders.ErrMixedCurrencies):
+		return &web.ApiError{Code: "validation_error", Message: err.Error()}, http.StatusBadRequest
+	default:
+		return &web.ApiError{Code: "internal_error", Message: message}, http.StatusInternalServerError
+	}
+}
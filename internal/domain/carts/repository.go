@@ -0,0 +1,29 @@
+package carts
+
+import (
+	"context"
+	"time"
+
+	"go-crud-api/internal/domain/orders"
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+)
+
+// CartRepository defines the interface for cart data operations.
+type CartRepository interface {
+	// ListItems returns the items of a user's cart with their product's name and price, oldest first.
+	// Items of products in the trash are left out.
+	ListItems(ctx context.Context, userID uuid.UUID) ([]Item, error)
+	// ListItemsForUpdate is ListItems, locking the items until the transaction ends.
+	ListItemsForUpdate(ctx context.Context, userID uuid.UUID) ([]Item, error)
+	FindItem(ctx context.Context, userID, productID uuid.UUID) (*Item, error)
+	// SaveItem inserts the item, or replaces the quantity and reservation of the user's item for the same product.
+	SaveItem(ctx context.Context, item *Item) error
+	DeleteItem(ctx context.Context, userID, productID uuid.UUID) error
+	DeleteItems(ctx context.Context, userID uuid.UUID) error
+	// ReleaseExpiredReservations clears the reservations that ended at or before now and returns how many there were.
+	ReleaseExpiredReservations(ctx context.Context, now time.Time) (int64, error)
+	// Transaction runs fn in a database transaction shared by the cart, order and product repositories it is given.
+	Transaction(ctx context.Context, fn func(repo CartRepository, orderRepo orders.OrderRepository, stock products.ProductRepository) error) error
+}
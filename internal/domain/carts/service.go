@@ -0,0 +1,171 @@
+package carts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-crud-api/internal/domain/orders"
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Service defines the cart service.
+type Service struct {
+	repo           CartRepository
+	reservationTTL time.Duration
+	now            func() time.Time
+}
+
+// NewService creates a new cart service. Reserved items hold their stock for reservationTTL.
+func NewService(repo CartRepository, reservationTTL time.Duration) *Service {
+	return &Service{repo: repo, reservationTTL: reservationTTL, now: time.Now}
+}
+
+// Get returns the user's cart.
+func (s *Service) Get(ctx context.Context, userID uuid.UUID) (*Cart, error) {
+	items, err := s.repo.ListItems(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cart{Items: items}, nil
+}
+
+// AddItem adds input.Quantity of a product to the user's cart, on top of any quantity already in it.
+func (s *Service) AddItem(ctx context.Context, userID uuid.UUID, input ItemInput) (*Item, error) {
+	return s.putItem(ctx, userID, input, true)
+}
+
+// SetItem sets the quantity of a product already in the user's cart.
+func (s *Service) SetItem(ctx context.Context, userID uuid.UUID, input ItemInput) (*Item, error) {
+	return s.putItem(ctx, userID, input, false)
+}
+
+// RemoveItem removes a product from the user's cart, releasing its reservation.
+func (s *Service) RemoveItem(ctx context.Context, userID, productID uuid.UUID) error {
+	if _, err := s.repo.FindItem(ctx, userID, productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrItemNotFound
+		}
+		return err
+	}
+
+	return s.repo.DeleteItem(ctx, userID, productID)
+}
+
+// Clear empties the user's cart, releasing its reservations.
+func (s *Service) Clear(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.DeleteItems(ctx, userID)
+}
+
+// Checkout places an order for the items in the user's cart and empties it, in one transaction.
+// Reserved items are guaranteed their stock until their reservation expires; the others are
+// placed if stock not reserved by other users is still available. If any item cannot be
+// fulfilled nothing is kept and the orders.LineError is returned.
+func (s *Service) Checkout(ctx context.Context, userID uuid.UUID) (*orders.Order, error) {
+	var order *orders.Order
+	err := s.repo.Transaction(ctx, func(tx CartRepository, orderRepo orders.OrderRepository, stock products.ProductRepository) error {
+		items, err := tx.ListItemsForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return ErrEmptyCart
+		}
+
+		lines := make([]orders.LineInput, 0, len(items))
+		for _, item := range items {
+			lines = append(lines, orders.LineInput{ProductID: item.ProductID, Quantity: item.Quantity})
+		}
+
+		order, err = orders.Place(ctx, orderRepo, stock, userID, lines)
+		if err != nil {
+			return err
+		}
+
+		return tx.DeleteItems(ctx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ReleaseExpiredReservations clears reservations that have expired and returns how many were released.
+// Expired reservations stop holding stock as soon as they expire; this only tidies the cart items up.
+func (s *Service) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	return s.repo.ReleaseExpiredReservations(ctx, s.now())
+}
+
+// putItem stores a cart item, adding to or replacing the quantity already in the cart.
+// A reserved item must fit in the product's stock not reserved by other users, and its
+// reservation is renewed; an item that is not reserved releases any earlier reservation.
+func (s *Service) putItem(ctx context.Context, userID uuid.UUID, input ItemInput, add bool) (*Item, error) {
+	if input.Quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	var item *Item
+	err := s.repo.Transaction(ctx, func(tx CartRepository, _ orders.OrderRepository, stock products.ProductRepository) error {
+		var product *products.Product
+		var err error
+		if input.Reserve {
+			product, err = stock.FindByIDForUpdate(ctx, input.ProductID)
+		} else {
+			product, err = stock.FindByID(ctx, input.ProductID)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProductNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if !product.ForSale() {
+			return products.ErrNotForSale
+		}
+
+		item, err = tx.FindItem(ctx, userID, input.ProductID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound) && add:
+			item = &Item{UserID: userID, ProductID: input.ProductID}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return ErrItemNotFound
+		case err != nil:
+			return err
+		}
+
+		if add {
+			item.Quantity += input.Quantity
+		} else {
+			item.Quantity = input.Quantity
+		}
+
+		item.ReservedUntil = nil
+		if input.Reserve {
+			reserved, err := stock.SumReservedStock(ctx, product.ID, userID)
+			if err != nil {
+				return err
+			}
+			if available := product.Stock - reserved; item.Quantity > available {
+				return &products.InsufficientStockError{Available: max(available, 0)}
+			}
+
+			reservedUntil := s.now().Add(s.reservationTTL)
+			item.ReservedUntil = &reservedUntil
+		}
+
+		item.ProductName = product.Name
+		item.UnitPrice = product.Price
+		item.Currency = product.Currency
+		return tx.SaveItem(ctx, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,266 @@
+package carts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-crud-api/internal/domain/orders"
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockCartRepository is a mock implementation of CartRepository.
+// Its transactions hand out the mock itself, Orders and Stock.
+type MockCartRepository struct {
+	mock.Mock
+	Orders *MockOrderRepository
+	Stock  *MockStockRepository
+}
+
+func (m *MockCartRepository) ListItems(ctx context.Context, userID uuid.UUID) ([]Item, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]Item), args.Error(1)
+}
+
+func (m *MockCartRepository) ListItemsForUpdate(ctx context.Context, userID uuid.UUID) ([]Item, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]Item), args.Error(1)
+}
+
+func (m *MockCartRepository) FindItem(ctx context.Context, userID, productID uuid.UUID) (*Item, error) {
+	args := m.Called(ctx, userID, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Item), args.Error(1)
+}
+
+func (m *MockCartRepository) SaveItem(ctx context.Context, item *Item) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) DeleteItem(ctx context.Context, userID, productID uuid.UUID) error {
+	args := m.Called(ctx, userID, productID)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) DeleteItems(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) ReleaseExpiredReservations(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCartRepository) Transaction(ctx context.Context, fn func(repo CartRepository, orderRepo orders.OrderRepository, stock products.ProductRepository) error) error {
+	return fn(m, m.Orders, m.Stock)
+}
+
+// MockOrderRepository mocks the order repository methods used at checkout.
+type MockOrderRepository struct {
+	orders.OrderRepository
+	mock.Mock
+}
+
+func (m *MockOrderRepository) Create(ctx context.Context, order *orders.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+// MockStockRepository mocks the product repository methods used to read products and change stock.
+type MockStockRepository struct {
+	products.ProductRepository
+	mock.Mock
+}
+
+func (m *MockStockRepository) FindByID(ctx context.Context, id uuid.UUID) (*products.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*products.Product), args.Error(1)
+}
+
+func (m *MockStockRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*products.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*products.Product), args.Error(1)
+}
+
+func (m *MockStockRepository) SumVariantStock(ctx context.Context, productID uuid.UUID) (int, int64, error) {
+	args := m.Called(ctx, productID)
+	return args.Int(0), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStockRepository) ListStockLevels(ctx context.Context, productID uuid.UUID) ([]products.StockLevel, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]products.StockLevel), args.Error(1)
+}
+
+func (m *MockStockRepository) SumReservedStock(ctx context.Context, productID, exceptUserID uuid.UUID) (int, error) {
+	args := m.Called(ctx, productID, exceptUserID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStockRepository) UpdateFields(ctx context.Context, product *products.Product, fields []string) error {
+	args := m.Called(ctx, product, fields)
+	return args.Error(0)
+}
+
+func (m *MockStockRepository) CreateMovement(ctx context.Context, movement *products.StockMovement) error {
+	args := m.Called(ctx, movement)
+	return args.Error(0)
+}
+
+func newMocks() (*MockCartRepository, *MockStockRepository) {
+	stock := new(MockStockRepository)
+	return &MockCartRepository{Orders: new(MockOrderRepository), Stock: stock}, stock
+}
+
+func TestCartService_PutItem(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	productID := uuid.New()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	product := &products.Product{ID: productID, Status: products.StatusActive, Name: "Mug", Price: decimal.RequireFromString("9.90"), Currency: "USD", Stock: 10}
+
+	// Test case 1: Adding a new product without reserving it
+	repo, stock := newMocks()
+	service := NewService(repo, 15*time.Minute)
+	service.now = func() time.Time { return now }
+	stock.On("FindByID", ctx, productID).Return(product, nil).Once()
+	repo.On("FindItem", ctx, userID, productID).Return(nil, gorm.ErrRecordNotFound).Once()
+	repo.On("SaveItem", ctx, mock.AnythingOfType("*carts.Item")).Return(nil).Once()
+	item, err := service.AddItem(ctx, userID, ItemInput{ProductID: productID, Quantity: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, item.Quantity)
+	assert.Nil(t, item.ReservedUntil)
+	assert.Equal(t, "Mug", item.ProductName)
+	repo.AssertExpectations(t)
+	stock.AssertExpectations(t)
+
+	// Test case 2: Adding more of a product and reserving the whole quantity
+	stock.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	repo.On("FindItem", ctx, userID, productID).Return(&Item{UserID: userID, ProductID: productID, Quantity: 2}, nil).Once()
+	stock.On("SumReservedStock", ctx, productID, userID).Return(6, nil).Once()
+	repo.On("SaveItem", ctx, mock.AnythingOfType("*carts.Item")).Return(nil).Once()
+	item, err = service.AddItem(ctx, userID, ItemInput{ProductID: productID, Quantity: 2, Reserve: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, item.Quantity)
+	assert.Equal(t, now.Add(15*time.Minute), *item.ReservedUntil)
+	repo.AssertExpectations(t)
+	stock.AssertExpectations(t)
+
+	// Test case 3: Reserving more than is left unreserved by other users
+	stock.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	repo.On("FindItem", ctx, userID, productID).Return(&Item{UserID: userID, ProductID: productID, Quantity: 4}, nil).Once()
+	stock.On("SumReservedStock", ctx, productID, userID).Return(7, nil).Once()
+	_, err = service.SetItem(ctx, userID, ItemInput{ProductID: productID, Quantity: 4, Reserve: true})
+	var shortage *products.InsufficientStockError
+	assert.ErrorIs(t, err, products.ErrInsufficientStock)
+	assert.True(t, errors.As(err, &shortage))
+	assert.Equal(t, 3, shortage.Available)
+	repo.AssertExpectations(t)
+
+	// Test case 4: Updating without reserve releases the reservation
+	reservedUntil := now.Add(time.Minute)
+	stock.On("FindByID", ctx, productID).Return(product, nil).Once()
+	repo.On("FindItem", ctx, userID, productID).Return(&Item{UserID: userID, ProductID: productID, Quantity: 4, ReservedUntil: &reservedUntil}, nil).Once()
+	repo.On("SaveItem", ctx, mock.AnythingOfType("*carts.Item")).Return(nil).Once()
+	item, err = service.SetItem(ctx, userID, ItemInput{ProductID: productID, Quantity: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, item.Quantity)
+	assert.Nil(t, item.ReservedUntil)
+	repo.AssertExpectations(t)
+
+	// Test case 5: Updating a product that is not in the cart
+	stock.On("FindByID", ctx, productID).Return(product, nil).Once()
+	repo.On("FindItem", ctx, userID, productID).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.SetItem(ctx, userID, ItemInput{ProductID: productID, Quantity: 1})
+	assert.ErrorIs(t, err, ErrItemNotFound)
+
+	// Test case 6: Unknown product, product not for sale and invalid quantity
+	missingID := uuid.New()
+	stock.On("FindByID", ctx, missingID).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.AddItem(ctx, userID, ItemInput{ProductID: missingID, Quantity: 1})
+	assert.ErrorIs(t, err, ErrProductNotFound)
+	draftID := uuid.New()
+	stock.On("FindByID", ctx, draftID).Return(&products.Product{ID: draftID, Status: products.StatusDraft, Stock: 5}, nil).Once()
+	_, err = service.AddItem(ctx, userID, ItemInput{ProductID: draftID, Quantity: 1})
+	assert.ErrorIs(t, err, products.ErrNotForSale)
+	_, err = service.AddItem(ctx, userID, ItemInput{ProductID: productID, Quantity: 0})
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+}
+
+func TestCartService_Checkout(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	productID := uuid.New()
+
+	// Test case 1: The cart becomes an order and is emptied
+	repo, stock := newMocks()
+	service := NewService(repo, 15*time.Minute)
+	product := &products.Product{ID: productID, Status: products.StatusActive, Name: "Mug", Price: decimal.RequireFromString("9.90"), Currency: "USD", Stock: 3}
+	repo.On("ListItemsForUpdate", ctx, userID).Return([]Item{{UserID: userID, ProductID: productID, Quantity: 2}}, nil).Once()
+	stock.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	stock.On("SumVariantStock", ctx, productID).Return(0, int64(0), nil).Once()
+	stock.On("ListStockLevels", ctx, productID).Return([]products.StockLevel(nil), nil).Once()
+	stock.On("SumReservedStock", ctx, productID, userID).Return(0, nil).Once()
+	stock.On("UpdateFields", ctx, product, []string{"stock"}).Return(nil).Once()
+	stock.On("CreateMovement", ctx, mock.AnythingOfType("*products.StockMovement")).Return(nil).Once()
+	repo.Orders.On("Create", ctx, mock.AnythingOfType("*orders.Order")).Return(nil).Once()
+	repo.On("DeleteItems", ctx, userID).Return(nil).Once()
+	order, err := service.Checkout(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, order.CustomerID)
+	assert.True(t, decimal.RequireFromString("19.80").Equal(order.Total))
+	assert.Equal(t, 1, product.Stock)
+	repo.AssertExpectations(t)
+	repo.Orders.AssertExpectations(t)
+	stock.AssertExpectations(t)
+
+	// Test case 2: Stock reserved by others fails the checkout and keeps the cart
+	repo, stock = newMocks()
+	service = NewService(repo, 15*time.Minute)
+	repo.On("ListItemsForUpdate", ctx, userID).Return([]Item{{UserID: userID, ProductID: productID, Quantity: 2}}, nil).Once()
+	stock.On("FindByIDForUpdate", ctx, productID).Return(&products.Product{ID: productID, Status: products.StatusActive, Stock: 3}, nil).Once()
+	stock.On("SumVariantStock", ctx, productID).Return(0, int64(0), nil).Once()
+	stock.On("ListStockLevels", ctx, productID).Return([]products.StockLevel(nil), nil).Once()
+	stock.On("SumReservedStock", ctx, productID, userID).Return(2, nil).Once()
+	_, err = service.Checkout(ctx, userID)
+	var lineErr *orders.LineError
+	assert.ErrorIs(t, err, products.ErrInsufficientStock)
+	assert.True(t, errors.As(err, &lineErr))
+	assert.Equal(t, 1, lineErr.Available)
+	repo.AssertNotCalled(t, "DeleteItems", mock.Anything, mock.Anything)
+
+	// Test case 3: Empty cart
+	repo.On("ListItemsForUpdate", ctx, userID).Return([]Item{}, nil).Once()
+	_, err = service.Checkout(ctx, userID)
+	assert.ErrorIs(t, err, ErrEmptyCart)
+}
+
+func TestCartService_ReleaseExpiredReservations(t *testing.T) {
+	repo, _ := newMocks()
+	service := NewService(repo, 15*time.Minute)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	repo.On("ReleaseExpiredReservations", context.Background(), now).Return(int64(3), nil).Once()
+	released, err := service.ReleaseExpiredReservations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), released)
+	repo.AssertExpectations(t)
+}
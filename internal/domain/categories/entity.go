@@ -0,0 +1,32 @@
+package categories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category represents a node of the product category tree.
+type Category struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name      string     `gorm:"type:varchar(120);not null" json:"name"`
+	Slug      string     `gorm:"type:varchar(140);not null;unique" json:"slug" example:"home-garden"`
+	ParentID  *uuid.UUID `gorm:"type:uuid" json:"parent_id"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// CategoryWithCount is a category together with the number of products
+// assigned to it or to any of its subcategories.
+type CategoryWithCount struct {
+	Category
+	ProductCount int64 `json:"product_count"`
+}
+
+// CategoryInput holds the editable fields of a category.
+// An empty slug is derived from the name; a nil parent makes a top-level category.
+type CategoryInput struct {
+	Name     string
+	Slug     string
+	ParentID *uuid.UUID
+}
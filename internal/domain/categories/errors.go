@@ -0,0 +1,16 @@
+package categories
+
+import "errors"
+
+var (
+	// ErrInvalidSlug is returned when a slug is not made of lowercase letters, digits and single hyphens.
+	ErrInvalidSlug = errors.New("slug must contain only lowercase letters, digits and single hyphens")
+	// ErrSlugTaken is returned when another category already uses the slug.
+	ErrSlugTaken = errors.New("slug is already in use")
+	// ErrParentNotFound is returned when the parent category does not exist.
+	ErrParentNotFound = errors.New("parent category not found")
+	// ErrCategoryCycle is returned when a category would become its own ancestor.
+	ErrCategoryCycle = errors.New("a category cannot be nested under itself or its subcategories")
+	// ErrCategoryHasChildren is returned when deleting a category that still has subcategories.
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+)
@@ -0,0 +1,210 @@
+package categories
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go-crud-api/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CategoryHandler handles category-related requests.
+type CategoryHandler struct {
+	service  *Service
+	validate *validator.Validate
+}
+
+// NewCategoryHandler creates a new CategoryHandler.
+func NewCategoryHandler(service *Service) *CategoryHandler {
+	return &CategoryHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CategoryRequest is the request payload for creating or updating a category.
+// An empty slug is derived from the name; a missing parent_id makes a top-level category.
+type CategoryRequest struct {
+	Name     string     `json:"name" validate:"required,min=2,max=120" example:"Home & Garden"`
+	Slug     string     `json:"slug" validate:"omitempty,max=140" example:"home-garden"`
+	ParentID *uuid.UUID `json:"parent_id" swaggertype:"string" format:"uuid"`
+}
+
+// CreateCategory handles category creation.
+// @Summary Create a category
+// @Description Create a category, optionally nested under a parent (Admin only)
+// @Tags Categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param category body CategoryRequest true "Category data"
+// @Success 201 {object} web.Response{data=Category} "Category created successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Slug already in use"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/categories [post]
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var req CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.service.Create(r.Context(), CategoryInput(req))
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not create category")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: category})
+}
+
+// ListCategories handles fetching all categories.
+// @Summary Get all categories
+// @Description Get all categories with their parent and the number of products in them, subcategories included
+// @Tags Categories
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} web.Response{data=[]CategoryWithCount} "List of categories"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/categories [get]
+func (h *CategoryHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.service.List(r.Context())
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch categories")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: categories})
+}
+
+// GetCategoryByID handles fetching a category by its ID.
+// @Summary Get category by ID
+// @Description Get details of a specific category
+// @Tags Categories
+// @Security BearerAuth
+// @Produce json
+// @Param categoryID path string true "Category ID"
+// @Success 200 {object} web.Response{data=Category} "Category details"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid category ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Category not found"
+// @Router /v1/categories/{categoryID} [get]
+func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "categoryID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid category ID format", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Category not found", http.StatusNotFound)
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: category})
+}
+
+// UpdateCategory handles updating a category.
+// @Summary Update a category
+// @Description Rename a category, change its slug or move it under another parent (Admin only)
+// @Tags Categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param categoryID path string true "Category ID"
+// @Param category body CategoryRequest true "Category data"
+// @Success 200 {object} web.Response{data=Category} "Category updated successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request, validation error or cycle"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Category not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Slug already in use"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/categories/{categoryID} [put]
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "categoryID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid category ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.service.Update(r.Context(), id, CategoryInput(req))
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update category")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: category})
+}
+
+// DeleteCategory handles deleting a category.
+// @Summary Delete a category
+// @Description Delete a category without subcategories; its products become uncategorised (Admin only)
+// @Tags Categories
+// @Security BearerAuth
+// @Produce json
+// @Param categoryID path string true "Category ID"
+// @Success 204 "Category deleted successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid category ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Category not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Category has subcategories"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/categories/{categoryID} [delete]
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "categoryID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid category ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		respondWithWriteError(w, r, err, "Could not delete category")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// respondWithWriteError maps errors from category writes to the matching HTTP response.
+func respondWithWriteError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch {
+	case errors.Is(err, ErrInvalidSlug), errors.Is(err, ErrParentNotFound), errors.Is(err, ErrCategoryCycle):
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrSlugTaken):
+		web.RespondWithError(w, "conflict", err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrCategoryHasChildren):
+		web.RespondWithError(w, "conflict", "Move or delete the subcategories first", http.StatusConflict)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		web.RespondWithError(w, "not_found", "Category not found", http.StatusNotFound)
+	default:
+		web.RespondWithServerError(w, r, err, message)
+	}
+}
@@ -0,0 +1,21 @@
+package categories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CategoryRepository defines the interface for category data operations.
+type CategoryRepository interface {
+	Create(ctx context.Context, category *Category) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Category, error)
+	FindBySlug(ctx context.Context, slug string) (*Category, error)
+	Update(ctx context.Context, category *Category) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListWithCounts returns every category with its product count, subcategories included.
+	ListWithCounts(ctx context.Context) ([]CategoryWithCount, error)
+	// ListDescendantIDs returns the IDs of all subcategories of a category, at any depth.
+	ListDescendantIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	HasChildren(ctx context.Context, id uuid.UUID) (bool, error)
+}
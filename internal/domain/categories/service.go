@@ -0,0 +1,166 @@
+package categories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+)
+
+// slugPattern matches lowercase words of letters and digits joined by single hyphens.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Service defines the category service.
+type Service struct {
+	repo CategoryRepository
+}
+
+// NewService creates a new category service.
+func NewService(repo CategoryRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Create creates a new category.
+func (s *Service) Create(ctx context.Context, input CategoryInput) (*Category, error) {
+	category := &Category{}
+	if err := s.apply(ctx, category, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, category); err != nil {
+		return nil, translateError(err)
+	}
+
+	return category, nil
+}
+
+// FindByID finds a category by its ID.
+func (s *Service) FindByID(ctx context.Context, id uuid.UUID) (*Category, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Update updates a category, possibly moving it under another parent.
+func (s *Service) Update(ctx context.Context, id uuid.UUID, input CategoryInput) (*Category, error) {
+	category, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ParentID != nil {
+		if *input.ParentID == id {
+			return nil, ErrCategoryCycle
+		}
+
+		descendants, err := s.repo.ListDescendantIDs(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(descendants, *input.ParentID) {
+			return nil, ErrCategoryCycle
+		}
+	}
+
+	if err := s.apply(ctx, category, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, category); err != nil {
+		return nil, translateError(err)
+	}
+
+	return category, nil
+}
+
+// Delete deletes a category. Its products are left uncategorised;
+// categories that still have subcategories cannot be deleted.
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	hasChildren, err := s.repo.HasChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return ErrCategoryHasChildren
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// List returns all categories with their product counts, subcategories included.
+func (s *Service) List(ctx context.Context) ([]CategoryWithCount, error) {
+	return s.repo.ListWithCounts(ctx)
+}
+
+// apply validates input and copies it onto category.
+func (s *Service) apply(ctx context.Context, category *Category, input CategoryInput) error {
+	slug := input.Slug
+	if slug == "" {
+		slug = Slugify(input.Name)
+	}
+	if !slugPattern.MatchString(slug) {
+		return ErrInvalidSlug
+	}
+
+	if slug != category.Slug {
+		existing, err := s.repo.FindBySlug(ctx, slug)
+		if err == nil && existing.ID != category.ID {
+			return ErrSlugTaken
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+
+	if input.ParentID != nil {
+		if _, err := s.repo.FindByID(ctx, *input.ParentID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrParentNotFound
+			}
+			return err
+		}
+	}
+
+	category.Name = input.Name
+	category.Slug = slug
+	category.ParentID = input.ParentID
+	return nil
+}
+
+// translateError maps a unique violation on the slug, lost to a concurrent write, to ErrSlugTaken.
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrSlugTaken
+	}
+	return err
+}
+
+// Slugify derives a URL slug from a name: accents are stripped, letters are lowercased
+// and every run of other characters becomes a single hyphen.
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks left over from decomposing accented letters.
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			hyphen = true
+		}
+	}
+	return b.String()
+}
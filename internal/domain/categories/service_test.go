@@ -0,0 +1,185 @@
+package categories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockCategoryRepository is a mock implementation of CategoryRepository.
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Create(ctx context.Context, category *Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindBySlug(ctx context.Context, slug string) (*Category, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, category *Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) ListWithCounts(ctx context.Context) ([]CategoryWithCount, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]CategoryWithCount), args.Error(1)
+}
+
+func (m *MockCategoryRepository) ListDescendantIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockCategoryRepository) HasChildren(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "home-garden", Slugify("Home & Garden"))
+	assert.Equal(t, "cafe-e-acucar", Slugify("  Café e Açúcar! "))
+	assert.Equal(t, "tv-4k", Slugify("TV--4K"))
+	assert.Equal(t, "", Slugify("***"))
+}
+
+func TestCategoryService_Create(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	service := NewService(repo)
+
+	ctx := context.Background()
+	parentID := uuid.New()
+
+	// Test case 1: Slug derived from the name, nested under a parent
+	repo.On("FindBySlug", ctx, "garden-tools").Return(nil, gorm.ErrRecordNotFound).Once()
+	repo.On("FindByID", ctx, parentID).Return(&Category{ID: parentID}, nil).Once()
+	repo.On("Create", ctx, mock.AnythingOfType("*categories.Category")).Return(nil).Once()
+	category, err := service.Create(ctx, CategoryInput{Name: "Garden Tools", ParentID: &parentID})
+	assert.NoError(t, err)
+	assert.Equal(t, "garden-tools", category.Slug)
+	assert.Equal(t, &parentID, category.ParentID)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Slug already in use
+	repo.On("FindBySlug", ctx, "tools").Return(&Category{ID: uuid.New(), Slug: "tools"}, nil).Once()
+	_, err = service.Create(ctx, CategoryInput{Name: "Tools"})
+	assert.ErrorIs(t, err, ErrSlugTaken)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Invalid explicit slug
+	_, err = service.Create(ctx, CategoryInput{Name: "Tools", Slug: "Tools_2"})
+	assert.ErrorIs(t, err, ErrInvalidSlug)
+
+	// Test case 4: Parent does not exist
+	repo.On("FindBySlug", ctx, "tools").Return(nil, gorm.ErrRecordNotFound).Once()
+	repo.On("FindByID", ctx, parentID).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.Create(ctx, CategoryInput{Name: "Tools", ParentID: &parentID})
+	assert.ErrorIs(t, err, ErrParentNotFound)
+	repo.AssertExpectations(t)
+
+	// Test case 5: Slug taken by a concurrent write
+	repo.On("FindBySlug", ctx, "tools").Return(nil, gorm.ErrRecordNotFound).Once()
+	repo.On("Create", ctx, mock.AnythingOfType("*categories.Category")).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.Create(ctx, CategoryInput{Name: "Tools"})
+	assert.ErrorIs(t, err, ErrSlugTaken)
+	repo.AssertExpectations(t)
+}
+
+func TestCategoryService_Update(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	service := NewService(repo)
+
+	ctx := context.Background()
+	id := uuid.New()
+	childID := uuid.New()
+	otherID := uuid.New()
+
+	// Test case 1: Move under another category, keeping the slug
+	repo.On("FindByID", ctx, id).Return(&Category{ID: id, Name: "Tools", Slug: "tools"}, nil).Once()
+	repo.On("ListDescendantIDs", ctx, id).Return([]uuid.UUID{childID}, nil).Once()
+	repo.On("FindByID", ctx, otherID).Return(&Category{ID: otherID}, nil).Once()
+	repo.On("Update", ctx, mock.AnythingOfType("*categories.Category")).Return(nil).Once()
+	category, err := service.Update(ctx, id, CategoryInput{Name: "Tools", Slug: "tools", ParentID: &otherID})
+	assert.NoError(t, err)
+	assert.Equal(t, &otherID, category.ParentID)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Moving under a subcategory would create a cycle
+	repo.On("FindByID", ctx, id).Return(&Category{ID: id, Slug: "tools"}, nil).Once()
+	repo.On("ListDescendantIDs", ctx, id).Return([]uuid.UUID{childID}, nil).Once()
+	_, err = service.Update(ctx, id, CategoryInput{Name: "Tools", ParentID: &childID})
+	assert.ErrorIs(t, err, ErrCategoryCycle)
+	repo.AssertExpectations(t)
+
+	// Test case 3: A category cannot be its own parent
+	repo.On("FindByID", ctx, id).Return(&Category{ID: id, Slug: "tools"}, nil).Once()
+	_, err = service.Update(ctx, id, CategoryInput{Name: "Tools", ParentID: &id})
+	assert.ErrorIs(t, err, ErrCategoryCycle)
+	repo.AssertExpectations(t)
+
+	// Test case 4: Category not found
+	repo.On("FindByID", ctx, id).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.Update(ctx, id, CategoryInput{Name: "Tools"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestCategoryService_Delete(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	service := NewService(repo)
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	// Test case 1: Successful delete
+	repo.On("FindByID", ctx, id).Return(&Category{ID: id}, nil).Once()
+	repo.On("HasChildren", ctx, id).Return(false, nil).Once()
+	repo.On("Delete", ctx, id).Return(nil).Once()
+	assert.NoError(t, service.Delete(ctx, id))
+	repo.AssertExpectations(t)
+
+	// Test case 2: Category with subcategories
+	repo.On("FindByID", ctx, id).Return(&Category{ID: id}, nil).Once()
+	repo.On("HasChildren", ctx, id).Return(true, nil).Once()
+	assert.ErrorIs(t, service.Delete(ctx, id), ErrCategoryHasChildren)
+	repo.AssertExpectations(t)
+}
+
+func TestCategoryService_List(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	service := NewService(repo)
+
+	ctx := context.Background()
+	expected := []CategoryWithCount{{Category: Category{Name: "Tools"}, ProductCount: 3}}
+
+	repo.On("ListWithCounts", ctx).Return(expected, nil).Once()
+	categories, err := service.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, categories)
+	repo.AssertExpectations(t)
+}
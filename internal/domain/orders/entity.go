@@ -0,0 +1,61 @@
+package orders
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Order statuses. Orders start pending, are paid and then shipped; pending and paid
+// orders can be cancelled, which returns their stock.
+const (
+	StatusPending   = "pending"
+	StatusPaid      = "paid"
+	StatusShipped   = "shipped"
+	StatusCancelled = "cancelled"
+)
+
+// Order represents a customer order. Its total and currency are fixed when it is placed.
+type Order struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	CustomerID  uuid.UUID       `gorm:"type:uuid;not null" json:"customer_id"`
+	Status      string          `gorm:"type:order_status;not null;default:pending" json:"status" example:"pending"`
+	Currency    string          `gorm:"type:char(3);not null" json:"currency" example:"USD"`
+	Total       decimal.Decimal `gorm:"type:numeric(19,4);not null" json:"total" swaggertype:"string" example:"59.70"`
+	Items       []Item          `gorm:"foreignKey:OrderID" json:"items"`
+	PaidAt      *time.Time      `json:"paid_at"`
+	ShippedAt   *time.Time      `json:"shipped_at"`
+	CancelledAt *time.Time      `json:"cancelled_at"`
+	CreatedAt   time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// Item is a line of an order, with the product name and unit price as they were when the order was placed.
+// ProductID is nil once the product has been purged.
+type Item struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrderID     uuid.UUID       `gorm:"type:uuid;not null" json:"-"`
+	ProductID   *uuid.UUID      `gorm:"type:uuid" json:"product_id"`
+	ProductName string          `gorm:"type:varchar(120);not null" json:"product_name"`
+	Quantity    int             `gorm:"type:integer;not null" json:"quantity" example:"3"`
+	UnitPrice   decimal.Decimal `gorm:"type:numeric(19,4);not null" json:"unit_price" swaggertype:"string" example:"19.90"`
+	LineTotal   decimal.Decimal `gorm:"type:numeric(19,4);not null" json:"line_total" swaggertype:"string" example:"59.70"`
+}
+
+// TableName overrides the table name used by Item.
+func (Item) TableName() string {
+	return "order_items"
+}
+
+// LineInput is a product and quantity requested when placing an order.
+type LineInput struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+// ListFilter narrows an order listing. Nil fields are not filtered on.
+type ListFilter struct {
+	CustomerID *uuid.UUID
+	Status     *string
+}
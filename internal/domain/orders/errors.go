@@ -0,0 +1,39 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrEmptyOrder is returned when placing an order without lines.
+	ErrEmptyOrder = errors.New("order must have at least one line")
+	// ErrInvalidQuantity is returned when a line quantity is not positive.
+	ErrInvalidQuantity = errors.New("quantity must be positive")
+	// ErrMixedCurrencies is returned when the products of an order are priced in different currencies.
+	ErrMixedCurrencies = errors.New("all products of an order must be priced in the same currency")
+	// ErrInvalidTransition is returned when an order cannot move to the requested status from its current one.
+	ErrInvalidTransition = errors.New("order cannot move to this status")
+	// ErrForbidden is returned when the actor may not view or change the order.
+	ErrForbidden = errors.New("not allowed to access this order")
+)
+
+// LineError reports the order line that could not be placed. Err is typically
+// a *products.InsufficientStockError, in which case Requested and Available are set,
+// or gorm.ErrRecordNotFound for a product that does not exist.
+type LineError struct {
+	ProductID uuid.UUID
+	Requested int
+	Available int
+	Err       error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("product %s: %v", e.ProductID, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
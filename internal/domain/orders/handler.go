@@ -0,0 +1,308 @@
+package orders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go-crud-api/internal/domain/products"
+	"go-crud-api/internal/http/middleware"
+	"go-crud-api/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrderHandler handles order-related requests.
+type OrderHandler struct {
+	service  *Service
+	validate *validator.Validate
+}
+
+// NewOrderHandler creates a new OrderHandler.
+func NewOrderHandler(service *Service) *OrderHandler {
+	return &OrderHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// OrderLineRequest is a product and quantity in a PlaceOrderRequest.
+type OrderLineRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required" swaggertype:"string" format:"uuid"`
+	Quantity  int       `json:"quantity" validate:"required,gt=0" example:"3"`
+}
+
+// PlaceOrderRequest is the request payload for placing an order.
+type PlaceOrderRequest struct {
+	Lines []OrderLineRequest `json:"lines" validate:"required,min=1,max=100,dive"`
+}
+
+// PlaceOrder handles placing an order.
+// @Summary Place an order
+// @Description Place an order for the authenticated user. Stock of every line is checked and decremented atomically and unit prices are snapshotted; if any line is short nothing is reserved. All products must share one currency.
+// @Tags Orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param order body PlaceOrderRequest true "Order lines"
+// @Success 201 {object} web.Response{data=Order} "Order placed successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Insufficient stock or product not for sale"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/orders [post]
+func (h *OrderHandler) PlaceOrder(w http.ResponseWriter, r *http.Request) {
+	var req PlaceOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	lines := make([]LineInput, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, LineInput(line))
+	}
+
+	order, err := h.service.PlaceOrder(r.Context(), actor.UserID, lines)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not place order")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: order})
+}
+
+// ListOrders handles listing orders.
+// @Summary List orders
+// @Description List orders, newest first. Users see their own orders; admins see every customer's, optionally filtered by customer_id.
+// @Tags Orders
+// @Security BearerAuth
+// @Produce json
+// @Param customer_id query string false "Customer ID (admin only)"
+// @Param status query string false "Order status" Enums(pending, paid, shipped, cancelled)
+// @Success 200 {object} web.Response{data=[]Order} "List of orders"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid customer ID or status"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/orders [get]
+func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	filter := ListFilter{CustomerID: &actor.UserID}
+	if actor.IsAdmin() {
+		filter.CustomerID = nil
+		if customerParam := r.URL.Query().Get("customer_id"); customerParam != "" {
+			id, err := uuid.Parse(customerParam)
+			if err != nil {
+				web.RespondWithError(w, "bad_request", "Invalid customer ID format", http.StatusBadRequest)
+				return
+			}
+			filter.CustomerID = &id
+		}
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		switch status {
+		case StatusPending, StatusPaid, StatusShipped, StatusCancelled:
+			filter.Status = &status
+		default:
+			web.RespondWithError(w, "bad_request", "Invalid order status", http.StatusBadRequest)
+			return
+		}
+	}
+
+	orders, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch orders")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: orders})
+}
+
+// GetOrderByID handles fetching an order by its ID.
+// @Summary Get order by ID
+// @Description Get an order with its lines. Users can only see their own orders.
+// @Tags Orders
+// @Security BearerAuth
+// @Produce json
+// @Param orderID path string true "Order ID"
+// @Success 200 {object} web.Response{data=Order} "Order details"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid order ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not the customer or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Order not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/orders/{orderID} [get]
+func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid order ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.FindByID(r.Context(), actor, id)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not fetch order")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: order})
+}
+
+// PayOrder handles marking an order as paid.
+// @Summary Mark an order as paid
+// @Description Move a pending order to paid (Admin only)
+// @Tags Orders
+// @Security BearerAuth
+// @Produce json
+// @Param orderID path string true "Order ID"
+// @Success 200 {object} web.Response{data=Order} "Order paid"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid order ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Order not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Order is not pending"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/orders/{orderID}/pay [post]
+func (h *OrderHandler) PayOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid order ID format", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.service.Pay(r.Context(), id)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update order")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: order})
+}
+
+// ShipOrder handles marking an order as shipped.
+// @Summary Mark an order as shipped
+// @Description Move a paid order to shipped (Admin only)
+// @Tags Orders
+// @Security BearerAuth
+// @Produce json
+// @Param orderID path string true "Order ID"
+// @Success 200 {object} web.Response{data=Order} "Order shipped"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid order ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Order not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Order is not paid"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/orders/{orderID}/ship [post]
+func (h *OrderHandler) ShipOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid order ID format", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.service.Ship(r.Context(), id)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update order")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: order})
+}
+
+// CancelOrder handles cancelling an order.
+// @Summary Cancel an order
+// @Description Cancel an order and return its stock. Users can cancel their own pending orders; admins can also cancel paid orders. Shipped orders cannot be cancelled.
+// @Tags Orders
+// @Security BearerAuth
+// @Produce json
+// @Param orderID path string true "Order ID"
+// @Success 200 {object} web.Response{data=Order} "Order cancelled"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid order ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Order not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Order can no longer be cancelled"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/orders/{orderID}/cancel [post]
+func (h *OrderHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid order ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.Cancel(r.Context(), actor, id)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not cancel order")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: order})
+}
+
+// respondWithWriteError maps service errors to HTTP responses.
+func respondWithWriteError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	apiErr, status := writeError(err, message)
+	if status == http.StatusInternalServerError {
+		web.RespondWithServerError(w, r, err, message)
+		return
+	}
+	web.RespondWithError(w, apiErr.Code, apiErr.Message, status)
+}
+
+// writeError maps a service error to the API error and status code reported for it.
+func writeError(err error, message string) (*web.ApiError, int) {
+	var lineErr *LineError
+	switch {
+	case errors.As(err, &lineErr) && errors.Is(err, products.ErrInsufficientStock):
+		return &web.ApiError{Code: "insufficient_stock", Message: fmt.Sprintf("Not enough stock for product %s: requested %d, available %d",
+			lineErr.ProductID, lineErr.Requested, lineErr.Available)}, http.StatusConflict
+	case errors.As(err, &lineErr) && errors.Is(err, gorm.ErrRecordNotFound):
+		return &web.ApiError{Code: "not_found", Message: fmt.Sprintf("Product %s not found", lineErr.ProductID)}, http.StatusNotFound
+	case errors.As(err, &lineErr) && errors.Is(err, products.ErrNotForSale):
+		return &web.ApiError{Code: "not_for_sale", Message: fmt.Sprintf("Product %s is not for sale", lineErr.ProductID)}, http.StatusConflict
+	case errors.Is(err, products.ErrStockManagedByVariants):
+		return &web.ApiError{Code: "stock_managed_by_variants", Message: err.Error()}, http.StatusConflict
+	case errors.Is(err, ErrEmptyOrder), errors.Is(err, ErrInvalidQuantity), errors.Is(err, ErrMixedCurrencies):
+		return &web.ApiError{Code: "validation_error", Message: err.Error()}, http.StatusBadRequest
+	case errors.Is(err, ErrInvalidTransition):
+		return &web.ApiError{Code: "conflict", Message: err.Error()}, http.StatusConflict
+	case errors.Is(err, ErrForbidden):
+		return &web.ApiError{Code: "forbidden", Message: "You do not have permission to access this order"}, http.StatusForbidden
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Order not found"}, http.StatusNotFound
+	default:
+		return &web.ApiError{Code: "internal_error", Message: message}, http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,25 @@
+package orders
+
+import (
+	"context"
+
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+)
+
+// OrderRepository defines the interface for order data operations.
+type OrderRepository interface {
+	// Create inserts the order together with its items.
+	Create(ctx context.Context, order *Order) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Order, error)
+	// FindByIDForUpdate finds an order with its items and locks its row until the transaction ends.
+	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*Order, error)
+	// List returns the orders matching the filter, newest first.
+	List(ctx context.Context, filter ListFilter) ([]Order, error)
+	// UpdateStatus saves the status and the paid, shipped and cancelled timestamps of an order.
+	UpdateStatus(ctx context.Context, order *Order) error
+	// Transaction runs fn in a database transaction shared by the order and product repositories
+	// it is given, so stock changes commit or roll back together with the order.
+	Transaction(ctx context.Context, fn func(repo OrderRepository, stock products.ProductRepository) error) error
+}
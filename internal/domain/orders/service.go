@@ -0,0 +1,254 @@
+package orders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Service defines the order service.
+type Service struct {
+	repo OrderRepository
+	now  func() time.Time
+}
+
+// NewService creates a new order service.
+func NewService(repo OrderRepository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// PlaceOrder places an order for customerID. In one transaction every product is locked,
+// its stock decremented through the movement ledger and its current price snapshotted
+// into the line. Stock reserved in other customers' carts is not available. If any line
+// cannot be fulfilled nothing is kept and a *LineError naming the product is returned.
+func (s *Service) PlaceOrder(ctx context.Context, customerID uuid.UUID, lines []LineInput) (*Order, error) {
+	var order *Order
+	err := s.repo.Transaction(ctx, func(tx OrderRepository, stock products.ProductRepository) error {
+		var err error
+		order, err = Place(ctx, tx, stock, customerID, lines)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// Place places an order inside the caller's transaction, as PlaceOrder does.
+// It is how other domains, such as carts, turn their lines into an order.
+func Place(ctx context.Context, tx OrderRepository, stock products.ProductRepository, customerID uuid.UUID, lines []LineInput) (*Order, error) {
+	lines, err := mergeLines(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		ID:         uuid.New(),
+		CustomerID: customerID,
+		Status:     StatusPending,
+		Total:      decimal.Zero,
+	}
+	adjustmentNote := "Order " + order.ID.String()
+
+	for _, line := range lines {
+		product, _, err := products.ChangeStock(ctx, stock, line.ProductID, products.StockAdjustment{
+			Delta:               -line.Quantity,
+			Reason:              products.ReasonSale,
+			Note:                adjustmentNote,
+			RespectReservations: true,
+		}, customerID)
+		if err != nil {
+			lineErr := &LineError{ProductID: line.ProductID, Err: err}
+			var shortage *products.InsufficientStockError
+			if errors.As(err, &shortage) {
+				lineErr.Requested = line.Quantity
+				lineErr.Available = shortage.Available
+			}
+			return nil, lineErr
+		}
+
+		if order.Currency == "" {
+			order.Currency = product.Currency
+		} else if product.Currency != order.Currency {
+			return nil, &LineError{ProductID: line.ProductID, Err: ErrMixedCurrencies}
+		}
+
+		productID := product.ID
+		lineTotal := product.Price.Mul(decimal.NewFromInt(int64(line.Quantity)))
+		order.Items = append(order.Items, Item{
+			OrderID:     order.ID,
+			ProductID:   &productID,
+			ProductName: product.Name,
+			Quantity:    line.Quantity,
+			UnitPrice:   product.Price,
+			LineTotal:   lineTotal,
+		})
+		order.Total = order.Total.Add(lineTotal)
+	}
+
+	if err := tx.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// FindByID finds an order. Customers can only see their own orders.
+func (s *Service) FindByID(ctx context.Context, actor products.Actor, id uuid.UUID) (*Order, error) {
+	order, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.CustomerID != actor.UserID && !actor.IsAdmin() {
+		return nil, ErrForbidden
+	}
+
+	return order, nil
+}
+
+// List returns the orders matching the filter, newest first.
+func (s *Service) List(ctx context.Context, filter ListFilter) ([]Order, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Pay marks a pending order as paid.
+func (s *Service) Pay(ctx context.Context, id uuid.UUID) (*Order, error) {
+	return s.transition(ctx, id, func(tx products.ProductRepository, order *Order, now time.Time) error {
+		if order.Status != StatusPending {
+			return ErrInvalidTransition
+		}
+		order.Status = StatusPaid
+		order.PaidAt = &now
+		return nil
+	})
+}
+
+// Ship marks a paid order as shipped.
+func (s *Service) Ship(ctx context.Context, id uuid.UUID) (*Order, error) {
+	return s.transition(ctx, id, func(tx products.ProductRepository, order *Order, now time.Time) error {
+		if order.Status != StatusPaid {
+			return ErrInvalidTransition
+		}
+		order.Status = StatusShipped
+		order.ShippedAt = &now
+		return nil
+	})
+}
+
+// Cancel cancels an order and returns its stock. Customers can cancel their own
+// pending orders; admins can also cancel paid orders. Shipped orders cannot be cancelled.
+// Lines whose product has since been deleted are not restocked.
+func (s *Service) Cancel(ctx context.Context, actor products.Actor, id uuid.UUID) (*Order, error) {
+	return s.transition(ctx, id, func(tx products.ProductRepository, order *Order, now time.Time) error {
+		if order.CustomerID != actor.UserID && !actor.IsAdmin() {
+			return ErrForbidden
+		}
+
+		switch {
+		case order.Status == StatusPending:
+		case order.Status == StatusPaid && actor.IsAdmin():
+		case order.Status == StatusPaid:
+			return ErrForbidden
+		default:
+			return ErrInvalidTransition
+		}
+
+		adjustmentNote := "Cancelled order " + order.ID.String()
+		for _, line := range returnedLines(order.Items) {
+			_, _, err := products.ChangeStock(ctx, tx, line.ProductID, products.StockAdjustment{
+				Delta:  line.Quantity,
+				Reason: products.ReasonReturn,
+				Note:   adjustmentNote,
+			}, actor.UserID)
+			// Products deleted or archived since the order was placed are not restocked.
+			if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, products.ErrProductArchived) {
+				continue
+			}
+			if err != nil {
+				return &LineError{ProductID: line.ProductID, Err: err}
+			}
+		}
+
+		order.Status = StatusCancelled
+		order.CancelledAt = &now
+		return nil
+	})
+}
+
+// transition locks an order, lets change move it to its next status and saves it.
+func (s *Service) transition(ctx context.Context, id uuid.UUID, change func(tx products.ProductRepository, order *Order, now time.Time) error) (*Order, error) {
+	var order *Order
+	err := s.repo.Transaction(ctx, func(repo OrderRepository, stock products.ProductRepository) error {
+		var err error
+		order, err = repo.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := change(stock, order, s.now()); err != nil {
+			return err
+		}
+
+		return repo.UpdateStatus(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// mergeLines validates the requested lines and merges those for the same product.
+// The result is sorted by product ID so concurrent orders lock products in the same
+// order and cannot deadlock.
+func mergeLines(lines []LineInput) ([]LineInput, error) {
+	if len(lines) == 0 {
+		return nil, ErrEmptyOrder
+	}
+
+	quantities := make(map[uuid.UUID]int, len(lines))
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, &LineError{ProductID: line.ProductID, Err: ErrInvalidQuantity}
+		}
+		quantities[line.ProductID] += line.Quantity
+	}
+
+	merged := make([]LineInput, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, LineInput{ProductID: productID, Quantity: quantity})
+	}
+	sortLines(merged)
+
+	return merged, nil
+}
+
+// returnedLines returns the lines of an order whose product still exists, sorted by
+// product ID for the same lock order as mergeLines.
+func returnedLines(items []Item) []LineInput {
+	lines := make([]LineInput, 0, len(items))
+	for _, item := range items {
+		if item.ProductID != nil {
+			lines = append(lines, LineInput{ProductID: *item.ProductID, Quantity: item.Quantity})
+		}
+	}
+	sortLines(lines)
+	return lines
+}
+
+// sortLines sorts lines by product ID.
+func sortLines(lines []LineInput) {
+	slices.SortFunc(lines, func(a, b LineInput) int {
+		return bytes.Compare(a.ProductID[:], b.ProductID[:])
+	})
+}
@@ -0,0 +1,323 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockOrderRepository is a mock implementation of OrderRepository.
+// Its transactions hand out the mock itself and Stock as the product repository.
+type MockOrderRepository struct {
+	mock.Mock
+	Stock *MockStockRepository
+}
+
+func (m *MockOrderRepository) Create(ctx context.Context, order *Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) FindByID(ctx context.Context, id uuid.UUID) (*Order, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*Order, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) List(ctx context.Context, filter ListFilter) ([]Order, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, order *Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) Transaction(ctx context.Context, fn func(repo OrderRepository, stock products.ProductRepository) error) error {
+	return fn(m, m.Stock)
+}
+
+// MockStockRepository mocks the product repository methods used to change stock.
+// Any other method panics through the nil embedded interface.
+type MockStockRepository struct {
+	products.ProductRepository
+	mock.Mock
+}
+
+func (m *MockStockRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*products.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*products.Product), args.Error(1)
+}
+
+func (m *MockStockRepository) SumVariantStock(ctx context.Context, productID uuid.UUID) (int, int64, error) {
+	args := m.Called(ctx, productID)
+	return args.Int(0), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStockRepository) ListStockLevels(ctx context.Context, productID uuid.UUID) ([]products.StockLevel, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]products.StockLevel), args.Error(1)
+}
+
+func (m *MockStockRepository) SumReservedStock(ctx context.Context, productID, exceptUserID uuid.UUID) (int, error) {
+	args := m.Called(ctx, productID, exceptUserID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStockRepository) UpdateFields(ctx context.Context, product *products.Product, fields []string) error {
+	args := m.Called(ctx, product, fields)
+	return args.Error(0)
+}
+
+func (m *MockStockRepository) CreateMovement(ctx context.Context, movement *products.StockMovement) error {
+	args := m.Called(ctx, movement)
+	return args.Error(0)
+}
+
+func (m *MockStockRepository) CreateStockAlert(ctx context.Context, alert *products.StockAlert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func newMocks() (*MockOrderRepository, *MockStockRepository) {
+	stock := new(MockStockRepository)
+	stock.On("SumVariantStock", mock.Anything, mock.Anything).Return(0, int64(0), nil).Maybe()
+	stock.On("ListStockLevels", mock.Anything, mock.Anything).Return([]products.StockLevel(nil), nil).Maybe()
+	stock.On("SumReservedStock", mock.Anything, mock.Anything, mock.Anything).Return(0, nil).Maybe()
+	return &MockOrderRepository{Stock: stock}, stock
+}
+
+func TestOrderService_PlaceOrder(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+	mugID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	teaID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	// Test case 1: Stock is decremented, prices snapshotted and duplicate lines merged
+	repo, stock := newMocks()
+	service := NewService(repo)
+	mug := &products.Product{ID: mugID, Status: products.StatusActive, Name: "Mug", Price: decimal.RequireFromString("9.90"), Currency: "USD", Stock: 10}
+	tea := &products.Product{ID: teaID, Status: products.StatusActive, Name: "Tea", Price: decimal.RequireFromString("4.50"), Currency: "USD", Stock: 5}
+	stock.On("FindByIDForUpdate", ctx, mugID).Return(mug, nil).Once()
+	stock.On("FindByIDForUpdate", ctx, teaID).Return(tea, nil).Once()
+	stock.On("UpdateFields", ctx, mock.Anything, []string{"stock"}).Return(nil).Twice()
+	stock.On("CreateMovement", ctx, mock.MatchedBy(func(movement *products.StockMovement) bool {
+		return movement.Reason == products.ReasonSale && movement.Delta < 0
+	})).Return(nil).Twice()
+	repo.On("Create", ctx, mock.AnythingOfType("*orders.Order")).Return(nil).Once()
+	order, err := service.PlaceOrder(ctx, customerID, []LineInput{
+		{ProductID: teaID, Quantity: 2},
+		{ProductID: mugID, Quantity: 1},
+		{ProductID: mugID, Quantity: 2},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, order.Status)
+	assert.Equal(t, "USD", order.Currency)
+	assert.True(t, decimal.RequireFromString("38.70").Equal(order.Total))
+	assert.Len(t, order.Items, 2)
+	assert.Equal(t, 3, order.Items[0].Quantity)
+	assert.Equal(t, "Mug", order.Items[0].ProductName)
+	assert.Equal(t, 7, mug.Stock)
+	assert.Equal(t, 3, tea.Stock)
+	repo.AssertExpectations(t)
+	stock.AssertExpectations(t)
+
+	// Test case 2: A short line fails the whole order before it is created
+	repo, stock = newMocks()
+	service = NewService(repo)
+	mug = &products.Product{ID: mugID, Status: products.StatusActive, Name: "Mug", Price: decimal.RequireFromString("9.90"), Currency: "USD", Stock: 10}
+	tea = &products.Product{ID: teaID, Status: products.StatusActive, Name: "Tea", Price: decimal.RequireFromString("4.50"), Currency: "USD", Stock: 1}
+	stock.On("FindByIDForUpdate", ctx, mugID).Return(mug, nil).Once()
+	stock.On("FindByIDForUpdate", ctx, teaID).Return(tea, nil).Once()
+	stock.On("UpdateFields", ctx, mug, []string{"stock"}).Return(nil).Once()
+	stock.On("CreateMovement", ctx, mock.Anything).Return(nil).Once()
+	_, err = service.PlaceOrder(ctx, customerID, []LineInput{{ProductID: mugID, Quantity: 1}, {ProductID: teaID, Quantity: 2}})
+	var lineErr *LineError
+	assert.ErrorIs(t, err, products.ErrInsufficientStock)
+	assert.True(t, errors.As(err, &lineErr))
+	assert.Equal(t, teaID, lineErr.ProductID)
+	assert.Equal(t, 2, lineErr.Requested)
+	assert.Equal(t, 1, lineErr.Available)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	stock.AssertExpectations(t)
+
+	// Test case 3: Products priced in different currencies
+	repo, stock = newMocks()
+	service = NewService(repo)
+	stock.On("FindByIDForUpdate", ctx, mugID).Return(&products.Product{ID: mugID, Status: products.StatusActive, Currency: "USD", Stock: 10}, nil).Once()
+	stock.On("FindByIDForUpdate", ctx, teaID).Return(&products.Product{ID: teaID, Status: products.StatusActive, Currency: "EUR", Stock: 10}, nil).Once()
+	stock.On("UpdateFields", ctx, mock.Anything, []string{"stock"}).Return(nil).Twice()
+	stock.On("CreateMovement", ctx, mock.Anything).Return(nil).Twice()
+	_, err = service.PlaceOrder(ctx, customerID, []LineInput{{ProductID: mugID, Quantity: 1}, {ProductID: teaID, Quantity: 1}})
+	assert.ErrorIs(t, err, ErrMixedCurrencies)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	// Test case 4: Unknown product
+	repo, stock = newMocks()
+	service = NewService(repo)
+	stock.On("FindByIDForUpdate", ctx, mugID).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.PlaceOrder(ctx, customerID, []LineInput{{ProductID: mugID, Quantity: 1}})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.True(t, errors.As(err, &lineErr))
+
+	// Test case 5: Stock reserved in other customers' carts is not available
+	stock = new(MockStockRepository)
+	repo = &MockOrderRepository{Stock: stock}
+	service = NewService(repo)
+	stock.On("FindByIDForUpdate", ctx, mugID).Return(&products.Product{ID: mugID, Status: products.StatusActive, Currency: "USD", Stock: 5}, nil).Once()
+	stock.On("SumVariantStock", ctx, mugID).Return(0, int64(0), nil).Once()
+	stock.On("ListStockLevels", ctx, mugID).Return([]products.StockLevel(nil), nil).Once()
+	stock.On("SumReservedStock", ctx, mugID, customerID).Return(4, nil).Once()
+	_, err = service.PlaceOrder(ctx, customerID, []LineInput{{ProductID: mugID, Quantity: 2}})
+	assert.ErrorIs(t, err, products.ErrInsufficientStock)
+	assert.True(t, errors.As(err, &lineErr))
+	assert.Equal(t, 1, lineErr.Available)
+	stock.AssertExpectations(t)
+
+	// Test case 6: Empty order and invalid quantity
+	_, err = service.PlaceOrder(ctx, customerID, nil)
+	assert.ErrorIs(t, err, ErrEmptyOrder)
+	_, err = service.PlaceOrder(ctx, customerID, []LineInput{{ProductID: mugID, Quantity: 0}})
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+
+	// Test case 7: Drafts and archived products are not for sale
+	repo, stock = newMocks()
+	service = NewService(repo)
+	stock.On("FindByIDForUpdate", ctx, mugID).Return(&products.Product{ID: mugID, Status: products.StatusDraft, Currency: "USD", Stock: 5}, nil).Once()
+	_, err = service.PlaceOrder(ctx, customerID, []LineInput{{ProductID: mugID, Quantity: 1}})
+	assert.ErrorIs(t, err, products.ErrNotForSale)
+	assert.True(t, errors.As(err, &lineErr))
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestOrderService_Transitions(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+	customer := products.Actor{UserID: customerID, Role: "user"}
+	admin := products.Actor{UserID: uuid.New(), Role: "admin"}
+	productID := uuid.New()
+	newOrder := func(status string) *Order {
+		return &Order{ID: uuid.New(), CustomerID: customerID, Status: status, Items: []Item{
+			{ProductID: &productID, Quantity: 2},
+			{ProductID: nil, Quantity: 1},
+		}}
+	}
+
+	// Test case 1: Pending order is paid, then shipped
+	repo, _ := newMocks()
+	service := NewService(repo)
+	order := newOrder(StatusPending)
+	repo.On("FindByIDForUpdate", ctx, order.ID).Return(order, nil).Twice()
+	repo.On("UpdateStatus", ctx, order).Return(nil).Twice()
+	paid, err := service.Pay(ctx, order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPaid, paid.Status)
+	assert.NotNil(t, paid.PaidAt)
+	shipped, err := service.Ship(ctx, order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusShipped, shipped.Status)
+	assert.NotNil(t, shipped.ShippedAt)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Shipping an unpaid order
+	order = newOrder(StatusPending)
+	repo.On("FindByIDForUpdate", ctx, order.ID).Return(order, nil).Once()
+	_, err = service.Ship(ctx, order.ID)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+
+	// Test case 3: Customer cancels a pending order; stock of remaining products is returned
+	repo, stock := newMocks()
+	service = NewService(repo)
+	order = newOrder(StatusPending)
+	product := &products.Product{ID: productID, Status: products.StatusActive, Stock: 1}
+	repo.On("FindByIDForUpdate", ctx, order.ID).Return(order, nil).Once()
+	stock.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	stock.On("UpdateFields", ctx, product, []string{"stock"}).Return(nil).Once()
+	stock.On("CreateMovement", ctx, mock.MatchedBy(func(movement *products.StockMovement) bool {
+		return movement.Reason == products.ReasonReturn && movement.Delta == 2
+	})).Return(nil).Once()
+	repo.On("UpdateStatus", ctx, order).Return(nil).Once()
+	cancelled, err := service.Cancel(ctx, customer, order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCancelled, cancelled.Status)
+	assert.NotNil(t, cancelled.CancelledAt)
+	assert.Equal(t, 3, product.Stock)
+	repo.AssertExpectations(t)
+	stock.AssertExpectations(t)
+
+	// Archived products keep their stock when an order is cancelled
+	order = newOrder(StatusPending)
+	repo.On("FindByIDForUpdate", ctx, order.ID).Return(order, nil).Once()
+	stock.On("FindByIDForUpdate", ctx, productID).Return(&products.Product{ID: productID, Status: products.StatusArchived, Stock: 1}, nil).Once()
+	repo.On("UpdateStatus", ctx, order).Return(nil).Once()
+	cancelled, err = service.Cancel(ctx, customer, order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCancelled, cancelled.Status)
+	stock.AssertNumberOfCalls(t, "CreateMovement", 1)
+
+	// Test case 4: Only admins cancel paid orders
+	order = newOrder(StatusPaid)
+	repo.On("FindByIDForUpdate", ctx, order.ID).Return(order, nil).Once()
+	_, err = service.Cancel(ctx, customer, order.ID)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	repo.On("FindByIDForUpdate", ctx, order.ID).Return(order, nil).Once()
+	stock.On("FindByIDForUpdate", ctx, productID).Return(nil, gorm.ErrRecordNotFound).Once()
+	repo.On("UpdateStatus", ctx, order).Return(nil).Once()
+	cancelled, err = service.Cancel(ctx, admin, order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCancelled, cancelled.Status)
+
+	// Test case 5: Shipped orders and other customers' orders cannot be cancelled
+	order = newOrder(StatusShipped)
+	repo.On("FindByIDForUpdate", ctx, order.ID).Return(order, nil).Twice()
+	_, err = service.Cancel(ctx, admin, order.ID)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	_, err = service.Cancel(ctx, products.Actor{UserID: uuid.New(), Role: "user"}, order.ID)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertExpectations(t)
+}
+
+func TestOrderService_FindByID(t *testing.T) {
+	repo, _ := newMocks()
+	service := NewService(repo)
+
+	ctx := context.Background()
+	order := &Order{ID: uuid.New(), CustomerID: uuid.New()}
+	repo.On("FindByID", ctx, order.ID).Return(order, nil)
+
+	// Test case 1: The customer sees their order
+	found, err := service.FindByID(ctx, products.Actor{UserID: order.CustomerID, Role: "user"}, order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, order, found)
+
+	// Test case 2: Admins see any order
+	_, err = service.FindByID(ctx, products.Actor{UserID: uuid.New(), Role: "admin"}, order.ID)
+	assert.NoError(t, err)
+
+	// Test case 3: Other users do not
+	_, err = service.FindByID(ctx, products.Actor{UserID: uuid.New(), Role: "user"}, order.ID)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
@@ -0,0 +1,122 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-crud-api/pkg/notify"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// EventLowStock is the notification event raised when a product's stock falls to or below its reorder threshold.
+	EventLowStock = "product.low_stock"
+	// maxAlertAttempts is how many times delivery of an alert is tried before it is given up.
+	maxAlertAttempts = 5
+	// alertBatchSize bounds the number of alerts delivered in one run.
+	alertBatchSize = 100
+)
+
+// checkLowStock raises a low-stock alert when a stock change takes the product from above its
+// reorder threshold to at or below it. Staying below the threshold raises no further alerts.
+func checkLowStock(ctx context.Context, tx ProductRepository, product *Product, previousStock int) error {
+	threshold := product.ReorderThreshold
+	if threshold == nil || previousStock <= *threshold || product.Stock > *threshold {
+		return nil
+	}
+
+	return tx.CreateStockAlert(ctx, &StockAlert{
+		ProductID:   product.ID,
+		OwnerID:     product.OwnerID,
+		ProductName: product.Name,
+		Stock:       product.Stock,
+		Threshold:   *threshold,
+	})
+}
+
+// SetReorderThreshold sets the stock at or below which the owner is alerted, or disables alerts when threshold is nil.
+// The change is recorded as a revision made by actorID.
+// version is the version the caller expects the product to be at; zero skips the precondition.
+func (s *Service) SetReorderThreshold(ctx context.Context, id uuid.UUID, version int, threshold *int, actorID uuid.UUID) (*Product, error) {
+	product, err := findEditable(ctx, s.repo, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	product.ReorderThreshold = threshold
+	err = s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		if err := tx.UpdateFields(ctx, product, []string{"reorder_threshold"}); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, product, RevisionUpdate, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// ListLowStock returns the products at or below their reorder threshold, lowest stock first.
+// A nil ownerID lists the products of every owner.
+func (s *Service) ListLowStock(ctx context.Context, ownerID *uuid.UUID) ([]Product, error) {
+	return s.repo.ListLowStock(ctx, ownerID)
+}
+
+// StockAlertService delivers low-stock alerts to product owners.
+type StockAlertService struct {
+	repo     ProductRepository
+	notifier notify.Notifier
+}
+
+// NewStockAlertService creates a new stock alert service delivering through notifier.
+func NewStockAlertService(repo ProductRepository, notifier notify.Notifier) *StockAlertService {
+	return &StockAlertService{repo: repo, notifier: notifier}
+}
+
+// DeliverPending sends the alerts not delivered yet and returns how many were delivered.
+// Failed alerts are retried on later runs, up to maxAlertAttempts times; delivery is at least once.
+func (s *StockAlertService) DeliverPending(ctx context.Context) (int, error) {
+	alerts, err := s.repo.ListPendingStockAlerts(ctx, maxAlertAttempts, alertBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	var errs []error
+	for i := range alerts {
+		alert := &alerts[i]
+		alert.Attempts++
+
+		if err := s.notifier.Notify(ctx, lowStockMessage(alert)); err != nil {
+			alert.LastError = err.Error()
+			errs = append(errs, fmt.Errorf("stock alert %s: %w", alert.ID, err))
+		} else {
+			now := time.Now()
+			alert.DeliveredAt = &now
+			alert.LastError = ""
+			delivered++
+		}
+
+		if err := s.repo.UpdateStockAlert(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return delivered, errors.Join(errs...)
+}
+
+func lowStockMessage(alert *StockAlert) notify.Message {
+	return notify.Message{
+		Event:     EventLowStock,
+		Recipient: alert.OwnerEmail,
+		Subject:   fmt.Sprintf("Low stock: %s", alert.ProductName),
+		Body: fmt.Sprintf("The stock of %s (%s) is down to %d, at or below its reorder threshold of %d.",
+			alert.ProductName, alert.ProductID, alert.Stock, alert.Threshold),
+		Data:       alert,
+		OccurredAt: alert.CreatedAt,
+	}
+}
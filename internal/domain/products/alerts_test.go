@@ -0,0 +1,63 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-crud-api/pkg/notify"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordingNotifier records the messages it is given and fails for the recipients in fail.
+type recordingNotifier struct {
+	sent []notify.Message
+	fail map[string]bool
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, msg notify.Message) error {
+	n.sent = append(n.sent, msg)
+	if n.fail[msg.Recipient] {
+		return errors.New("mailbox unavailable")
+	}
+	return nil
+}
+
+func TestStockAlertService(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+
+	// Test case 1: Pending alerts are sent to their owner and marked delivered
+	repo := new(MockProductRepository)
+	notifier := &recordingNotifier{fail: map[string]bool{"down@example.com": true}}
+	service := NewStockAlertService(repo, notifier)
+
+	alerts := []StockAlert{
+		{ID: uuid.New(), ProductID: productID, ProductName: "Lamp", Stock: 2, Threshold: 5, OwnerEmail: "owner@example.com"},
+		{ID: uuid.New(), ProductID: productID, ProductName: "Desk", Stock: 0, Threshold: 1, OwnerEmail: "down@example.com", Attempts: 1},
+	}
+	repo.On("ListPendingStockAlerts", ctx, maxAlertAttempts, alertBatchSize).Return(alerts, nil)
+	repo.On("UpdateStockAlert", ctx, mock.AnythingOfType("*products.StockAlert")).Return(nil).Twice()
+
+	delivered, err := service.DeliverPending(ctx)
+	assert.Equal(t, 1, delivered)
+	assert.ErrorContains(t, err, "mailbox unavailable")
+
+	assert.Len(t, notifier.sent, 2)
+	assert.Equal(t, EventLowStock, notifier.sent[0].Event)
+	assert.Equal(t, "owner@example.com", notifier.sent[0].Recipient)
+	assert.Equal(t, "Low stock: Lamp", notifier.sent[0].Subject)
+	assert.Contains(t, notifier.sent[0].Body, "down to 2, at or below its reorder threshold of 5")
+
+	// Test case 2: A failed alert counts the attempt and keeps the error for the next run
+	updated := repo.Calls[1].Arguments.Get(1).(*StockAlert)
+	assert.NotNil(t, updated.DeliveredAt)
+	failed := repo.Calls[2].Arguments.Get(1).(*StockAlert)
+	assert.Nil(t, failed.DeliveredAt)
+	assert.Equal(t, 2, failed.Attempts)
+	assert.Equal(t, "mailbox unavailable", failed.LastError)
+	repo.AssertExpectations(t)
+}
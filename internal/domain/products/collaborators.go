@@ -0,0 +1,106 @@
+package products
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// canAccess reports whether the actor may access the product with role. Admins and the
+// owner can do anything; editors can also do what viewers can.
+func canAccess(ctx context.Context, repo ProductRepository, actor Actor, product *Product, role string) (bool, error) {
+	if actor.IsAdmin() || product.OwnerID == actor.UserID {
+		return true, nil
+	}
+
+	collaborator, err := repo.FindCollaborator(ctx, product.ID, actor.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return collaborator.Role == CollaboratorEditor || collaborator.Role == role, nil
+}
+
+// CanAccess reports whether the actor may access the product as a collaborator with role.
+func (s *Service) CanAccess(ctx context.Context, actor Actor, product *Product, role string) (bool, error) {
+	return canAccess(ctx, s.repo, actor, product, role)
+}
+
+// ListCollaborators returns the collaborators of a product. The owner, admins and
+// collaborators can list them.
+func (s *Service) ListCollaborators(ctx context.Context, actor Actor, productID uuid.UUID) ([]Collaborator, error) {
+	product, err := s.repo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := canAccess(ctx, s.repo, actor, product, CollaboratorViewer)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrForbidden
+	}
+
+	return s.repo.ListCollaborators(ctx, productID)
+}
+
+// SetCollaborator gives a user viewer or editor access to a product, or changes the access
+// they have. Only the owner and admins can manage collaborators.
+func (s *Service) SetCollaborator(ctx context.Context, actor Actor, productID, userID uuid.UUID, role string) (*Collaborator, error) {
+	if role != CollaboratorViewer && role != CollaboratorEditor {
+		return nil, ErrInvalidCollaboratorRole
+	}
+
+	product, err := s.repo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.OwnerID != actor.UserID && !actor.IsAdmin() {
+		return nil, ErrForbidden
+	}
+	if product.OwnerID == userID {
+		return nil, ErrCollaboratorIsOwner
+	}
+
+	collaborator := &Collaborator{
+		ProductID: productID,
+		UserID:    userID,
+		Role:      role,
+		GrantedBy: actorRef(actor.UserID),
+	}
+	if err := s.repo.SaveCollaborator(ctx, collaborator); err != nil {
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return nil, ErrCollaboratorUserNotFound
+		}
+		return nil, err
+	}
+
+	return collaborator, nil
+}
+
+// RemoveCollaborator revokes a user's access to a product. The owner and admins can
+// remove any collaborator; collaborators can remove themselves.
+func (s *Service) RemoveCollaborator(ctx context.Context, actor Actor, productID, userID uuid.UUID) error {
+	product, err := s.repo.FindByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product.OwnerID != actor.UserID && userID != actor.UserID && !actor.IsAdmin() {
+		return ErrForbidden
+	}
+
+	if err := s.repo.DeleteCollaborator(ctx, productID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCollaboratorNotFound
+		}
+		return err
+	}
+
+	return nil
+}
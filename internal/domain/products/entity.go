@@ -1,19 +1,435 @@
 package products
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"go-crud-api/internal/http/middleware"
+
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 // Product represents the product model.
+// ReorderThreshold is the stock at or below which the owner is alerted; nil disables alerts.
+// RatingAverage and RatingCount summarise the visible reviews and are maintained by the reviews domain.
+// Stock is the sum of the variants' stock when the product has variants, or of its warehouse stock levels when it has any.
+// Status follows the lifecycle draft -> active -> discontinued -> archived; see ForSale.
+// SKU and GTIN are optional and unique among the owner's products; GTIN holds a GTIN-8, UPC-A, EAN-13 or GTIN-14 barcode.
 type Product struct {
-	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	Name        string    `gorm:"type:varchar(120);not null" json:"name" validate:"required,min=2,max=120"`
-	Description string    `gorm:"type:text" json:"description"`
-	Price       float64   `gorm:"type:numeric(10,2);not null" json:"price" validate:"required,gte=0"`
-	Stock       int       `gorm:"type:integer;not null" json:"stock" validate:"required,gte=0"`
-	OwnerID     uuid.UUID `gorm:"type:uuid;not null" json:"owner_id"`
-	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID               uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name             string          `gorm:"type:varchar(120);not null" json:"name" validate:"required,min=2,max=120"`
+	Description      string          `gorm:"type:text" json:"description"`
+	SKU              *string         `gorm:"column:sku;type:varchar(64)" json:"sku" example:"LAMP-OAK-01"`
+	GTIN             *string         `gorm:"column:gtin;type:varchar(14)" json:"gtin" example:"4006381333931"`
+	Price            decimal.Decimal `gorm:"type:numeric(19,4);not null" json:"price" validate:"money" swaggertype:"string" example:"19.90"`
+	Currency         string          `gorm:"type:char(3);not null;default:USD" json:"currency" validate:"required,iso4217" example:"USD"`
+	Stock            int             `gorm:"type:integer;not null" json:"stock" validate:"required,gte=0"`
+	ReorderThreshold *int            `gorm:"type:integer" json:"reorder_threshold" example:"5"`
+	Status           string          `gorm:"type:product_status;not null;default:draft" json:"status" example:"active"`
+	RatingAverage    float64         `gorm:"->;type:numeric(3,2)" json:"rating_average" example:"4.5"`
+	RatingCount      int             `gorm:"->;type:integer" json:"rating_count" example:"12"`
+	OwnerID          uuid.UUID       `gorm:"type:uuid;not null" json:"owner_id"`
+	Owner            *Owner          `gorm:"-" json:"owner,omitempty"`
+	CategoryID       *uuid.UUID      `gorm:"type:uuid" json:"category_id"`
+	Tags             []Tag           `gorm:"many2many:product_tags" json:"tags,omitempty" swaggertype:"array,string" example:"summer,outdoor"`
+	Version          int             `gorm:"type:integer;not null;default:1" json:"version"`
+	CreatedAt        time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt  `gorm:"index" json:"deleted_at" swaggertype:"string" format:"date-time"`
+}
+
+// Owner is the public profile of a product's owner, embedded in products with expand=owner.
+type Owner struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name" example:"Jane Doe"`
+}
+
+// Product lifecycle statuses.
+const (
+	StatusDraft        = "draft"
+	StatusActive       = "active"
+	StatusDiscontinued = "discontinued"
+	StatusArchived     = "archived"
+)
+
+// Tag is a free-form label attached to products. Tags are created on first use.
+type Tag struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name      string    `gorm:"type:varchar(50);not null;unique"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// MarshalJSON renders a tag as its name.
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Name)
+}
+
+// ProductPatch holds the fields of a partial product update.
+// Nil fields were not supplied and are left untouched; an empty SKU or GTIN clears it.
+type ProductPatch struct {
+	Name        *string
+	Description *string
+	SKU         *string
+	GTIN        *string
+	Price       *decimal.Decimal
+	Currency    *string
+	Stock       *int
+}
+
+// ProductInput holds the editable fields of a product, as supplied on create and full update.
+// An empty SKU or GTIN leaves the product without one.
+type ProductInput struct {
+	Name        string
+	Description string
+	SKU         string
+	GTIN        string
+	Price       decimal.Decimal
+	Currency    string
+	Stock       int
+}
+
+// Stock movement reasons.
+const (
+	ReasonSale       = "sale"
+	ReasonRestock    = "restock"
+	ReasonCorrection = "correction"
+	ReasonReturn     = "return"
+)
+
+// StockMovement is an append-only ledger entry recording a change to a product's stock.
+// WarehouseID is the warehouse the change was made in; it is nil for products without stock
+// levels and for sales drawn from several warehouses.
+type StockMovement struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID   uuid.UUID  `gorm:"type:uuid;not null" json:"product_id"`
+	Delta       int        `gorm:"type:integer;not null" json:"delta"`
+	Reason      string     `gorm:"type:stock_movement_reason;not null" json:"reason"`
+	StockAfter  int        `gorm:"type:integer;not null" json:"stock_after"`
+	ActorID     *uuid.UUID `gorm:"type:uuid" json:"actor_id"`
+	WarehouseID *uuid.UUID `gorm:"type:uuid" json:"warehouse_id"`
+	Note        string     `gorm:"type:text" json:"note"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// StockAlert records that a product's stock fell to or below its reorder threshold.
+// Alerts are delivered to the owner after the stock change commits; OwnerEmail is read with pending alerts to address them.
+type StockAlert struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID   uuid.UUID  `gorm:"type:uuid;not null" json:"product_id"`
+	OwnerID     uuid.UUID  `gorm:"type:uuid;not null" json:"owner_id"`
+	ProductName string     `gorm:"type:varchar(120);not null" json:"product_name"`
+	Stock       int        `gorm:"type:integer;not null" json:"stock"`
+	Threshold   int        `gorm:"type:integer;not null" json:"threshold"`
+	Attempts    int        `gorm:"type:integer;not null;default:0" json:"-"`
+	LastError   string     `gorm:"type:text" json:"-"`
+	DeliveredAt *time.Time `json:"-"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	OwnerEmail  string     `gorm:"->;-:migration" json:"-"`
+}
+
+// StockAdjustment describes a relative change to a product's stock.
+// With RespectReservations set, a decrease cannot take stock held by other users' cart reservations.
+// WarehouseID names the warehouse a product with stock levels gains or loses the stock in;
+// without it, see allocateStock.
+type StockAdjustment struct {
+	Delta               int
+	Reason              string
+	Note                string
+	WarehouseID         *uuid.UUID
+	RespectReservations bool
+}
+
+// StockLevel is the quantity of a product held in a warehouse.
+// The warehouse's code and name are read with the level.
+type StockLevel struct {
+	ProductID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"product_id"`
+	WarehouseID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"warehouse_id"`
+	WarehouseCode string    `gorm:"->;-:migration" json:"warehouse_code" example:"SP-01"`
+	WarehouseName string    `gorm:"->;-:migration" json:"warehouse_name" example:"São Paulo"`
+	Quantity      int       `gorm:"type:integer;not null" json:"quantity"`
+	UpdatedAt     time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName overrides the table name used by StockLevel.
+func (StockLevel) TableName() string {
+	return "product_stock_levels"
+}
+
+// StockTransfer records stock moved between two warehouses. It is append-only; the
+// warehouses are nil once deleted.
+type StockTransfer struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID       uuid.UUID  `gorm:"type:uuid;not null" json:"product_id"`
+	FromWarehouseID *uuid.UUID `gorm:"type:uuid" json:"from_warehouse_id"`
+	ToWarehouseID   *uuid.UUID `gorm:"type:uuid" json:"to_warehouse_id"`
+	Quantity        int        `gorm:"type:integer;not null" json:"quantity"`
+	ActorID         *uuid.UUID `gorm:"type:uuid" json:"actor_id"`
+	Note            string     `gorm:"type:text" json:"note"`
+	CreatedAt       time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// StockTransferInput describes stock to move from one warehouse to another.
+type StockTransferInput struct {
+	FromWarehouseID uuid.UUID
+	ToWarehouseID   uuid.UUID
+	Quantity        int
+	Note            string
+}
+
+// VariantOptions maps option names to values, e.g. size=M and colour=red.
+type VariantOptions map[string]string
+
+// Value stores the options as a JSON object.
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(o)
+	return string(b), err
+}
+
+// Scan reads the options from a JSON object.
+func (o *VariantOptions) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, o)
+	case string:
+		return json.Unmarshal([]byte(v), o)
+	case nil:
+		*o = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into VariantOptions", value)
+	}
+}
+
+// Variant is a sellable version of a product, such as a size or colour.
+// The product's stock is the sum of its variants' stock.
+type Variant struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID uuid.UUID        `gorm:"type:uuid;not null" json:"product_id"`
+	SKU       string           `gorm:"column:sku;type:varchar(64);not null;unique" json:"sku" example:"TSHIRT-RED-M"`
+	Options   VariantOptions   `gorm:"type:jsonb;not null" json:"options" swaggertype:"object,string" example:"size:M,colour:red"`
+	Price     *decimal.Decimal `gorm:"type:numeric(19,4)" json:"price" swaggertype:"string" example:"21.90"`
+	Stock     int              `gorm:"type:integer;not null" json:"stock"`
+	Version   int              `gorm:"type:integer;not null;default:1" json:"version"`
+	CreatedAt time.Time        `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time        `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName overrides the table name used by Variant.
+func (Variant) TableName() string {
+	return "product_variants"
+}
+
+// VariantInput holds the editable fields of a variant.
+// A nil price means the variant is sold at the product's price.
+type VariantInput struct {
+	SKU     string
+	Options VariantOptions
+	Price   *decimal.Decimal
+	Stock   int
 }
+
+// Image is a picture of a product. Images are listed by position, starting at 0.
+// URL and ThumbnailURL are signed, expiring links filled in when images are returned.
+type Image struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID    uuid.UUID `gorm:"type:uuid;not null" json:"product_id"`
+	Position     int       `gorm:"type:integer;not null" json:"position"`
+	ContentType  string    `gorm:"type:varchar(50);not null" json:"content_type" example:"image/jpeg"`
+	Size         int64     `gorm:"type:bigint;not null" json:"size"`
+	Width        int       `gorm:"type:integer;not null" json:"width"`
+	Height       int       `gorm:"type:integer;not null" json:"height"`
+	BlobKey      string    `gorm:"type:varchar(255);not null" json:"-"`
+	ThumbnailKey string    `gorm:"type:varchar(255);not null" json:"-"`
+	URL          string    `gorm:"-" json:"url"`
+	ThumbnailURL string    `gorm:"-" json:"thumbnail_url"`
+	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName overrides the table name used by Image.
+func (Image) TableName() string {
+	return "product_images"
+}
+
+// Collaborator roles. A product's details, variants and images are readable by any authenticated
+// user, so roles only guard the rest: viewers can read its stock movements, revisions and price
+// schedules; editors can also change and delete it. Only the owner and admins manage collaborators.
+const (
+	CollaboratorViewer = "viewer"
+	CollaboratorEditor = "editor"
+)
+
+// Collaborator grants a user other than the owner access to a product.
+type Collaborator struct {
+	ProductID uuid.UUID  `gorm:"type:uuid;primaryKey" json:"product_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Role      string     `gorm:"type:product_collaborator_role;not null" json:"role" example:"editor"`
+	GrantedBy *uuid.UUID `gorm:"type:uuid" json:"granted_by"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName overrides the table name used by Collaborator.
+func (Collaborator) TableName() string {
+	return "product_collaborators"
+}
+
+// Revision actions.
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionDelete   = "delete"
+	RevisionRestore  = "restore"
+	RevisionRollback = "rollback"
+)
+
+// ProductSnapshot holds the editable state of a product, its status and its owner at a revision.
+// Status is empty and OwnerID nil in revisions recorded before they were.
+type ProductSnapshot struct {
+	Name             string          `json:"name"`
+	Description      string          `json:"description"`
+	SKU              *string         `json:"sku"`
+	GTIN             *string         `json:"gtin"`
+	Price            decimal.Decimal `json:"price" swaggertype:"string" example:"19.90"`
+	Currency         string          `json:"currency" example:"USD"`
+	Stock            int             `json:"stock"`
+	CategoryID       *uuid.UUID      `json:"category_id"`
+	Tags             []string        `json:"tags"`
+	ReorderThreshold *int            `json:"reorder_threshold"`
+	Status           string          `json:"status,omitempty" example:"active"`
+	OwnerID          *uuid.UUID      `json:"owner_id,omitempty"`
+	Version          int             `json:"version"`
+}
+
+// Revision is an immutable record of a product's state after a create, update or delete.
+// Revisions of a product are numbered from 1.
+type Revision struct {
+	ID        uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID uuid.UUID       `gorm:"type:uuid;not null" json:"product_id"`
+	Revision  int             `gorm:"type:integer;not null" json:"revision"`
+	Action    string          `gorm:"type:varchar(20);not null" json:"action" example:"update"`
+	Snapshot  ProductSnapshot `gorm:"type:jsonb;serializer:json;not null" json:"snapshot"`
+	ActorID   *uuid.UUID      `gorm:"type:uuid" json:"actor_id"`
+	CreatedAt time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName overrides the table name used by Revision.
+func (Revision) TableName() string {
+	return "product_revisions"
+}
+
+// FieldChange describes a field that differs between two revisions.
+type FieldChange struct {
+	Field string      `json:"field" example:"price"`
+	From  interface{} `json:"from" swaggertype:"string" example:"19.90"`
+	To    interface{} `json:"to" swaggertype:"string" example:"24.90"`
+}
+
+// Price schedule statuses. A schedule is pending until its start, active while its
+// price is in effect and completed once it has ended; a permanent change completes
+// as soon as it is applied. Schedules that could not be applied are skipped.
+const (
+	ScheduleStatusPending   = "pending"
+	ScheduleStatusActive    = "active"
+	ScheduleStatusCompleted = "completed"
+	ScheduleStatusCancelled = "cancelled"
+	ScheduleStatusSkipped   = "skipped"
+)
+
+// PriceSchedule is a price a product takes from EffectiveFrom until EffectiveTo.
+// When EffectiveTo is nil the change is permanent; otherwise the previous price is restored at its end.
+type PriceSchedule struct {
+	ID            uuid.UUID        `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID     uuid.UUID        `gorm:"type:uuid;not null" json:"product_id"`
+	Price         decimal.Decimal  `gorm:"type:numeric(19,4);not null" json:"price" swaggertype:"string" example:"14.90"`
+	Currency      string           `gorm:"type:char(3);not null" json:"currency" example:"USD"`
+	EffectiveFrom time.Time        `gorm:"not null" json:"effective_from"`
+	EffectiveTo   *time.Time       `json:"effective_to"`
+	Status        string           `gorm:"type:price_schedule_status;not null;default:pending" json:"status" example:"pending"`
+	PreviousPrice *decimal.Decimal `gorm:"type:numeric(19,4)" json:"previous_price,omitempty" swaggertype:"string" example:"19.90"`
+	CreatedBy     *uuid.UUID       `gorm:"type:uuid" json:"created_by"`
+	AppliedAt     *time.Time       `json:"applied_at,omitempty"`
+	EndedAt       *time.Time       `json:"ended_at,omitempty"`
+	CreatedAt     time.Time        `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time        `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// PriceScheduleInput holds the fields of a new price schedule.
+type PriceScheduleInput struct {
+	Price         decimal.Decimal
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time
+}
+
+// PriceChange is an append-only history entry recording a price a product took and when.
+type PriceChange struct {
+	ID         uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID  uuid.UUID       `gorm:"type:uuid;not null" json:"product_id"`
+	Price      decimal.Decimal `gorm:"type:numeric(19,4);not null" json:"price" swaggertype:"string" example:"19.90"`
+	Currency   string          `gorm:"type:char(3);not null" json:"currency" example:"USD"`
+	ScheduleID *uuid.UUID      `gorm:"type:uuid" json:"schedule_id"`
+	ActorID    *uuid.UUID      `gorm:"type:uuid" json:"actor_id"`
+	CreatedAt  time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// Actor is the authenticated user performing an operation, as read from the request by
+// middleware.ActorFromContext.
+type Actor = middleware.Actor
+
+// Batch operation kinds.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchOperation is a single create, update or delete within a batch.
+// ID and Version apply to updates and deletes; a zero Version skips the precondition.
+// Input applies to creates and updates.
+type BatchOperation struct {
+	Op      string
+	ID      uuid.UUID
+	Version int
+	Input   ProductInput
+}
+
+// BatchResult is the outcome of a single batch operation.
+type BatchResult struct {
+	Product *Product
+	Err     error
+}
+
+// ListFilter narrows the products returned by listing and export.
+// Zero values do not filter.
+type ListFilter struct {
+	OwnerID  *uuid.UUID
+	Query    string
+	Currency string
+	MinPrice *decimal.Decimal
+	MaxPrice *decimal.Decimal
+	InStock  bool
+	// Category is a category ID or slug; products in its subcategories match too.
+	Category string
+	// Tags lists tag names a product must all carry.
+	Tags []string
+	// Statuses only lists products with one of these statuses when set.
+	Statuses []string
+	// WarehouseID only lists products with stock in this warehouse when set.
+	WarehouseID *uuid.UUID
+	// Sort is SortNewest (the default) or SortRating.
+	Sort string
+}
+
+// Product list orders.
+const (
+	// SortNewest lists products in the order they were created.
+	SortNewest = "created_at"
+	// SortRating lists the best rated products first, breaking ties by number of reviews.
+	SortRating = "rating"
+)
@@ -0,0 +1,99 @@
+package products
+
+import "errors"
+
+var (
+	// ErrVersionMismatch is returned when a write targets a product version that is no longer current.
+	ErrVersionMismatch = errors.New("product version mismatch")
+	// ErrRetentionExpired is returned when restoring a product that has been in the trash for longer than the retention window.
+	ErrRetentionExpired = errors.New("product trash retention expired")
+	// ErrUnsupportedCurrency is returned when a price uses a currency that is not allowed.
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrInvalidPriceScale is returned when a price has more decimal places than its currency allows.
+	ErrInvalidPriceScale = errors.New("price has more decimal places than its currency allows")
+	// ErrInsufficientStock is returned when a stock change would take stock below zero or into stock reserved by others.
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrInvalidAdjustment is returned when the sign of a stock adjustment does not match its reason.
+	ErrInvalidAdjustment = errors.New("adjustment delta does not match its reason")
+	// ErrForbidden is returned when the actor is neither the product owner, a collaborator with enough access, nor an admin.
+	ErrForbidden = errors.New("not allowed to modify this product")
+	// ErrBatchRolledBack is returned when an atomic batch was rolled back because an operation failed.
+	ErrBatchRolledBack = errors.New("batch rolled back")
+	// ErrUnknownBatchOperation is returned for batch operations other than create, update and delete.
+	ErrUnknownBatchOperation = errors.New("unknown batch operation")
+	// ErrCategoryNotFound is returned when assigning a product to a category that does not exist.
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrInvalidTag is returned for empty or overlong tag names, or too many tags.
+	ErrInvalidTag = errors.New("tags must be 1 to 50 characters long, at most 20 per product")
+	// ErrDuplicateVariant is returned when another variant already uses the SKU, or the product already has a variant with the same options.
+	ErrDuplicateVariant = errors.New("a variant with this SKU or option combination already exists")
+	// ErrStockManagedByVariants is returned when editing the stock of a product that has variants; its stock is the sum of theirs.
+	ErrStockManagedByVariants = errors.New("stock of a product with variants is managed through its variants")
+	// ErrUnsupportedImage is returned for uploads that are not JPEG, PNG or GIF images.
+	ErrUnsupportedImage = errors.New("image must be a JPEG, PNG or GIF")
+	// ErrImageTooLarge is returned for images over the size or pixel limits.
+	ErrImageTooLarge = errors.New("image is too large")
+	// ErrTooManyImages is returned when uploading to a product that already has the maximum number of images.
+	ErrTooManyImages = errors.New("product already has the maximum number of images")
+	// ErrImageNotFound is returned when an image does not exist or belongs to another product.
+	ErrImageNotFound = errors.New("image not found")
+	// ErrInvalidImageOrder is returned when a reorder does not list each of the product's images exactly once.
+	ErrInvalidImageOrder = errors.New("order must list each image of the product exactly once")
+	// ErrRevisionNotFound is returned when a product has no revision with the requested number.
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrInvalidSchedule is returned when a price schedule does not start in the future or ends before it starts.
+	ErrInvalidSchedule = errors.New("effective_from must be in the future and before effective_to")
+	// ErrScheduleOverlap is returned when a price schedule overlaps another pending or active schedule of the product.
+	ErrScheduleOverlap = errors.New("price schedule overlaps another schedule of the product")
+	// ErrScheduleNotFound is returned when a price schedule does not exist or belongs to another product.
+	ErrScheduleNotFound = errors.New("price schedule not found")
+	// ErrInvalidTransition is returned when a product cannot move to the requested status from its current one.
+	ErrInvalidTransition = errors.New("product cannot move to this status")
+	// ErrProductArchived is returned when changing an archived product; archived products are read-only.
+	ErrProductArchived = errors.New("archived products are read-only")
+	// ErrProductDiscontinued is returned when increasing the stock of a discontinued product other than by a return.
+	ErrProductDiscontinued = errors.New("stock of a discontinued product cannot be increased")
+	// ErrNotForSale is returned when selling a product that is a draft or archived.
+	ErrNotForSale = errors.New("product is not for sale")
+	// ErrScheduleNotPending is returned when cancelling a price schedule that has already started or ended.
+	ErrScheduleNotPending = errors.New("only pending price schedules can be cancelled")
+	// ErrInvalidCollaboratorRole is returned for collaborator roles other than viewer and editor.
+	ErrInvalidCollaboratorRole = errors.New("collaborator role must be viewer or editor")
+	// ErrCollaboratorIsOwner is returned when adding the product's owner as a collaborator.
+	ErrCollaboratorIsOwner = errors.New("the owner cannot be a collaborator of their own product")
+	// ErrCollaboratorUserNotFound is returned when adding a user that does not exist as a collaborator.
+	ErrCollaboratorUserNotFound = errors.New("user not found")
+	// ErrCollaboratorNotFound is returned when removing a user that is not a collaborator of the product.
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	// ErrStockManagedByWarehouses is returned when editing the stock of a product with warehouse stock levels, or adding variants to it.
+	ErrStockManagedByWarehouses = errors.New("stock of a product with warehouse stock levels is managed through its warehouses")
+	// ErrNoStockLevels is returned when a stock change names a warehouse but the product's stock is not kept in warehouses.
+	ErrNoStockLevels = errors.New("product stock is not kept in warehouses")
+	// ErrWarehouseNotFound is returned when stocking a product in, or taking stock from, a warehouse that does not exist.
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	// ErrSameWarehouse is returned when moving stock from a warehouse to itself.
+	ErrSameWarehouse = errors.New("source and destination warehouses must be different")
+	// ErrStockLevelNotFound is returned when a product has no stock level in the warehouse.
+	ErrStockLevelNotFound = errors.New("stock level not found")
+	// ErrStockLevelNotEmpty is returned when removing a stock level that still holds stock.
+	ErrStockLevelNotEmpty = errors.New("stock level still holds stock")
+	// ErrDuplicateCode is returned when another of the owner's products already uses the SKU or barcode.
+	ErrDuplicateCode = errors.New("another product of the owner already uses this SKU or barcode")
+
+	// errDryRun rolls back the transaction of a dry run.
+	errDryRun = errors.New("dry run")
+)
+
+// InsufficientStockError reports the stock that was available to a change that needed more.
+// It matches ErrInsufficientStock.
+type InsufficientStockError struct {
+	Available int
+}
+
+func (e *InsufficientStockError) Error() string {
+	return ErrInsufficientStock.Error()
+}
+
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrInsufficientStock
+}
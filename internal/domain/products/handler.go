@@ -1,50 +1,290 @@
 package products
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"go-crud-api/internal/http/middleware"
+	"go-crud-api/pkg/barcode"
+	"go-crud-api/pkg/blobstore"
+	"go-crud-api/pkg/money"
 	"go-crud-api/pkg/web"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 // ProductHandler handles product-related requests.
 type ProductHandler struct {
 	service  *Service
+	images   *ImageService
 	validate *validator.Validate
 }
 
 // NewProductHandler creates a new ProductHandler.
-func NewProductHandler(service *Service) *ProductHandler {
+func NewProductHandler(service *Service, images *ImageService) *ProductHandler {
+	validate := validator.New()
+	money.RegisterValidations(validate)
+	barcode.RegisterValidations(validate)
+
 	return &ProductHandler{
 		service:  service,
-		validate: validator.New(),
+		images:   images,
+		validate: validate,
 	}
 }
 
 // CreateProductRequest is the request payload for creating a product.
+// Price is an exact decimal, sent as a string ("19.90") or a JSON number.
 type CreateProductRequest struct {
-	Name        string  `json:"name" validate:"required,min=2,max=120"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" validate:"required,gte=0"`
-	Stock       int     `json:"stock" validate:"required,gte=0"`
+	Name        string           `json:"name" validate:"required,min=2,max=120"`
+	Description string           `json:"description"`
+	SKU         string           `json:"sku" validate:"omitempty,max=64" example:"LAMP-OAK-01"`
+	GTIN        string           `json:"gtin" validate:"omitempty,gtin" example:"4006381333931"`
+	Price       *decimal.Decimal `json:"price" validate:"required,money" swaggertype:"string" example:"19.90"`
+	Currency    string           `json:"currency" validate:"omitempty,iso4217" example:"USD"`
+	Stock       int              `json:"stock" validate:"required,gte=0"`
 }
 
 // UpdateProductRequest is the request payload for updating a product.
+// Price is an exact decimal, sent as a string ("19.90") or a JSON number.
 type UpdateProductRequest struct {
-	Name        string  `json:"name" validate:"required,min=2,max=120"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" validate:"required,gte=0"`
-	Stock       int     `json:"stock" validate:"required,gte=0"`
+	Name        string           `json:"name" validate:"required,min=2,max=120"`
+	Description string           `json:"description"`
+	SKU         string           `json:"sku" validate:"omitempty,max=64" example:"LAMP-OAK-01"`
+	GTIN        string           `json:"gtin" validate:"omitempty,gtin" example:"4006381333931"`
+	Price       *decimal.Decimal `json:"price" validate:"required,money" swaggertype:"string" example:"19.90"`
+	Currency    string           `json:"currency" validate:"omitempty,iso4217" example:"USD"`
+	Stock       int              `json:"stock" validate:"required,gte=0"`
+}
+
+// PatchProductRequest is the request payload for partially updating a product.
+// Nil fields were absent from the patch document and are neither validated nor written.
+// A null description, sku or gtin arrives as an empty string and clears the field.
+type PatchProductRequest struct {
+	Name        *string          `json:"name" validate:"omitnil,min=2,max=120"`
+	Description *string          `json:"description"`
+	SKU         *string          `json:"sku" validate:"omitnil,max=64" example:"LAMP-OAK-01"`
+	GTIN        *string          `json:"gtin" validate:"omitnil,len=0|gtin" example:"4006381333931"`
+	Price       *decimal.Decimal `json:"price" validate:"omitnil,money" swaggertype:"string" example:"19.90"`
+	Currency    *string          `json:"currency" validate:"omitnil,iso4217" example:"USD"`
+	Stock       *int             `json:"stock" validate:"omitnil,gte=0"`
+}
+
+// StockAdjustmentRequest is the request payload for adjusting a product's stock.
+// WarehouseID is only accepted for products whose stock is kept in warehouses.
+type StockAdjustmentRequest struct {
+	Delta       int        `json:"delta" validate:"required" example:"-2"`
+	Reason      string     `json:"reason" validate:"required,oneof=sale restock correction return" example:"sale"`
+	Note        string     `json:"note" validate:"max=500"`
+	WarehouseID *uuid.UUID `json:"warehouse_id"`
+}
+
+// StockLevelRequest is the request payload for setting the stock of a product held in a warehouse.
+type StockLevelRequest struct {
+	Quantity *int   `json:"quantity" validate:"required,gte=0" example:"40"`
+	Note     string `json:"note" validate:"max=500" example:"Monthly count"`
+}
+
+// StockLevelResponse is the product after setting a stock level, with the level set.
+type StockLevelResponse struct {
+	Product *Product    `json:"product"`
+	Level   *StockLevel `json:"level"`
+}
+
+// StockTransferRequest is the request payload for moving stock between warehouses.
+type StockTransferRequest struct {
+	FromWarehouseID uuid.UUID `json:"from_warehouse_id" validate:"required"`
+	ToWarehouseID   uuid.UUID `json:"to_warehouse_id" validate:"required"`
+	Quantity        int       `json:"quantity" validate:"required,gt=0" example:"5"`
+	Note            string    `json:"note" validate:"max=500"`
+}
+
+// StockAdjustmentResponse is the product after a stock adjustment, with the ledger entry recording it.
+type StockAdjustmentResponse struct {
+	Product  *Product       `json:"product"`
+	Movement *StockMovement `json:"movement"`
+}
+
+// TrashedProductResponse is a deleted product together with the time it will be purged.
+type TrashedProductResponse struct {
+	Product
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// SetCategoryRequest is the request payload for assigning a product to a category.
+// A null category_id removes the product from its category.
+type SetCategoryRequest struct {
+	CategoryID *uuid.UUID `json:"category_id" swaggertype:"string" format:"uuid"`
+}
+
+// SetReorderThresholdRequest is the request payload for setting a product's reorder threshold.
+// A null reorder_threshold disables low-stock alerts for the product.
+type SetReorderThresholdRequest struct {
+	ReorderThreshold *int `json:"reorder_threshold" validate:"omitnil,gte=0" example:"5"`
+}
+
+// SetTagsRequest is the request payload for replacing a product's tags.
+type SetTagsRequest struct {
+	Tags []string `json:"tags" validate:"required,max=20,dive,required,max=50" example:"summer,outdoor"`
+}
+
+// PriceScheduleRequest is the request payload for scheduling a price change.
+// Without effective_to the change is permanent; otherwise the current price is restored at effective_to.
+type PriceScheduleRequest struct {
+	Price         *decimal.Decimal `json:"price" validate:"required,money" swaggertype:"string" example:"14.90"`
+	EffectiveFrom time.Time        `json:"effective_from" validate:"required" example:"2025-11-28T00:00:00Z"`
+	EffectiveTo   *time.Time       `json:"effective_to" example:"2025-12-01T00:00:00Z"`
+}
+
+// VariantRequest is the request payload for creating or updating a variant.
+// A missing price means the variant is sold at the product's price.
+type VariantRequest struct {
+	SKU     string            `json:"sku" validate:"required,max=64" example:"TSHIRT-RED-M"`
+	Options map[string]string `json:"options" validate:"required,min=1,max=10,dive,keys,required,max=50,endkeys,required,max=50" swaggertype:"object,string" example:"size:M,colour:red"`
+	Price   *decimal.Decimal  `json:"price" validate:"omitnil,money" swaggertype:"string" example:"21.90"`
+	Stock   int               `json:"stock" validate:"gte=0" example:"4"`
+}
+
+// ReorderImagesRequest is the request payload for reordering a product's images.
+type ReorderImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"image_ids" validate:"required" swaggertype:"array,string"`
+}
+
+// CollaboratorRequest is the request payload for sharing a product with a user.
+type CollaboratorRequest struct {
+	Role string `json:"role" validate:"required,oneof=viewer editor" example:"editor"`
+}
+
+// Batch modes.
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+)
+
+// maxBatchOperations caps the number of operations in a single batch request.
+const maxBatchOperations = 500
+
+// BatchProductRequest is the request payload for applying several product operations at once.
+// In atomic mode either every operation is applied or none is; in best_effort mode each
+// operation is applied independently.
+type BatchProductRequest struct {
+	Mode       string                  `json:"mode" validate:"required,oneof=atomic best_effort" example:"atomic"`
+	Operations []BatchOperationRequest `json:"operations" validate:"required,min=1,dive"`
+}
+
+// BatchOperationRequest is a single operation of a batch request.
+// Data holds a CreateProductRequest for creates and an UpdateProductRequest for updates.
+// Version is optional and, like If-Match, rejects the operation if the product has changed since.
+type BatchOperationRequest struct {
+	Op      string          `json:"op" validate:"required,oneof=create update delete" example:"update"`
+	ID      *uuid.UUID      `json:"id,omitempty" validate:"required_unless=Op create" swaggertype:"string" format:"uuid"`
+	Version int             `json:"version,omitempty" validate:"gte=0" example:"3"`
+	Data    json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+// BatchItemResult is the outcome of a single batch operation.
+// Status is the HTTP status the operation would have had as a single request.
+type BatchItemResult struct {
+	Index  int           `json:"index"`
+	Op     string        `json:"op"`
+	Status int           `json:"status" example:"200"`
+	Data   *Product      `json:"data,omitempty"`
+	Error  *web.ApiError `json:"error,omitempty"`
+}
+
+// BatchProductResponse is the result of a batch request.
+// Committed reports whether any change was kept.
+type BatchProductResponse struct {
+	Mode      string            `json:"mode"`
+	Committed bool              `json:"committed"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// maxImportSize caps the size of an uploaded import file.
+const maxImportSize = 10 << 20
+
+// maxImportRows caps the number of data rows in an import file.
+const maxImportRows = 5000
+
+// ImportRowResult is the outcome of a single imported row.
+// Action is "create" or "update", depending on whether the row matched an existing product;
+// Status is the HTTP status the row would have had as a single request.
+type ImportRowResult struct {
+	Line   int                `json:"line" example:"2"`
+	Action string             `json:"action,omitempty" example:"create"`
+	Status int                `json:"status" example:"201"`
+	Data   *Product           `json:"data,omitempty"`
+	Errors []ImportFieldError `json:"errors,omitempty"`
+}
+
+// ImportProductsResponse is the row-by-row report of a CSV import.
+type ImportProductsResponse struct {
+	DryRun    bool              `json:"dry_run"`
+	Mode      string            `json:"mode"`
+	Committed bool              `json:"committed"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+// nonNullablePatchFields lists the fields that a merge patch may not set to null.
+var nonNullablePatchFields = []string{"name", "price", "currency", "stock"}
+
+// decodePatchProductRequest builds a PatchProductRequest from a merge patch document.
+func decodePatchProductRequest(fields map[string]json.RawMessage) (PatchProductRequest, error) {
+	var req PatchProductRequest
+
+	for _, name := range nonNullablePatchFields {
+		if raw, ok := fields[name]; ok && string(raw) == "null" {
+			return req, fmt.Errorf("field %q cannot be null", name)
+		}
+	}
+
+	// A null description, SKU or barcode clears it.
+	for _, name := range []string{"description", "sku", "gtin"} {
+		if raw, ok := fields[name]; ok && string(raw) == "null" {
+			fields[name] = json.RawMessage(`""`)
+		}
+	}
+
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return req, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return req, err
+	}
+
+	return req, nil
 }
 
 // CreateProduct handles product creation.
 // @Summary Create a new product
-// @Description Create a new product with name, description, price, and stock
+// @Description Create a new product with name, description, price, currency, and stock, and optionally a SKU and a GTIN-8, UPC-A, EAN-13 or GTIN-14 barcode, each unique among the owner's products. Currency defaults to DEFAULT_CURRENCY and must be one of ALLOWED_CURRENCIES.
 // @Tags Products
 // @Security BearerAuth
 // @Accept json
@@ -53,6 +293,7 @@ type UpdateProductRequest struct {
 // @Success 201 {object} web.Response{data=Product} "Product created successfully"
 // @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
 // @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 409 {object} web.Response{error=web.ApiError} "SKU or barcode already used by another of the owner's products"
 // @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
 // @Router /v1/products [post]
 func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
@@ -67,30 +308,43 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ownerID, ok := r.Context().Value(middleware.ContextKeyUserID).(uuid.UUID)
+	actor, ok := middleware.ActorFromContext(w, r)
 	if !ok {
-		web.RespondWithError(w, "unauthorized", "User ID not found in context", http.StatusUnauthorized)
 		return
 	}
 
-	product, err := h.service.Create(r.Context(), req.Name, req.Description, req.Price, req.Stock, ownerID)
+	input := ProductInput{
+		Name:        req.Name,
+		Description: req.Description,
+		SKU:         req.SKU,
+		GTIN:        req.GTIN,
+		Price:       *req.Price,
+		Currency:    req.Currency,
+		Stock:       req.Stock,
+	}
+
+	product, err := h.service.Create(r.Context(), input, actor.UserID)
 	if err != nil {
-		web.RespondWithError(w, "internal_error", "Could not create product", http.StatusInternalServerError)
+		respondWithWriteError(w, r, err, "Could not create product")
 		return
 	}
 
+	web.SetETag(w, product.Version)
 	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: product})
 }
 
 // GetProductByID handles fetching a product by ID.
 // @Summary Get product by ID
-// @Description Get product details by its ID
+// @Description Get product details by its ID. Any authenticated user can read any product.
 // @Tags Products
 // @Security BearerAuth
 // @Produce json
 // @Param productID path string true "Product ID"
+// @Param fields query string false "Comma-separated fields to return; id is always included" example(name,price)
+// @Param expand query string false "Related resources to embed" Enums(owner)
 // @Success 200 {object} web.Response{data=Product} "Product details"
-// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Header 200 {string} ETag "Current product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format, field or expansion"
 // @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
 // @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
 // @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
@@ -103,6 +357,12 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	fields, expandOwner, err := parseProductView(r)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	product, err := h.service.FindByID(r.Context(), id)
 	if err != nil {
 		// TODO: Differentiate between not found and other errors
@@ -110,42 +370,416 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: product})
+	if expandOwner {
+		expanded := []Product{*product}
+		if err := h.service.ExpandOwners(r.Context(), expanded); err != nil {
+			web.RespondWithServerError(w, r, err, "Could not fetch product owner")
+			return
+		}
+		product = &expanded[0]
+	}
+
+	web.SetETag(w, product.Version)
+	web.RespondWithFields(w, r, http.StatusOK, fields, product)
+}
+
+// GetProductBySKU handles fetching a product by its SKU.
+// @Summary Get product by SKU
+// @Description Get one of an owner's products by its SKU, compared case-insensitively. The owner defaults to the caller.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param sku path string true "Product SKU"
+// @Param owner_id query string false "Owner of the product; defaults to the caller" format(uuid)
+// @Param fields query string false "Comma-separated fields to return; id is always included" example(name,price)
+// @Param expand query string false "Related resources to embed" Enums(owner)
+// @Success 200 {object} web.Response{data=Product} "Product details"
+// @Header 200 {string} ETag "Current product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid owner ID, field or expansion"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/by-sku/{sku} [get]
+func (h *ProductHandler) GetProductBySKU(w http.ResponseWriter, r *http.Request) {
+	// chi matches the escaped path when it differs from the decoded one, so an
+	// SKU containing a slash arrives still escaped.
+	sku := chi.URLParam(r, "sku")
+	if r.URL.RawPath != "" {
+		unescaped, err := url.PathUnescape(sku)
+		if err != nil {
+			web.RespondWithError(w, "bad_request", "Invalid SKU", http.StatusBadRequest)
+			return
+		}
+		sku = unescaped
+	}
+
+	h.lookupProduct(w, r, func(ownerID uuid.UUID) (*Product, error) {
+		return h.service.FindBySKU(r.Context(), ownerID, sku)
+	})
+}
+
+// GetProductByBarcode handles fetching a product by its barcode.
+// @Summary Get product by barcode
+// @Description Get one of an owner's products by its GTIN-8, UPC-A, EAN-13 or GTIN-14 barcode. Codes are compared as GTIN-14, so a UPC-A code finds a product stored with its EAN-13 form. The owner defaults to the caller.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param code path string true "Barcode digits" example(4006381333931)
+// @Param owner_id query string false "Owner of the product; defaults to the caller" format(uuid)
+// @Param fields query string false "Comma-separated fields to return; id is always included" example(name,price)
+// @Param expand query string false "Related resources to embed" Enums(owner)
+// @Success 200 {object} web.Response{data=Product} "Product details"
+// @Header 200 {string} ETag "Current product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid barcode, owner ID, field or expansion"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/by-barcode/{code} [get]
+func (h *ProductHandler) GetProductByBarcode(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+	if !barcode.Valid(code) {
+		web.RespondWithError(w, "bad_request", "Invalid barcode: expected 8, 12, 13 or 14 digits with a valid check digit", http.StatusBadRequest)
+		return
+	}
+
+	h.lookupProduct(w, r, func(ownerID uuid.UUID) (*Product, error) {
+		return h.service.FindByBarcode(r.Context(), ownerID, code)
+	})
+}
+
+// lookupProduct responds with the product find returns for the owner named by the
+// owner_id query parameter, or for the caller when it is absent.
+func (h *ProductHandler) lookupProduct(w http.ResponseWriter, r *http.Request, find func(ownerID uuid.UUID) (*Product, error)) {
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+	ownerID := actor.UserID
+	if raw := r.URL.Query().Get("owner_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			web.RespondWithError(w, "bad_request", "Invalid owner ID format", http.StatusBadRequest)
+			return
+		}
+		ownerID = id
+	}
+
+	fields, expandOwner, err := parseProductView(r)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	product, err := find(ownerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		web.RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch product")
+		return
+	}
+
+	if expandOwner {
+		expanded := []Product{*product}
+		if err := h.service.ExpandOwners(r.Context(), expanded); err != nil {
+			web.RespondWithServerError(w, r, err, "Could not fetch product owner")
+			return
+		}
+		product = &expanded[0]
+	}
+
+	web.SetETag(w, product.Version)
+	web.RespondWithFields(w, r, http.StatusOK, fields, product)
 }
 
 // ListProducts handles fetching all products.
 // @Summary Get all products
-// @Description Get a list of all products
+// @Description Get a list of all products of every owner, optionally filtered. Any authenticated user can list them.
 // @Tags Products
 // @Security BearerAuth
 // @Produce json
+// @Param owner_id query string false "Only products of this owner" format(uuid)
+// @Param q query string false "Case-insensitive search in the product name"
+// @Param currency query string false "Only products priced in this currency"
+// @Param min_price query string false "Minimum price"
+// @Param max_price query string false "Maximum price"
+// @Param in_stock query bool false "Only products with stock"
+// @Param warehouse_id query string false "Only products with stock in this warehouse" format(uuid)
+// @Param category query string false "Category ID or slug; subcategories included"
+// @Param tags query string false "Comma-separated tags the products must all carry"
+// @Param status query string false "Only products with this status" Enums(draft, active, discontinued, archived)
+// @Param sort query string false "Order: created_at (default) or rating, best rated first" Enums(created_at, rating)
+// @Param fields query string false "Comma-separated fields to return; id is always included" example(name,price)
+// @Param expand query string false "Related resources to embed" Enums(owner)
 // @Success 200 {object} web.Response{data=[]Product} "List of products"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid filter, field or expansion"
 // @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
 // @Router /v1/products [get]
 func (h *ProductHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
-	products, err := h.service.List(r.Context())
+	filter, err := parseListFilter(r)
 	if err != nil {
-		web.RespondWithError(w, "internal_error", "Could not fetch products", http.StatusInternalServerError)
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: products})
+	fields, expandOwner, err := parseProductView(r)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	products, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch products")
+		return
+	}
+
+	if expandOwner {
+		if err := h.service.ExpandOwners(r.Context(), products); err != nil {
+			web.RespondWithServerError(w, r, err, "Could not fetch product owners")
+			return
+		}
+	}
+
+	web.RespondWithFields(w, r, http.StatusOK, fields, products)
+}
+
+// productFields are the fields of a product that can be requested with the fields query parameter.
+var productFields = web.JSONFieldNames(Product{})
+
+// parseProductView reads the fields and expand query parameters of product reads.
+// It reports whether the owner should be embedded.
+func parseProductView(r *http.Request) (web.Fields, bool, error) {
+	fields, err := web.ParseFields(r, productFields)
+	if err != nil {
+		return nil, false, err
+	}
+
+	expand, err := web.ParseExpand(r, "owner")
+	if err != nil {
+		return nil, false, err
+	}
+
+	return fields, expand["owner"], nil
+}
+
+// Export formats.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// exportColumns is the header of CSV exports. The leading columns match the import format.
+var exportColumns = []string{"id", "name", "description", "sku", "gtin", "price", "currency", "stock", "owner_id", "version", "created_at", "updated_at"}
+
+// stringValue returns the string s points to, or an empty string for nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ExportProducts handles streaming the catalogue as a file download.
+// @Summary Export products
+// @Description Stream all products matching the listing filters as CSV or newline-delimited JSON. Rows are read from a database cursor and written as they arrive.
+// @Tags Products
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "Export format" Enums(csv, ndjson) default(csv)
+// @Param owner_id query string false "Only products of this owner" format(uuid)
+// @Param q query string false "Case-insensitive search in the product name"
+// @Param currency query string false "Only products priced in this currency"
+// @Param min_price query string false "Minimum price"
+// @Param max_price query string false "Maximum price"
+// @Param in_stock query bool false "Only products with stock"
+// @Param warehouse_id query string false "Only products with stock in this warehouse" format(uuid)
+// @Param category query string false "Category ID or slug; subcategories included"
+// @Param tags query string false "Comma-separated tags the products must all carry"
+// @Param status query string false "Only products with this status" Enums(draft, active, discontinued, archived)
+// @Param sort query string false "Order: created_at (default) or rating, best rated first" Enums(created_at, rating)
+// @Success 200 {file} file "Product export"
+// @Header 200 {string} Content-Disposition "attachment; filename=products-20060102.csv"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid format or filter"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/export [get]
+func (h *ProductHandler) ExportProducts(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	if format != ExportFormatCSV && format != ExportFormatNDJSON {
+		web.RespondWithError(w, "bad_request", "format must be csv or ndjson", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseListFilter(r)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		csvWriter *csv.Writer
+		encoder   *json.Encoder
+		started   bool
+	)
+
+	// Headers are only sent once the query has succeeded, so a failing query still gets an error response.
+	start := func() error {
+		started = true
+		filename := fmt.Sprintf("products-%s.%s", time.Now().UTC().Format("20060102"), format)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+		if format == ExportFormatNDJSON {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			encoder = json.NewEncoder(w)
+			return nil
+		}
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		csvWriter = csv.NewWriter(w)
+		return csvWriter.Write(exportColumns)
+	}
+
+	err = h.service.Export(r.Context(), filter, func(product *Product) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		if encoder != nil {
+			return encoder.Encode(product)
+		}
+
+		// csv.Writer buffers rows and writes them out in chunks, keeping memory bounded.
+		return csvWriter.Write([]string{
+			product.ID.String(),
+			product.Name,
+			product.Description,
+			stringValue(product.SKU),
+			stringValue(product.GTIN),
+			product.Price.String(),
+			product.Currency,
+			strconv.Itoa(product.Stock),
+			product.OwnerID.String(),
+			strconv.Itoa(product.Version),
+			product.CreatedAt.UTC().Format(time.RFC3339),
+			product.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err == nil {
+			err = csvWriter.Error()
+		}
+	}
+
+	if err != nil {
+		if !started {
+			web.RespondWithServerError(w, r, err, "Could not export products")
+			return
+		}
+		// The response is already under way; the client sees a truncated file.
+		zerolog.Ctx(r.Context()).Error().Err(err).Str("format", format).Msg("Product export interrupted")
+	}
+}
+
+// parseListFilter reads the product list filters from the query string.
+func parseListFilter(r *http.Request) (ListFilter, error) {
+	query := r.URL.Query()
+	filter := ListFilter{
+		Query:    query.Get("q"),
+		Currency: strings.ToUpper(query.Get("currency")),
+		Category: query.Get("category"),
+	}
+
+	if raw := query.Get("owner_id"); raw != "" {
+		ownerID, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("invalid owner_id filter")
+		}
+		filter.OwnerID = &ownerID
+	}
+
+	if raw := query.Get("warehouse_id"); raw != "" {
+		warehouseID, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("invalid warehouse_id filter")
+		}
+		filter.WarehouseID = &warehouseID
+	}
+
+	for name, target := range map[string]**decimal.Decimal{"min_price": &filter.MinPrice, "max_price": &filter.MaxPrice} {
+		if raw := query.Get(name); raw != "" {
+			price, err := decimal.NewFromString(raw)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s filter", name)
+			}
+			*target = &price
+		}
+	}
+
+	if raw := query.Get("tags"); raw != "" {
+		tags, err := NormalizeTags(strings.Split(raw, ","))
+		if err != nil {
+			return filter, errors.New("invalid tags filter")
+		}
+		filter.Tags = tags
+	}
+
+	if raw := query.Get("in_stock"); raw != "" {
+		inStock, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, errors.New("invalid in_stock filter")
+		}
+		filter.InStock = inStock
+	}
+
+	switch status := query.Get("status"); status {
+	case "":
+	case StatusDraft, StatusActive, StatusDiscontinued, StatusArchived:
+		filter.Statuses = []string{status}
+	default:
+		return filter, errors.New("invalid status filter")
+	}
+
+	switch sort := query.Get("sort"); sort {
+	case "", SortNewest, SortRating:
+		filter.Sort = sort
+	default:
+		return filter, errors.New("invalid sort")
+	}
+
+	return filter, nil
 }
 
 // UpdateProduct handles updating an existing product.
 // @Summary Update an existing product
-// @Description Update product details by its ID. Only owner or admin can update.
+// @Description Update product details by its ID. Only the owner, editors or admins can update.
 // @Tags Products
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param productID path string true "Product ID"
+// @Param If-Match header string false "ETag of the product version being modified"
 // @Param product body UpdateProductRequest true "Product update data"
 // @Success 200 {object} web.Response{data=Product} "Product updated successfully"
+// @Header 200 {string} ETag "New product version"
 // @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
 // @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
-// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner or admin)"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
 // @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "SKU or barcode already used by another of the owner's products"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product was modified since the given ETag"
 // @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
 // @Router /v1/products/{productID} [put]
 func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
@@ -167,15 +801,82 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ownerID, ok := r.Context().Value(middleware.ContextKeyUserID).(uuid.UUID)
+	actor, ok := middleware.ActorFromContext(w, r)
 	if !ok {
-		web.RespondWithError(w, "unauthorized", "User ID not found in context", http.StatusUnauthorized)
 		return
 	}
 
-	userRole, ok := r.Context().Value(middleware.ContextKeyRole).(string)
+	// Check ownership or admin role
+	product, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.checkAccess(w, r, actor, product, CollaboratorEditor, "You do not have permission to update this product") {
+		return
+	}
+
+	if !web.CheckIfMatch(r, product.Version) {
+		web.RespondWithError(w, "precondition_failed", "Product has been modified since it was last read", http.StatusPreconditionFailed)
+		return
+	}
+
+	input := ProductInput{
+		Name:        req.Name,
+		Description: req.Description,
+		SKU:         req.SKU,
+		GTIN:        req.GTIN,
+		Price:       *req.Price,
+		Currency:    req.Currency,
+		Stock:       req.Stock,
+	}
+
+	updatedProduct, err := h.service.Update(r.Context(), id, product.Version, input, actor.UserID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update product")
+		return
+	}
+
+	web.SetETag(w, updatedProduct.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: updatedProduct})
+}
+
+// SetProductCategory handles assigning a product to a category.
+// @Summary Set product category
+// @Description Assign a product to a category, or remove it from its category with a null category_id. Only the owner, editors or admins can change it.
+// @Tags Products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param If-Match header string false "ETag of the product version being modified"
+// @Param category body SetCategoryRequest true "Category assignment"
+// @Success 200 {object} web.Response{data=Product} "Category assigned successfully"
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product version does not match If-Match"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/category [put]
+func (h *ProductHandler) SetProductCategory(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "productID")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req SetCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
 	if !ok {
-		web.RespondWithError(w, "unauthorized", "User role not found in context", http.StatusUnauthorized)
 		return
 	}
 
@@ -186,35 +887,45 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if product.OwnerID != ownerID && userRole != "admin" {
-		web.RespondWithError(w, "forbidden", "You do not have permission to update this product", http.StatusForbidden)
+	if !h.checkAccess(w, r, actor, product, CollaboratorEditor, "You do not have permission to update this product") {
+		return
+	}
+
+	if !web.CheckIfMatch(r, product.Version) {
+		web.RespondWithError(w, "precondition_failed", "Product has been modified since it was last read", http.StatusPreconditionFailed)
 		return
 	}
 
-	updatedProduct, err := h.service.Update(r.Context(), id, req.Name, req.Description, req.Price, req.Stock)
+	updatedProduct, err := h.service.SetCategory(r.Context(), id, product.Version, req.CategoryID, actor.UserID)
 	if err != nil {
-		web.RespondWithError(w, "internal_error", "Could not update product", http.StatusInternalServerError)
+		respondWithWriteError(w, r, err, "Could not update product")
 		return
 	}
 
+	web.SetETag(w, updatedProduct.Version)
 	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: updatedProduct})
 }
 
-// DeleteProduct handles deleting a product.
-// @Summary Delete a product
-// @Description Delete a product by its ID. Only owner or admin can delete.
+// SetProductTags handles replacing the tags of a product.
+// @Summary Set product tags
+// @Description Replace the tags of a product. Tags are free-form, lowercased and created on first use; an empty list removes all tags. Only the owner, editors or admins can change it.
 // @Tags Products
 // @Security BearerAuth
+// @Accept json
 // @Produce json
 // @Param productID path string true "Product ID"
-// @Success 204 "Product deleted successfully"
-// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Param If-Match header string false "ETag of the product version being modified"
+// @Param tags body SetTagsRequest true "Product tags"
+// @Success 200 {object} web.Response{data=Product} "Tags replaced successfully"
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
 // @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
-// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner or admin)"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
 // @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product version does not match If-Match"
 // @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
-// @Router /v1/products/{productID} [delete]
-func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+// @Router /v1/products/{productID}/tags [put]
+func (h *ProductHandler) SetProductTags(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "productID")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -222,15 +933,19 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ownerID, ok := r.Context().Value(middleware.ContextKeyUserID).(uuid.UUID)
-	if !ok {
-		web.RespondWithError(w, "unauthorized", "User ID not found in context", http.StatusUnauthorized)
+	var req SetTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
 		return
 	}
 
-	userRole, ok := r.Context().Value(middleware.ContextKeyRole).(string)
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
 	if !ok {
-		web.RespondWithError(w, "unauthorized", "User role not found in context", http.StatusUnauthorized)
 		return
 	}
 
@@ -241,15 +956,2297 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if product.OwnerID != ownerID && userRole != "admin" {
-		web.RespondWithError(w, "forbidden", "You do not have permission to delete this product", http.StatusForbidden)
+	if !h.checkAccess(w, r, actor, product, CollaboratorEditor, "You do not have permission to update this product") {
 		return
 	}
 
-	if err := h.service.Delete(r.Context(), id); err != nil {
-		web.RespondWithError(w, "internal_error", "Could not delete product", http.StatusInternalServerError)
+	if !web.CheckIfMatch(r, product.Version) {
+		web.RespondWithError(w, "precondition_failed", "Product has been modified since it was last read", http.StatusPreconditionFailed)
 		return
 	}
 
-	web.RespondWithJSON(w, http.StatusNoContent, nil) // No content for successful delete
-}
\ No newline at end of file
+	updatedProduct, err := h.service.SetTags(r.Context(), id, product.Version, req.Tags, actor.UserID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update product")
+		return
+	}
+
+	web.SetETag(w, updatedProduct.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: updatedProduct})
+}
+
+// PatchProduct handles partially updating an existing product.
+// @Summary Partially update a product
+// @Description Apply a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) to a product. Only the supplied fields are validated and written. Only the owner, editors or admins can update.
+// @Tags Products
+// @Security BearerAuth
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param If-Match header string false "ETag of the product version being modified"
+// @Param product body PatchProductRequest true "Merge patch document, or an array of JSON Patch operations"
+// @Success 200 {object} web.Response{data=Product} "Product updated successfully"
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "JSON Patch test operation failed, or SKU or barcode already in use"
+// @Failure 415 {object} web.Response{error=web.ApiError} "Unsupported patch content type"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product was modified since the given ETag"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID} [patch]
+func (h *ProductHandler) PatchProduct(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "productID")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	// Check ownership or admin role
+	product, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.checkAccess(w, r, actor, product, CollaboratorEditor, "You do not have permission to update this product") {
+		return
+	}
+
+	if !web.CheckIfMatch(r, product.Version) {
+		web.RespondWithError(w, "precondition_failed", "Product has been modified since it was last read", http.StatusPreconditionFailed)
+		return
+	}
+
+	fields, err := web.DecodePatch(r, product)
+	if err != nil {
+		web.RespondWithPatchError(w, err)
+		return
+	}
+
+	req, err := decodePatchProductRequest(fields)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	patch := ProductPatch{
+		Name:        req.Name,
+		Description: req.Description,
+		SKU:         req.SKU,
+		GTIN:        req.GTIN,
+		Price:       req.Price,
+		Currency:    req.Currency,
+		Stock:       req.Stock,
+	}
+
+	updatedProduct, err := h.service.Patch(r.Context(), id, product.Version, patch, actor.UserID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update product")
+		return
+	}
+
+	web.SetETag(w, updatedProduct.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: updatedProduct})
+}
+
+// DeleteProduct handles deleting a product.
+// @Summary Delete a product
+// @Description Move a product to the trash by its ID. It can be restored until the retention window expires. Only the owner, editors or admins can delete.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param If-Match header string false "ETag of the product version being modified"
+// @Success 204 "Product deleted successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product was modified since the given ETag"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID} [delete]
+func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "productID")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	// Check ownership or admin role
+	product, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.checkAccess(w, r, actor, product, CollaboratorEditor, "You do not have permission to delete this product") {
+		return
+	}
+
+	if !web.CheckIfMatch(r, product.Version) {
+		web.RespondWithError(w, "precondition_failed", "Product has been modified since it was last read", http.StatusPreconditionFailed)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id, product.Version, actor.UserID); err != nil {
+		respondWithWriteError(w, r, err, "Could not delete product")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil) // No content for successful delete
+}
+
+// AdjustStock handles relative stock adjustments.
+// @Summary Adjust product stock
+// @Description Atomically apply a stock delta with a reason code and record it in the movement ledger. Stock never goes below zero. For a product kept in warehouses, the change is made in warehouse_id; without it, stock is added to the fullest warehouse and taken from the fullest warehouses first. Only the owner, editors or admins can adjust.
+// @Tags Products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param adjustment body StockAdjustmentRequest true "Stock adjustment"
+// @Success 201 {object} web.Response{data=StockAdjustmentResponse} "Stock adjusted successfully"
+// @Header 201 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or warehouse not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Insufficient stock"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/stock/adjustments [post]
+func (h *ProductHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "productID")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req StockAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	// Check ownership or admin role
+	product, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.checkAccess(w, r, actor, product, CollaboratorEditor, "You do not have permission to adjust stock of this product") {
+		return
+	}
+
+	adjustment := StockAdjustment{
+		Delta:       req.Delta,
+		Reason:      req.Reason,
+		Note:        req.Note,
+		WarehouseID: req.WarehouseID,
+	}
+
+	adjustedProduct, movement, err := h.service.AdjustStock(r.Context(), id, adjustment, actor.UserID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not adjust stock")
+		return
+	}
+
+	web.SetETag(w, adjustedProduct.Version)
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: StockAdjustmentResponse{Product: adjustedProduct, Movement: movement}})
+}
+
+// ListStockMovements handles fetching a product's stock movement history.
+// @Summary List stock movements
+// @Description Get the stock movement ledger of a product, most recent first. Only the owner, collaborators or admins can view.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]StockMovement} "Stock movements"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/stock/movements [get]
+func (h *ProductHandler) ListStockMovements(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "productID")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	// Check ownership or admin role
+	product, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+		return
+	}
+
+	if !h.checkAccess(w, r, actor, product, CollaboratorViewer, "You do not have permission to view stock of this product") {
+		return
+	}
+
+	movements, err := h.service.ListMovements(r.Context(), id)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch stock movements")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: movements})
+}
+
+// ListStockLevels handles fetching a product's stock per warehouse.
+// @Summary List stock levels
+// @Description Get the stock of a product held in each warehouse, fullest first. Only the owner, collaborators or admins can view.
+// @Tags Warehouses
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]StockLevel} "Stock levels"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/stock/levels [get]
+func (h *ProductHandler) ListStockLevels(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	if _, _, ok := h.authorizeProduct(w, r, productID, CollaboratorViewer, "You do not have permission to view stock of this product"); !ok {
+		return
+	}
+
+	levels, err := h.service.ListStockLevels(r.Context(), productID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not fetch stock levels")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: levels})
+}
+
+// SetStockLevel handles setting the stock of a product held in a warehouse.
+// @Summary Set a stock level
+// @Description Set the quantity of a product held in a warehouse, as after a stock count. From its first stock level on, the product's stock is the sum of its stock levels and can no longer be edited directly; a change to it is recorded in the movement ledger as a correction. Products with variants cannot be kept in warehouses. Only the owner, editors or admins can set it.
+// @Tags Warehouses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param warehouseID path string true "Warehouse ID"
+// @Param level body StockLevelRequest true "Quantity held"
+// @Success 200 {object} web.Response{data=StockLevelResponse} "Stock level set"
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or warehouse not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Product has variants or cannot gain stock in its status"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/stock/levels/{warehouseID} [put]
+func (h *ProductHandler) SetStockLevel(w http.ResponseWriter, r *http.Request) {
+	productID, warehouseID, ok := parseStockLevelPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req StockLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actorID, _, ok := h.authorizeProductWrite(w, r, productID)
+	if !ok {
+		return
+	}
+
+	product, level, err := h.service.SetStockLevel(r.Context(), productID, warehouseID, *req.Quantity, req.Note, actorID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not set stock level")
+		return
+	}
+
+	web.SetETag(w, product.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: StockLevelResponse{Product: product, Level: level}})
+}
+
+// RemoveStockLevel handles removing an empty stock level.
+// @Summary Remove a stock level
+// @Description Stop keeping a product in a warehouse. The stock level must be empty; move or count out its stock first. Once the last level is removed, the product's stock is edited directly again. Only the owner, editors or admins can remove it.
+// @Tags Warehouses
+// @Security BearerAuth
+// @Param productID path string true "Product ID"
+// @Param warehouseID path string true "Warehouse ID"
+// @Success 204 "Stock level removed"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or stock level not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Stock level still holds stock"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/stock/levels/{warehouseID} [delete]
+func (h *ProductHandler) RemoveStockLevel(w http.ResponseWriter, r *http.Request) {
+	productID, warehouseID, ok := parseStockLevelPath(w, r)
+	if !ok {
+		return
+	}
+
+	if _, _, ok := h.authorizeProductWrite(w, r, productID); !ok {
+		return
+	}
+
+	if err := h.service.RemoveStockLevel(r.Context(), productID, warehouseID); err != nil {
+		respondWithWriteError(w, r, err, "Could not remove stock level")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// TransferStock handles moving stock between warehouses.
+// @Summary Transfer stock between warehouses
+// @Description Atomically move stock of a product from one warehouse to another and record the transfer. The product's total stock does not change. Only the owner, editors or admins can transfer.
+// @Tags Warehouses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param transfer body StockTransferRequest true "Stock transfer"
+// @Success 201 {object} web.Response{data=StockTransfer} "Stock transferred"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request, validation error or product not kept in warehouses"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or warehouse not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Insufficient stock in the source warehouse"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/stock/transfers [post]
+func (h *ProductHandler) TransferStock(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req StockTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actorID, _, ok := h.authorizeProductWrite(w, r, productID)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.service.TransferStock(r.Context(), productID, StockTransferInput(req), actorID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not transfer stock")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: transfer})
+}
+
+// ListStockTransfers handles fetching the stock moved between warehouses for a product.
+// @Summary List stock transfers
+// @Description Get the transfers of a product's stock between warehouses, most recent first. Only the owner, collaborators or admins can view.
+// @Tags Warehouses
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]StockTransfer} "Stock transfers"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/stock/transfers [get]
+func (h *ProductHandler) ListStockTransfers(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	if _, _, ok := h.authorizeProduct(w, r, productID, CollaboratorViewer, "You do not have permission to view stock of this product"); !ok {
+		return
+	}
+
+	transfers, err := h.service.ListStockTransfers(r.Context(), productID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not fetch stock transfers")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: transfers})
+}
+
+// parseStockLevelPath reads the product and warehouse IDs from the URL, responding with 400 if either is malformed.
+func parseStockLevelPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	warehouseID, err := uuid.Parse(chi.URLParam(r, "warehouseID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid warehouse ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return productID, warehouseID, true
+}
+
+// SetReorderThreshold handles setting the low-stock threshold of a product.
+// @Summary Set reorder threshold
+// @Description Set the stock at or below which the owner is alerted, or disable alerts with a null reorder_threshold. An alert is raised when a stock change takes the product from above the threshold to at or below it. Only the owner, editors or admins can change it.
+// @Tags Products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param If-Match header string false "ETag of the product version being modified"
+// @Param threshold body SetReorderThresholdRequest true "Reorder threshold"
+// @Success 200 {object} web.Response{data=Product} "Threshold set successfully"
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product version does not match If-Match"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/stock/threshold [put]
+func (h *ProductHandler) SetReorderThreshold(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req SetReorderThresholdRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actorID, product, ok := h.authorizeProductWrite(w, r, id)
+	if !ok {
+		return
+	}
+
+	if !web.CheckIfMatch(r, product.Version) {
+		web.RespondWithError(w, "precondition_failed", "Product has been modified since it was last read", http.StatusPreconditionFailed)
+		return
+	}
+
+	product, err = h.service.SetReorderThreshold(r.Context(), id, product.Version, req.ReorderThreshold, actorID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update product")
+		return
+	}
+
+	web.SetETag(w, product.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: product})
+}
+
+// ActivateProduct handles putting a product on sale.
+// @Summary Activate product
+// @Description Put a draft product on sale, or bring back a discontinued one. Only the owner, editors or admins can activate it.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param If-Match header string false "ETag of the product version being modified"
+// @Success 200 {object} web.Response{data=Product} "Product activated"
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Product cannot be activated from its current status"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product version does not match If-Match"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/activate [post]
+func (h *ProductHandler) ActivateProduct(w http.ResponseWriter, r *http.Request) {
+	h.transitionProduct(w, r, h.service.Activate)
+}
+
+// DiscontinueProduct handles discontinuing a product.
+// @Summary Discontinue product
+// @Description Stop restocking an active product. It stays on sale until its stock runs out, but its stock can no longer be increased except by returns. Only the owner, editors or admins can discontinue it.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param If-Match header string false "ETag of the product version being modified"
+// @Success 200 {object} web.Response{data=Product} "Product discontinued"
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Product cannot be discontinued from its current status"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product version does not match If-Match"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/discontinue [post]
+func (h *ProductHandler) DiscontinueProduct(w http.ResponseWriter, r *http.Request) {
+	h.transitionProduct(w, r, h.service.Discontinue)
+}
+
+// ArchiveProduct handles archiving a product.
+// @Summary Archive product
+// @Description Retire a discontinued product. Archived products are kept for order history, are no longer sold and are read-only. Only the owner, editors or admins can archive it.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param If-Match header string false "ETag of the product version being modified"
+// @Success 200 {object} web.Response{data=Product} "Product archived"
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Product cannot be archived from its current status"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product version does not match If-Match"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/archive [post]
+func (h *ProductHandler) ArchiveProduct(w http.ResponseWriter, r *http.Request) {
+	h.transitionProduct(w, r, h.service.Archive)
+}
+
+// transitionProduct moves the product in the request path to another lifecycle status with transition.
+func (h *ProductHandler) transitionProduct(w http.ResponseWriter, r *http.Request, transition func(ctx context.Context, id uuid.UUID, version int, actorID uuid.UUID) (*Product, error)) {
+	id, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	actorID, product, ok := h.authorizeProductWrite(w, r, id)
+	if !ok {
+		return
+	}
+
+	if !web.CheckIfMatch(r, product.Version) {
+		web.RespondWithError(w, "precondition_failed", "Product has been modified since it was last read", http.StatusPreconditionFailed)
+		return
+	}
+
+	product, err = transition(r.Context(), id, product.Version, actorID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update product")
+		return
+	}
+
+	web.SetETag(w, product.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: product})
+}
+
+// ListPublicProducts handles listing the public catalogue.
+// @Summary List products on sale
+// @Description List the active and discontinued products without authentication, optionally filtered. Products are shown without their owner, stock level and other internal fields.
+// @Tags Catalogue
+// @Produce json
+// @Param q query string false "Case-insensitive search in the product name"
+// @Param currency query string false "Only products priced in this currency"
+// @Param min_price query string false "Minimum price"
+// @Param max_price query string false "Maximum price"
+// @Param in_stock query bool false "Only products with stock"
+// @Param category query string false "Category ID or slug; subcategories included"
+// @Param tags query string false "Comma-separated tags the products must all carry"
+// @Param sort query string false "Order: created_at (default) or rating, best rated first" Enums(created_at, rating)
+// @Param fields query string false "Comma-separated fields to return; id is always included" example(name,price)
+// @Success 200 {object} web.Response{data=[]PublicProduct} "Products on sale"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid filter or field"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /public/v1/products [get]
+func (h *ProductHandler) ListPublicProducts(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseListFilter(r)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fields, err := web.ParseFields(r, publicProductFields)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	products, err := h.service.ListPublic(r.Context(), filter)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch products")
+		return
+	}
+
+	views := make([]PublicProduct, len(products))
+	for i := range products {
+		views[i] = NewPublicProduct(&products[i])
+	}
+
+	web.RespondWithFields(w, r, http.StatusOK, fields, views)
+}
+
+// GetPublicProduct handles fetching a product on sale.
+// @Summary Get product on sale by ID
+// @Description Get an active or discontinued product without authentication. Drafts and archived products are not found.
+// @Tags Catalogue
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param fields query string false "Comma-separated fields to return; id is always included" example(name,price)
+// @Success 200 {object} web.Response{data=PublicProduct} "Product details"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format or field"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /public/v1/products/{productID} [get]
+func (h *ProductHandler) GetPublicProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	fields, err := web.ParseFields(r, publicProductFields)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	product, err := h.service.FindPublic(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			web.RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+			return
+		}
+		web.RespondWithServerError(w, r, err, "Could not fetch product")
+		return
+	}
+
+	web.RespondWithFields(w, r, http.StatusOK, fields, NewPublicProduct(product))
+}
+
+// ListLowStock handles the low-stock report.
+// @Summary List low-stock products
+// @Description List products whose stock is at or below their reorder threshold, lowest stock first. Users see their own products; admins see every owner's, optionally filtered by owner_id.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param owner_id query string false "Owner ID (admin only)"
+// @Success 200 {object} web.Response{data=[]Product} "Low-stock products"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid owner ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/low-stock [get]
+func (h *ProductHandler) ListLowStock(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	ownerID := &actor.UserID
+	if actor.IsAdmin() {
+		ownerID = nil
+		if ownerParam := r.URL.Query().Get("owner_id"); ownerParam != "" {
+			id, err := uuid.Parse(ownerParam)
+			if err != nil {
+				web.RespondWithError(w, "bad_request", "Invalid owner ID format", http.StatusBadRequest)
+				return
+			}
+			ownerID = &id
+		}
+	}
+
+	products, err := h.service.ListLowStock(r.Context(), ownerID)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch low-stock products")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: products})
+}
+
+// ListProductRevisions handles fetching a product's revision history.
+// @Summary List product revisions
+// @Description Get the revisions recorded on each create, update, delete, restore and rollback of a product, newest first, with a full snapshot, the acting user and the time. Products in the trash keep their history. Only the owner, collaborators or admins can view.
+// @Tags Revisions
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]Revision} "Revisions"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/revisions [get]
+func (h *ProductHandler) ListProductRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	if !h.authorizeRevisionAccess(w, r, id) {
+		return
+	}
+
+	revisions, err := h.service.ListRevisions(r.Context(), id)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch revisions")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: revisions})
+}
+
+// DiffProductRevisions handles comparing two revisions of a product.
+// @Summary Compare product revisions
+// @Description List the fields that changed between two revisions of a product. Only the owner, collaborators or admins can view.
+// @Tags Revisions
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param from query int true "Revision to compare from"
+// @Param to query int true "Revision to compare to"
+// @Success 200 {object} web.Response{data=[]FieldChange} "Changed fields"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID or revision numbers"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or revision not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/revisions/diff [get]
+func (h *ProductHandler) DiffProductRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	from, errFrom := strconv.Atoi(r.URL.Query().Get("from"))
+	to, errTo := strconv.Atoi(r.URL.Query().Get("to"))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		web.RespondWithError(w, "bad_request", "from and to must be revision numbers", http.StatusBadRequest)
+		return
+	}
+
+	if !h.authorizeRevisionAccess(w, r, id) {
+		return
+	}
+
+	changes, err := h.service.DiffRevisions(r.Context(), id, from, to)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not compare revisions")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: changes})
+}
+
+// RollbackProduct handles restoring a product to a previous revision.
+// @Summary Roll back a product
+// @Description Restore the name, description, SKU, barcode, price, currency, stock, category and tags of a product to their state at a revision. The rollback is recorded as a new revision. The stock of a product with variants is left as the sum of its variants, and a category deleted since is left unset. Only the owner, editors or admins can roll back.
+// @Tags Revisions
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param revision path int true "Revision number"
+// @Param If-Match header string false "ETag of the product version being modified"
+// @Success 200 {object} web.Response{data=Product} "Product rolled back successfully"
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID or revision, or price no longer valid"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or revision not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Another of the owner's products now uses the revision's SKU or barcode"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Product version does not match If-Match"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/revisions/{revision}/rollback [post]
+func (h *ProductHandler) RollbackProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil || revision < 1 {
+		web.RespondWithError(w, "bad_request", "Invalid revision number", http.StatusBadRequest)
+		return
+	}
+
+	actorID, product, ok := h.authorizeProductWrite(w, r, id)
+	if !ok {
+		return
+	}
+
+	if !web.CheckIfMatch(r, product.Version) {
+		web.RespondWithError(w, "precondition_failed", "Product has been modified since it was last read", http.StatusPreconditionFailed)
+		return
+	}
+
+	product, err = h.service.Rollback(r.Context(), id, product.Version, revision, actorID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not roll back product")
+		return
+	}
+
+	web.SetETag(w, product.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: product})
+}
+
+// authorizeRevisionAccess checks that the authenticated user owns the product, collaborates on it
+// or is an admin, responding with the matching error if not. Products in the trash are included.
+func (h *ProductHandler) authorizeRevisionAccess(w http.ResponseWriter, r *http.Request, productID uuid.UUID) bool {
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return false
+	}
+
+	product, err := h.service.FindByID(r.Context(), productID)
+	if err != nil {
+		product, err = h.service.FindDeletedByID(r.Context(), productID)
+	}
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+		return false
+	}
+
+	if !h.checkAccess(w, r, actor, product, CollaboratorViewer, "You do not have permission to view the history of this product") {
+		return false
+	}
+
+	return true
+}
+
+// ListPriceSchedules handles fetching the price schedules of a product.
+// @Summary List price schedules
+// @Description Get the scheduled price changes of a product ordered by start, including past, cancelled and skipped ones. Only the owner, collaborators or admins can view.
+// @Tags Prices
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]PriceSchedule} "Price schedules"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/prices/schedules [get]
+func (h *ProductHandler) ListPriceSchedules(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	if _, _, ok := h.authorizeProduct(w, r, productID, CollaboratorViewer, "You do not have permission to view the price schedules of this product"); !ok {
+		return
+	}
+
+	schedules, err := h.service.ListPriceSchedules(r.Context(), productID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not fetch price schedules")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: schedules})
+}
+
+// CreatePriceSchedule handles scheduling a price change.
+// @Summary Schedule a price change
+// @Description Schedule a price for a product from effective_from, in its current currency. With effective_to the previous price is restored when the schedule ends, unless the price was changed by hand in between; without it the change is permanent. Schedules are applied by a background job and must not overlap the product's pending or active schedules. Only the owner, editors or admins can schedule prices.
+// @Tags Prices
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param schedule body PriceScheduleRequest true "Price schedule"
+// @Success 201 {object} web.Response{data=PriceSchedule} "Price change scheduled"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Overlaps another schedule of the product"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/prices/schedules [post]
+func (h *ProductHandler) CreatePriceSchedule(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req PriceScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actorID, _, ok := h.authorizeProductWrite(w, r, productID)
+	if !ok {
+		return
+	}
+
+	input := PriceScheduleInput{Price: *req.Price, EffectiveFrom: req.EffectiveFrom, EffectiveTo: req.EffectiveTo}
+	schedule, err := h.service.SchedulePrice(r.Context(), productID, input, actorID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not schedule price change")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: schedule})
+}
+
+// CancelPriceSchedule handles cancelling a scheduled price change.
+// @Summary Cancel a price schedule
+// @Description Cancel a price schedule that has not started yet. The schedule is kept with status cancelled. Only the owner, editors or admins can cancel schedules.
+// @Tags Prices
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param scheduleID path string true "Schedule ID"
+// @Success 200 {object} web.Response{data=PriceSchedule} "Price schedule cancelled"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or schedule not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Schedule has already started or ended"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/prices/schedules/{scheduleID} [delete]
+func (h *ProductHandler) CancelPriceSchedule(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	scheduleID, err := uuid.Parse(chi.URLParam(r, "scheduleID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid schedule ID format", http.StatusBadRequest)
+		return
+	}
+
+	if _, _, ok := h.authorizeProductWrite(w, r, productID); !ok {
+		return
+	}
+
+	schedule, err := h.service.CancelPriceSchedule(r.Context(), productID, scheduleID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not cancel price schedule")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: schedule})
+}
+
+// ListPriceHistory handles fetching the price history of a product.
+// @Summary List price history
+// @Description Get every price a product has had, newest first, whether set on create, by an edit, a rollback or a price schedule starting or ending
+// @Tags Prices
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]PriceChange} "Price history"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/prices/history [get]
+func (h *ProductHandler) ListPriceHistory(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.service.ListPriceHistory(r.Context(), productID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not fetch price history")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: changes})
+}
+
+// ListCollaborators handles listing the users a product is shared with.
+// @Summary List product collaborators
+// @Description List the users a product is shared with and their role, oldest first. Only the owner, collaborators or admins can view.
+// @Tags Collaborators
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]Collaborator} "Collaborators"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/collaborators [get]
+func (h *ProductHandler) ListCollaborators(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	collaborators, err := h.service.ListCollaborators(r.Context(), actor, productID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not fetch collaborators")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: collaborators})
+}
+
+// SetCollaborator handles sharing a product with a user.
+// @Summary Share a product
+// @Description Give a user viewer or editor access to a product, or change the role they have. The product's details, variants and images are readable by any authenticated user; viewers can also read its stock movements, revisions and price schedules; editors can also change and delete the product. Only the owner or admins can manage collaborators.
+// @Tags Collaborators
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param userID path string true "User ID"
+// @Param collaborator body CollaboratorRequest true "Role"
+// @Success 200 {object} web.Response{data=Collaborator} "Collaborator saved"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request, validation error or the user is the owner"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or user not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/collaborators/{userID} [put]
+func (h *ProductHandler) SetCollaborator(w http.ResponseWriter, r *http.Request) {
+	productID, userID, ok := parseCollaboratorPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req CollaboratorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	collaborator, err := h.service.SetCollaborator(r.Context(), actor, productID, userID, req.Role)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not save collaborator")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: collaborator})
+}
+
+// RemoveCollaborator handles revoking a user's access to a product.
+// @Summary Stop sharing a product
+// @Description Revoke a user's access to a product. The owner or admins can remove any collaborator; collaborators can remove themselves.
+// @Tags Collaborators
+// @Security BearerAuth
+// @Param productID path string true "Product ID"
+// @Param userID path string true "User ID"
+// @Success 204 "Collaborator removed"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, the collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or collaborator not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/collaborators/{userID} [delete]
+func (h *ProductHandler) RemoveCollaborator(w http.ResponseWriter, r *http.Request) {
+	productID, userID, ok := parseCollaboratorPath(w, r)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveCollaborator(r.Context(), actor, productID, userID); err != nil {
+		respondWithWriteError(w, r, err, "Could not remove collaborator")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// parseCollaboratorPath reads the product and user IDs from the request path.
+// It writes a 400 response and returns false when either is malformed.
+func parseCollaboratorPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid user ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return productID, userID, true
+}
+
+// ListVariants handles fetching the variants of a product.
+// @Summary List product variants
+// @Description Get the variants of a product, ordered by SKU. Any authenticated user can read them.
+// @Tags Variants
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]Variant} "List of variants"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/variants [get]
+func (h *ProductHandler) ListVariants(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	variants, err := h.service.ListVariants(r.Context(), productID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not fetch variants")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: variants})
+}
+
+// GetVariant handles fetching a variant by its ID.
+// @Summary Get variant by ID
+// @Description Get a variant of a product
+// @Tags Variants
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param variantID path string true "Variant ID"
+// @Success 200 {object} web.Response{data=Variant} "Variant details"
+// @Header 200 {string} ETag "Current variant version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Variant not found"
+// @Router /v1/products/{productID}/variants/{variantID} [get]
+func (h *ProductHandler) GetVariant(w http.ResponseWriter, r *http.Request) {
+	productID, variantID, ok := parseVariantPath(w, r)
+	if !ok {
+		return
+	}
+
+	variant, err := h.service.FindVariant(r.Context(), productID, variantID)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Variant not found", http.StatusNotFound)
+		return
+	}
+
+	web.SetETag(w, variant.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: variant})
+}
+
+// CreateVariant handles adding a variant to a product.
+// @Summary Create a product variant
+// @Description Add a variant with a unique SKU, option values, an optional price override and its own stock. The product's stock becomes the sum of its variants' stock. Only the owner, editors or admins can add variants.
+// @Tags Variants
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param variant body VariantRequest true "Variant data"
+// @Success 201 {object} web.Response{data=Variant} "Variant created successfully"
+// @Header 201 {string} ETag "Variant version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "SKU or option combination already in use"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/variants [post]
+func (h *ProductHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	input, ok := h.decodeVariantRequest(w, r)
+	if !ok {
+		return
+	}
+
+	actorID, _, ok := h.authorizeProductWrite(w, r, productID)
+	if !ok {
+		return
+	}
+
+	variant, err := h.service.CreateVariant(r.Context(), productID, input, actorID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not create variant")
+		return
+	}
+
+	web.SetETag(w, variant.Version)
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: variant})
+}
+
+// UpdateVariant handles updating a variant.
+// @Summary Update a product variant
+// @Description Update a variant's SKU, options, price override and stock; the product's stock is recalculated. Only the owner, editors or admins can update variants.
+// @Tags Variants
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param variantID path string true "Variant ID"
+// @Param If-Match header string false "ETag of the variant version being modified"
+// @Param variant body VariantRequest true "Variant data"
+// @Success 200 {object} web.Response{data=Variant} "Variant updated successfully"
+// @Header 200 {string} ETag "New variant version"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or variant not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "SKU or option combination already in use"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Variant version does not match If-Match"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/variants/{variantID} [put]
+func (h *ProductHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	productID, variantID, ok := parseVariantPath(w, r)
+	if !ok {
+		return
+	}
+
+	input, ok := h.decodeVariantRequest(w, r)
+	if !ok {
+		return
+	}
+
+	actorID, _, ok := h.authorizeProductWrite(w, r, productID)
+	if !ok {
+		return
+	}
+
+	variant, err := h.service.FindVariant(r.Context(), productID, variantID)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Variant not found", http.StatusNotFound)
+		return
+	}
+
+	if !web.CheckIfMatch(r, variant.Version) {
+		web.RespondWithError(w, "precondition_failed", "Variant has been modified since it was last read", http.StatusPreconditionFailed)
+		return
+	}
+
+	variant, err = h.service.UpdateVariant(r.Context(), productID, variantID, variant.Version, input, actorID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update variant")
+		return
+	}
+
+	web.SetETag(w, variant.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: variant})
+}
+
+// DeleteVariant handles deleting a variant.
+// @Summary Delete a product variant
+// @Description Delete a variant; the product's stock is recalculated. Only the owner, editors or admins can delete variants.
+// @Tags Variants
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param variantID path string true "Variant ID"
+// @Param If-Match header string false "ETag of the variant version being deleted"
+// @Success 204 "Variant deleted successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or variant not found"
+// @Failure 412 {object} web.Response{error=web.ApiError} "Variant version does not match If-Match"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/variants/{variantID} [delete]
+func (h *ProductHandler) DeleteVariant(w http.ResponseWriter, r *http.Request) {
+	productID, variantID, ok := parseVariantPath(w, r)
+	if !ok {
+		return
+	}
+
+	actorID, _, ok := h.authorizeProductWrite(w, r, productID)
+	if !ok {
+		return
+	}
+
+	variant, err := h.service.FindVariant(r.Context(), productID, variantID)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Variant not found", http.StatusNotFound)
+		return
+	}
+
+	if !web.CheckIfMatch(r, variant.Version) {
+		web.RespondWithError(w, "precondition_failed", "Variant has been modified since it was last read", http.StatusPreconditionFailed)
+		return
+	}
+
+	if err := h.service.DeleteVariant(r.Context(), productID, variantID, variant.Version, actorID); err != nil {
+		respondWithWriteError(w, r, err, "Could not delete variant")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// ListProductImages handles fetching the images of a product.
+// @Summary List product images
+// @Description Get the images of a product in display order, with signed, expiring URLs for the image and its thumbnail. Any authenticated user can read them.
+// @Tags Images
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]Image} "List of images"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/images [get]
+func (h *ProductHandler) ListProductImages(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	images, err := h.images.List(r.Context(), productID)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not fetch images")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: images})
+}
+
+// UploadProductImage handles uploading an image of a product.
+// @Summary Upload a product image
+// @Description Upload a JPEG, PNG or GIF image of up to 5 MB as the multipart field "file". The type is detected from the content, a thumbnail is generated and the image is appended to the product's images (at most 10). Only the owner, editors or admins can upload images.
+// @Tags Images
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param file formData file true "Image file"
+// @Success 201 {object} web.Response{data=Image} "Image uploaded successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID or missing file"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Product already has the maximum number of images"
+// @Failure 413 {object} web.Response{error=web.ApiError} "Image too large"
+// @Failure 415 {object} web.Response{error=web.ApiError} "Not a JPEG, PNG or GIF image"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/images [post]
+func (h *ProductHandler) UploadProductImage(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	if _, _, ok := h.authorizeProductWrite(w, r, productID); !ok {
+		return
+	}
+
+	// Leave room for the multipart headers around the file.
+	r.Body = http.MaxBytesReader(w, r.Body, MaxImageSize+64<<10)
+	if err := r.ParseMultipartForm(MaxImageSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondWithWriteError(w, r, ErrImageTooLarge, "")
+			return
+		}
+		web.RespondWithError(w, "bad_request", "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Image file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > MaxImageSize {
+		respondWithWriteError(w, r, ErrImageTooLarge, "")
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Could not read image file", http.StatusBadRequest)
+		return
+	}
+
+	image, err := h.images.Upload(r.Context(), productID, data)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not upload image")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: image})
+}
+
+// ReorderProductImages handles changing the display order of a product's images.
+// @Summary Reorder product images
+// @Description Set the display order of a product's images; image_ids must list each of its images exactly once. Only the owner, editors or admins can reorder images.
+// @Tags Images
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param order body ReorderImagesRequest true "Image IDs in the new order"
+// @Success 200 {object} web.Response{data=[]Image} "Images in the new order"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or order not listing each image exactly once"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/images/order [put]
+func (h *ProductHandler) ReorderProductImages(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req ReorderImagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, _, ok := h.authorizeProductWrite(w, r, productID); !ok {
+		return
+	}
+
+	images, err := h.images.Reorder(r.Context(), productID, req.ImageIDs)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not reorder images")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: images})
+}
+
+// DeleteProductImage handles deleting an image of a product.
+// @Summary Delete a product image
+// @Description Delete an image and its thumbnail; the remaining images keep their order. Only the owner, editors or admins can delete images.
+// @Tags Images
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param imageID path string true "Image ID"
+// @Success 204 "Image deleted successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or image not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/images/{imageID} [delete]
+func (h *ProductHandler) DeleteProductImage(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	imageID, err := uuid.Parse(chi.URLParam(r, "imageID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid image ID format", http.StatusBadRequest)
+		return
+	}
+
+	if _, _, ok := h.authorizeProductWrite(w, r, productID); !ok {
+		return
+	}
+
+	if err := h.images.Delete(r.Context(), productID, imageID); err != nil {
+		respondWithWriteError(w, r, err, "Could not delete image")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// ServeImage handles downloading an image file through a signed URL.
+// @Summary Download an image file
+// @Description Serve an image or thumbnail file. The URL must be one returned by the image endpoints, signed and not yet expired; no bearer token is needed.
+// @Tags Images
+// @Produce image/jpeg,image/png,image/gif
+// @Param key path string true "Image file key"
+// @Param expires query int true "Expiry of the URL, in Unix seconds"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file "Image file"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Invalid or expired signature"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Image not found"
+// @Router /v1/images/{key} [get]
+func (h *ProductHandler) ServeImage(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "*")
+	query := r.URL.Query()
+
+	body, expiry, err := h.images.Open(r.Context(), key, query.Get("expires"), query.Get("signature"))
+	switch {
+	case errors.Is(err, blobstore.ErrInvalidSignature), errors.Is(err, blobstore.ErrURLExpired):
+		web.RespondWithError(w, "forbidden", err.Error(), http.StatusForbidden)
+		return
+	case errors.Is(err, blobstore.ErrNotFound):
+		web.RespondWithError(w, "not_found", "Image not found", http.StatusNotFound)
+		return
+	case err != nil:
+		web.RespondWithServerError(w, r, err, "Could not read image")
+		return
+	}
+	defer body.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(time.Until(expiry).Seconds())))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, body); err != nil {
+		zerolog.Ctx(r.Context()).Error().Err(err).Str("key", key).Msg("Could not serve image")
+	}
+}
+
+// parseVariantPath reads the product and variant IDs from the URL, responding with 400 if either is malformed.
+func parseVariantPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	variantID, err := uuid.Parse(chi.URLParam(r, "variantID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid variant ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return productID, variantID, true
+}
+
+// decodeVariantRequest decodes and validates a variant payload, responding with 400 if it is invalid.
+func (h *ProductHandler) decodeVariantRequest(w http.ResponseWriter, r *http.Request) (VariantInput, bool) {
+	var req VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return VariantInput{}, false
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return VariantInput{}, false
+	}
+
+	return VariantInput{SKU: req.SKU, Options: req.Options, Price: req.Price, Stock: req.Stock}, true
+}
+
+// authorizeProductWrite checks that the authenticated user owns the product, edits it as a
+// collaborator or is an admin, responding with the matching error if not. It returns the user's
+// ID and the product the check was made against.
+func (h *ProductHandler) authorizeProductWrite(w http.ResponseWriter, r *http.Request, productID uuid.UUID) (uuid.UUID, *Product, bool) {
+	return h.authorizeProduct(w, r, productID, CollaboratorEditor, "You do not have permission to modify this product")
+}
+
+// authorizeProduct checks that the authenticated user may access the product with the given
+// collaborator role, responding with the matching error if not. It returns the user's ID and
+// the product the check was made against.
+func (h *ProductHandler) authorizeProduct(w http.ResponseWriter, r *http.Request, productID uuid.UUID, role, message string) (uuid.UUID, *Product, bool) {
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return uuid.Nil, nil, false
+	}
+
+	product, err := h.service.FindByID(r.Context(), productID)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+		return uuid.Nil, nil, false
+	}
+
+	if !h.checkAccess(w, r, actor, product, role, message) {
+		return uuid.Nil, nil, false
+	}
+
+	return actor.UserID, product, true
+}
+
+// checkAccess checks that the actor may access the product with the given collaborator role,
+// responding with a forbidden error carrying message if not.
+func (h *ProductHandler) checkAccess(w http.ResponseWriter, r *http.Request, actor Actor, product *Product, role, message string) bool {
+	allowed, err := h.service.CanAccess(r.Context(), actor, product, role)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not check product permissions")
+		return false
+	}
+
+	if !allowed {
+		web.RespondWithError(w, "forbidden", message, http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// ListTrash handles listing deleted products.
+// @Summary List deleted products
+// @Description List products in the trash, most recently deleted first. Users see their own products; admins see every owner's, optionally filtered by owner_id.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param owner_id query string false "Owner ID (admin only)"
+// @Success 200 {object} web.Response{data=[]TrashedProductResponse} "Deleted products"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid owner ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/trash [get]
+func (h *ProductHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	ownerID := &actor.UserID
+	if actor.IsAdmin() {
+		ownerID = nil
+		if ownerParam := r.URL.Query().Get("owner_id"); ownerParam != "" {
+			id, err := uuid.Parse(ownerParam)
+			if err != nil {
+				web.RespondWithError(w, "bad_request", "Invalid owner ID format", http.StatusBadRequest)
+				return
+			}
+			ownerID = &id
+		}
+	}
+
+	products, err := h.service.ListTrash(r.Context(), ownerID)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch deleted products")
+		return
+	}
+
+	retention := h.service.TrashRetention()
+	trash := make([]TrashedProductResponse, 0, len(products))
+	for _, product := range products {
+		trash = append(trash, TrashedProductResponse{
+			Product: product,
+			PurgeAt: product.DeletedAt.Time.Add(retention),
+		})
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: trash})
+}
+
+// RestoreProduct handles restoring a deleted product.
+// @Summary Restore a deleted product
+// @Description Take a product out of the trash while it is still within the retention window. Only the owner, editors or admins can restore.
+// @Tags Products
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=Product} "Product restored successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not owner, collaborator or admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found in trash"
+// @Failure 409 {object} web.Response{error=web.ApiError} "SKU or barcode now used by another of the owner's products"
+// @Failure 410 {object} web.Response{error=web.ApiError} "Retention window has expired"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/restore [post]
+func (h *ProductHandler) RestoreProduct(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "productID")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	// Check ownership or admin role
+	product, err := h.service.FindDeletedByID(r.Context(), id)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Product not found in trash", http.StatusNotFound)
+		return
+	}
+
+	if !h.checkAccess(w, r, actor, product, CollaboratorEditor, "You do not have permission to restore this product") {
+		return
+	}
+
+	restoredProduct, err := h.service.Restore(r.Context(), id, actor.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrRetentionExpired):
+			web.RespondWithError(w, "gone", "Product can no longer be restored", http.StatusGone)
+		case errors.Is(err, ErrDuplicateCode):
+			web.RespondWithError(w, "conflict", err.Error(), http.StatusConflict)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			web.RespondWithError(w, "not_found", "Product not found in trash", http.StatusNotFound)
+		default:
+			web.RespondWithServerError(w, r, err, "Could not restore product")
+		}
+		return
+	}
+
+	web.SetETag(w, restoredProduct.Version)
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: restoredProduct})
+}
+
+// BatchProducts handles bulk product creation, update and deletion.
+// @Summary Create, update and delete products in bulk
+// @Description Apply up to 500 create, update and delete operations. In atomic mode all operations run in one transaction and nothing is kept if any fails; in best_effort mode each operation is applied independently. Each result carries the status the operation would have had as a single request; operations undone by an atomic rollback report 424. Updates and deletes are only allowed for the owner, editors or an admin.
+// @Tags Products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param batch body BatchProductRequest true "Batch operations"
+// @Success 200 {object} web.Response{data=BatchProductResponse} "All operations succeeded"
+// @Success 207 {object} web.Response{data=BatchProductResponse} "Some operations failed (best_effort)"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 422 {object} web.Response{data=BatchProductResponse} "Atomic batch rolled back"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products:batch [post]
+func (h *ProductHandler) BatchProducts(w http.ResponseWriter, r *http.Request) {
+	var req BatchProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Operations) > maxBatchOperations {
+		web.RespondWithError(w, "validation_error", fmt.Sprintf("A batch may contain at most %d operations", maxBatchOperations), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	atomic := req.Mode == BatchModeAtomic
+	results := make([]BatchItemResult, len(req.Operations))
+
+	// Operations that fail validation are reported as such and never reach the service.
+	var ops []BatchOperation
+	var positions []int
+	for i, item := range req.Operations {
+		results[i] = BatchItemResult{Index: i, Op: item.Op}
+
+		op, err := h.batchOperation(item)
+		if err != nil {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = &web.ApiError{Code: "validation_error", Message: err.Error()}
+			continue
+		}
+
+		ops = append(ops, op)
+		positions = append(positions, i)
+	}
+
+	outcomes, committed, err := h.applyBatch(r.Context(), actor, ops, atomic, len(ops) < len(req.Operations), false)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not apply batch")
+		return
+	}
+
+	for n, outcome := range outcomes {
+		i := positions[n]
+		results[i].Status, results[i].Error = batchItemStatus(ops[n].Op, outcome)
+		if ops[n].Op != BatchDelete {
+			results[i].Data = outcome.Product
+		}
+	}
+
+	response := BatchProductResponse{Mode: req.Mode, Committed: committed, Results: results}
+	for _, result := range results {
+		if result.Error != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+
+	status := http.StatusOK
+	switch {
+	case atomic && response.Failed > 0:
+		status = http.StatusUnprocessableEntity
+	case response.Failed > 0:
+		status = http.StatusMultiStatus
+	}
+
+	web.RespondWithJSON(w, status, web.Response{Data: response})
+}
+
+// ImportProducts handles CSV product imports.
+// @Summary Import products from CSV
+// @Description Upload a CSV file (first line is the header) to create and update products. Rows are matched with existing products on the key column: "id" (rows without an id are created) or "name" (matched among the caller's products). Every row is validated like a single create or update and reported line by line. With dry_run=true nothing is written and the report shows what would be created or updated. Mode works as in the batch endpoint; updates are only allowed for the owner, editors or an admin.
+// @Tags Products
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file"
+// @Param mapping formData string false "JSON object mapping product fields (id, name, description, price, currency, stock) to CSV column names, e.g. {\"price\": \"Unit Price\"}"
+// @Param key formData string false "Key column used to match existing products" Enums(id, name) default(id)
+// @Param mode formData string false "Batch mode" Enums(atomic, best_effort) default(atomic)
+// @Param dry_run query bool false "Validate and report without writing"
+// @Success 200 {object} web.Response{data=ImportProductsResponse} "All rows imported (or dry-run report)"
+// @Success 207 {object} web.Response{data=ImportProductsResponse} "Some rows failed (best_effort)"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or unreadable CSV"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 422 {object} web.Response{data=ImportProductsResponse} "Atomic import rolled back"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/import [post]
+func (h *ProductHandler) ImportProducts(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "CSV file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var mapping map[string]string
+	if raw := r.FormValue("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			web.RespondWithError(w, "bad_request", "Invalid column mapping", http.StatusBadRequest)
+			return
+		}
+	}
+
+	key := r.FormValue("key")
+	if key == "" {
+		key = ImportKeyID
+	}
+	if key != ImportKeyID && key != ImportKeyName {
+		web.RespondWithError(w, "validation_error", "key must be id or name", http.StatusBadRequest)
+		return
+	}
+
+	mode := r.FormValue("mode")
+	if mode == "" {
+		mode = BatchModeAtomic
+	}
+	if mode != BatchModeAtomic && mode != BatchModeBestEffort {
+		web.RespondWithError(w, "validation_error", "mode must be atomic or best_effort", http.StatusBadRequest)
+		return
+	}
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			web.RespondWithError(w, "bad_request", "Invalid dry_run parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	rows, err := parseImportCSV(file, mapping, maxImportRows)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Could not read CSV: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	atomic := mode == BatchModeAtomic
+	results := make([]ImportRowResult, len(rows))
+
+	// Rows that fail validation are reported as such and never reach the service.
+	var ops []BatchOperation
+	var positions []int
+	for i, row := range rows {
+		results[i] = ImportRowResult{Line: row.Line}
+
+		op, errs, err := h.importOperation(r.Context(), row, key, actor.UserID)
+		if err != nil {
+			web.RespondWithServerError(w, r, err, "Could not match imported rows")
+			return
+		}
+
+		results[i].Action = op.Op
+		if len(errs) > 0 {
+			results[i].Status = http.StatusBadRequest
+			results[i].Errors = errs
+			continue
+		}
+
+		ops = append(ops, op)
+		positions = append(positions, i)
+	}
+
+	outcomes, committed, err := h.applyBatch(r.Context(), actor, ops, atomic, len(ops) < len(rows), dryRun)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not import products")
+		return
+	}
+
+	for n, outcome := range outcomes {
+		i := positions[n]
+		var apiErr *web.ApiError
+		results[i].Status, apiErr = batchItemStatus(ops[n].Op, outcome)
+		if apiErr != nil {
+			results[i].Errors = []ImportFieldError{{Message: apiErr.Message}}
+			continue
+		}
+		results[i].Data = outcome.Product
+	}
+
+	response := ImportProductsResponse{DryRun: dryRun, Mode: mode, Committed: committed, Rows: results}
+	for _, result := range results {
+		switch {
+		case len(result.Errors) > 0:
+			response.Failed++
+		case result.Action == BatchCreate:
+			response.Created++
+		default:
+			response.Updated++
+		}
+	}
+
+	status := http.StatusOK
+	switch {
+	case dryRun:
+	case atomic && response.Failed > 0:
+		status = http.StatusUnprocessableEntity
+	case response.Failed > 0:
+		status = http.StatusMultiStatus
+	}
+
+	web.RespondWithJSON(w, status, web.Response{Data: response})
+}
+
+// importOperation validates an imported row and turns it into a create, or an update when
+// the row's key matches an existing product. Rejected rows are returned with their field errors.
+func (h *ProductHandler) importOperation(ctx context.Context, row importRow, key string, ownerID uuid.UUID) (BatchOperation, []ImportFieldError, error) {
+	op := BatchOperation{Op: BatchCreate}
+	req, errs := row.productRequest()
+
+	switch key {
+	case ImportKeyID:
+		if value := row.Values["id"]; value != "" {
+			op.Op = BatchUpdate
+			id, err := uuid.Parse(value)
+			if err != nil {
+				errs = append(errs, ImportFieldError{Field: "id", Message: "not a valid UUID"})
+			}
+			op.ID = id
+		}
+	case ImportKeyName:
+		if name := row.Values["name"]; name != "" {
+			product, err := h.service.FindByName(ctx, ownerID, name)
+			switch {
+			case err == nil:
+				op.Op = BatchUpdate
+				op.ID = product.ID
+			case !errors.Is(err, gorm.ErrRecordNotFound):
+				return op, nil, err
+			}
+		}
+	}
+
+	var validationErr error
+	if op.Op == BatchUpdate {
+		validationErr = h.validate.Struct(UpdateProductRequest(req))
+	} else {
+		validationErr = h.validate.Struct(req)
+	}
+	if validationErr != nil {
+		// Cells that could not be parsed already have a more precise error.
+		for _, fieldErr := range importFieldErrors(validationErr) {
+			if !slices.ContainsFunc(errs, func(e ImportFieldError) bool { return e.Field == fieldErr.Field }) {
+				errs = append(errs, fieldErr)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return op, errs, nil
+	}
+
+	op.Input = ProductInput{
+		Name:        req.Name,
+		Description: req.Description,
+		SKU:         req.SKU,
+		GTIN:        req.GTIN,
+		Price:       *req.Price,
+		Currency:    req.Currency,
+		Stock:       req.Stock,
+	}
+
+	return op, nil, nil
+}
+
+// applyBatch applies the valid operations of a batch on behalf of actor and reports whether
+// any change was kept. In atomic mode nothing is applied if invalid operations were left out;
+// a dry run applies nothing but reports what an atomic batch would do.
+func (h *ProductHandler) applyBatch(ctx context.Context, actor Actor, ops []BatchOperation, atomic, invalid, dryRun bool) ([]BatchResult, bool, error) {
+	switch {
+	case dryRun:
+		results, err := h.service.DryRunBatch(ctx, actor, ops)
+		return results, false, err
+	case atomic && invalid:
+		results := make([]BatchResult, len(ops))
+		for i := range results {
+			results[i].Err = ErrBatchRolledBack
+		}
+		return results, false, nil
+	}
+
+	results, err := h.service.Batch(ctx, actor, ops, atomic)
+	if err != nil && !errors.Is(err, ErrBatchRolledBack) {
+		return nil, false, err
+	}
+	return results, err == nil, nil
+}
+
+// batchItemStatus returns the status a batch operation would have had as a single request.
+func batchItemStatus(op string, outcome BatchResult) (int, *web.ApiError) {
+	switch {
+	case outcome.Err != nil:
+		apiErr, status := writeError(outcome.Err, "Could not apply operation")
+		return status, apiErr
+	case op == BatchCreate:
+		return http.StatusCreated, nil
+	case op == BatchDelete:
+		return http.StatusNoContent, nil
+	default:
+		return http.StatusOK, nil
+	}
+}
+
+// batchOperation decodes and validates the payload of a batch operation.
+func (h *ProductHandler) batchOperation(item BatchOperationRequest) (BatchOperation, error) {
+	op := BatchOperation{Op: item.Op, Version: item.Version}
+	if item.ID != nil {
+		op.ID = *item.ID
+	}
+
+	if item.Op == BatchDelete {
+		return op, nil
+	}
+
+	if len(item.Data) == 0 {
+		return op, errors.New("data is required")
+	}
+
+	// Creates and updates share the same fields and validation rules.
+	var data CreateProductRequest
+	if item.Op == BatchUpdate {
+		var update UpdateProductRequest
+		if err := json.Unmarshal(item.Data, &update); err != nil {
+			return op, errors.New("invalid data payload")
+		}
+		if err := h.validate.Struct(update); err != nil {
+			return op, err
+		}
+		data = CreateProductRequest(update)
+	} else {
+		if err := json.Unmarshal(item.Data, &data); err != nil {
+			return op, errors.New("invalid data payload")
+		}
+		if err := h.validate.Struct(data); err != nil {
+			return op, err
+		}
+	}
+
+	op.Input = ProductInput{
+		Name:        data.Name,
+		Description: data.Description,
+		SKU:         data.SKU,
+		GTIN:        data.GTIN,
+		Price:       *data.Price,
+		Currency:    data.Currency,
+		Stock:       data.Stock,
+	}
+
+	return op, nil
+}
+
+// respondWithWriteError maps errors from product writes to the matching HTTP response.
+func respondWithWriteError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	apiErr, status := writeError(err, message)
+	if status == http.StatusInternalServerError {
+		web.RespondWithServerError(w, r, err, message)
+		return
+	}
+	web.RespondWithError(w, apiErr.Code, apiErr.Message, status)
+}
+
+// writeError maps a service error to an API error and HTTP status.
+func writeError(err error, message string) (*web.ApiError, int) {
+	switch {
+	case errors.Is(err, ErrVersionMismatch):
+		return &web.ApiError{Code: "precondition_failed", Message: "Product has been modified since it was last read"}, http.StatusPreconditionFailed
+	case errors.Is(err, ErrUnsupportedCurrency), errors.Is(err, ErrInvalidPriceScale), errors.Is(err, ErrInvalidAdjustment),
+		errors.Is(err, ErrCategoryNotFound), errors.Is(err, ErrInvalidTag):
+		return &web.ApiError{Code: "validation_error", Message: err.Error()}, http.StatusBadRequest
+	case errors.Is(err, ErrDuplicateVariant), errors.Is(err, ErrDuplicateCode):
+		return &web.ApiError{Code: "conflict", Message: err.Error()}, http.StatusConflict
+	case errors.Is(err, ErrInvalidTransition):
+		return &web.ApiError{Code: "invalid_transition", Message: err.Error()}, http.StatusConflict
+	case errors.Is(err, ErrProductArchived):
+		return &web.ApiError{Code: "product_archived", Message: "Archived products are read-only"}, http.StatusConflict
+	case errors.Is(err, ErrProductDiscontinued):
+		return &web.ApiError{Code: "product_discontinued", Message: "Stock of a discontinued product can only be increased by returns"}, http.StatusConflict
+	case errors.Is(err, ErrNotForSale):
+		return &web.ApiError{Code: "not_for_sale", Message: err.Error()}, http.StatusConflict
+	case errors.Is(err, ErrStockManagedByVariants):
+		return &web.ApiError{Code: "stock_managed_by_variants", Message: "Stock of a product with variants is the sum of its variants; edit the variants instead"}, http.StatusConflict
+	case errors.Is(err, ErrStockManagedByWarehouses):
+		return &web.ApiError{Code: "stock_managed_by_warehouses", Message: "Stock of a product kept in warehouses is the sum of its stock levels; edit the stock levels instead"}, http.StatusConflict
+	case errors.Is(err, ErrNoStockLevels), errors.Is(err, ErrSameWarehouse):
+		return &web.ApiError{Code: "validation_error", Message: err.Error()}, http.StatusBadRequest
+	case errors.Is(err, ErrStockLevelNotEmpty):
+		return &web.ApiError{Code: "conflict", Message: "Move or count out the stock held in this warehouse first"}, http.StatusConflict
+	case errors.Is(err, ErrWarehouseNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Warehouse not found"}, http.StatusNotFound
+	case errors.Is(err, ErrStockLevelNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Stock level not found"}, http.StatusNotFound
+	case errors.Is(err, ErrInvalidImageOrder):
+		return &web.ApiError{Code: "validation_error", Message: err.Error()}, http.StatusBadRequest
+	case errors.Is(err, ErrTooManyImages):
+		return &web.ApiError{Code: "conflict", Message: err.Error()}, http.StatusConflict
+	case errors.Is(err, ErrImageTooLarge):
+		return &web.ApiError{Code: "payload_too_large", Message: "Images must be at most 5 MB and 25 megapixels"}, http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrUnsupportedImage):
+		return &web.ApiError{Code: "unsupported_media_type", Message: err.Error()}, http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrInvalidSchedule):
+		return &web.ApiError{Code: "validation_error", Message: err.Error()}, http.StatusBadRequest
+	case errors.Is(err, ErrScheduleOverlap), errors.Is(err, ErrScheduleNotPending):
+		return &web.ApiError{Code: "conflict", Message: err.Error()}, http.StatusConflict
+	case errors.Is(err, ErrInvalidCollaboratorRole), errors.Is(err, ErrCollaboratorIsOwner):
+		return &web.ApiError{Code: "validation_error", Message: err.Error()}, http.StatusBadRequest
+	case errors.Is(err, ErrCollaboratorUserNotFound):
+		return &web.ApiError{Code: "not_found", Message: "User not found"}, http.StatusNotFound
+	case errors.Is(err, ErrCollaboratorNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Collaborator not found"}, http.StatusNotFound
+	case errors.Is(err, ErrScheduleNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Price schedule not found"}, http.StatusNotFound
+	case errors.Is(err, ErrRevisionNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Revision not found"}, http.StatusNotFound
+	case errors.Is(err, ErrImageNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Image not found"}, http.StatusNotFound
+	case errors.Is(err, ErrInsufficientStock):
+		return &web.ApiError{Code: "insufficient_stock", Message: "Not enough stock for this change"}, http.StatusConflict
+	case errors.Is(err, ErrForbidden):
+		return &web.ApiError{Code: "forbidden", Message: "You do not have permission to modify this product"}, http.StatusForbidden
+	case errors.Is(err, ErrBatchRolledBack):
+		return &web.ApiError{Code: "rolled_back", Message: "Not applied because another operation in the batch failed"}, http.StatusFailedDependency
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Product not found"}, http.StatusNotFound
+	default:
+		return &web.ApiError{Code: "internal_error", Message: message}, http.StatusInternalServerError
+	}
+}
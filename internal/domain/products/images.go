@@ -0,0 +1,310 @@
+package products
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	// Register the GIF decoder; JPEG and PNG are registered by the imports above.
+	_ "image/gif"
+
+	"go-crud-api/pkg/blobstore"
+	"go-crud-api/pkg/imaging"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxImageSize is the largest image upload accepted, in bytes.
+	MaxImageSize = 5 << 20
+	// maxImagePixels bounds the decoded size of an image, so small files cannot expand into huge bitmaps.
+	maxImagePixels = 25_000_000
+	// maxProductImages is the number of images a product can have.
+	maxProductImages = 10
+	// thumbnailSize is the largest side of a thumbnail, in pixels.
+	thumbnailSize = 320
+	// defaultImageURLTTL is used when the signed URL lifetime is not configured.
+	defaultImageURLTTL = 15 * time.Minute
+)
+
+// imageExtensions maps the accepted image types, as sniffed from their content, to the extension they are stored with.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ImageService manages product images. Image files live in a blob store and are
+// served through signed, expiring URLs; their metadata lives in the database.
+type ImageService struct {
+	repo   ProductRepository
+	store  blobstore.BlobStore
+	signer *blobstore.URLSigner
+	urlTTL time.Duration
+}
+
+// NewImageService creates a new image service. Signed URLs are valid for urlTTL.
+func NewImageService(repo ProductRepository, store blobstore.BlobStore, signer *blobstore.URLSigner, urlTTL time.Duration) *ImageService {
+	if urlTTL <= 0 {
+		urlTTL = defaultImageURLTTL
+	}
+	return &ImageService{repo: repo, store: store, signer: signer, urlTTL: urlTTL}
+}
+
+// List returns the images of a product ordered by position.
+func (s *ImageService) List(ctx context.Context, productID uuid.UUID) ([]Image, error) {
+	if _, err := s.repo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	images, err := s.repo.ListImages(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(images)
+	return images, nil
+}
+
+// Upload validates an image by sniffing its content, stores it with a thumbnail
+// and appends it to the product's images.
+func (s *ImageService) Upload(ctx context.Context, productID uuid.UUID, data []byte) (*Image, error) {
+	if len(data) > MaxImageSize {
+		return nil, ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	thumbnail, thumbnailType, thumbnailExt, err := encodeThumbnail(decoded, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New()
+	img := &Image{
+		ID:           id,
+		ProductID:    productID,
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Width:        config.Width,
+		Height:       config.Height,
+		BlobKey:      "products/" + productID.String() + "/images/" + id.String() + ext,
+		ThumbnailKey: "products/" + productID.String() + "/images/" + id.String() + "_thumb" + thumbnailExt,
+	}
+
+	// Blobs are written before the row, so a listed image always has its files;
+	// they are removed again if the row cannot be created.
+	if err := s.store.Put(ctx, img.BlobKey, bytes.NewReader(data), img.Size, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, img.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), thumbnailType); err != nil {
+		s.deleteBlobs(ctx, *img)
+		return nil, err
+	}
+
+	err = s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		// Locking the product serialises uploads, so positions stay unique.
+		product, err := tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+
+		images, err := tx.ListImages(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if len(images) >= maxProductImages {
+			return ErrTooManyImages
+		}
+
+		img.Position = len(images)
+		return tx.CreateImage(ctx, img)
+	})
+	if err != nil {
+		s.deleteBlobs(ctx, *img)
+		return nil, err
+	}
+
+	img.URL, img.ThumbnailURL = s.signer.Sign(img.BlobKey, s.urlTTL), s.signer.Sign(img.ThumbnailKey, s.urlTTL)
+	return img, nil
+}
+
+// Reorder sets the order of a product's images. ids must list each of its images exactly once.
+func (s *ImageService) Reorder(ctx context.Context, productID uuid.UUID, ids []uuid.UUID) ([]Image, error) {
+	var images []Image
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		product, err := tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+
+		current, err := tx.ListImages(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if !isPermutation(current, ids) {
+			return ErrInvalidImageOrder
+		}
+
+		if err := tx.SetImagePositions(ctx, productID, ids); err != nil {
+			return err
+		}
+
+		images, err = tx.ListImages(ctx, productID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.sign(images)
+	return images, nil
+}
+
+// Delete removes an image, closes the gap in the positions of the others and deletes its files.
+func (s *ImageService) Delete(ctx context.Context, productID, id uuid.UUID) error {
+	var img *Image
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		product, err := tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+
+		img, err = tx.FindImage(ctx, productID, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrImageNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.DeleteImage(ctx, productID, id); err != nil {
+			return err
+		}
+
+		remaining, err := tx.ListImages(ctx, productID)
+		if err != nil {
+			return err
+		}
+		ids := make([]uuid.UUID, len(remaining))
+		for i, image := range remaining {
+			ids[i] = image.ID
+		}
+		return tx.SetImagePositions(ctx, productID, ids)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The image is gone once its row is; files left behind by a failed delete are only logged.
+	s.deleteBlobs(ctx, *img)
+	return nil
+}
+
+// DeleteExpiredTrashBlobs deletes the files of images of products moved to the trash more than retention ago.
+// It runs before the products are purged; the image rows go with them.
+func (s *ImageService) DeleteExpiredTrashBlobs(ctx context.Context, retention time.Duration) error {
+	images, err := s.repo.ListImagesDeletedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, img := range images {
+		errs = append(errs, s.store.Delete(ctx, img.BlobKey), s.store.Delete(ctx, img.ThumbnailKey))
+	}
+	return errors.Join(errs...)
+}
+
+// Open returns the content of an image file and the expiry of its URL
+// if signature and expires form a valid signed URL for key.
+func (s *ImageService) Open(ctx context.Context, key, expires, signature string) (io.ReadCloser, time.Time, error) {
+	expiry, err := s.signer.Verify(key, expires, signature)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	body, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return body, expiry, nil
+}
+
+// sign fills in the signed URLs of images.
+func (s *ImageService) sign(images []Image) {
+	for i := range images {
+		images[i].URL = s.signer.Sign(images[i].BlobKey, s.urlTTL)
+		images[i].ThumbnailURL = s.signer.Sign(images[i].ThumbnailKey, s.urlTTL)
+	}
+}
+
+func (s *ImageService) deleteBlobs(ctx context.Context, img Image) {
+	for _, key := range []string{img.BlobKey, img.ThumbnailKey} {
+		if err := s.store.Delete(ctx, key); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("Could not delete image blob")
+		}
+	}
+}
+
+// encodeThumbnail scales img down to a thumbnail, encoded as JPEG for JPEG images and as PNG otherwise,
+// which keeps transparency.
+func encodeThumbnail(img image.Image, contentType string) (data []byte, thumbnailType, ext string, err error) {
+	thumbnail := imaging.Thumbnail(img, thumbnailSize)
+
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 85})
+		return buf.Bytes(), "image/jpeg", ".jpg", err
+	}
+	err = png.Encode(&buf, thumbnail)
+	return buf.Bytes(), "image/png", ".png", err
+}
+
+// isPermutation reports whether ids lists the ID of each image exactly once.
+func isPermutation(images []Image, ids []uuid.UUID) bool {
+	if len(images) != len(ids) {
+		return false
+	}
+	for _, img := range images {
+		if !slices.Contains(ids, img.ID) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package products
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-crud-api/pkg/blobstore"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestImageService(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	product := &Product{ID: productID}
+
+	newService := func(t *testing.T) (*ImageService, *MockProductRepository, string) {
+		dir := t.TempDir()
+		store, err := blobstore.NewLocalStore(dir)
+		require.NoError(t, err)
+		repo := new(MockProductRepository)
+		return NewImageService(repo, store, blobstore.NewURLSigner("secret", "/v1/images"), time.Minute), repo, dir
+	}
+
+	// Test case 1: Upload stores the image and a thumbnail and appends the image
+	service, repo, dir := newService(t)
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil)
+	repo.On("ListImages", ctx, productID).Return([]Image{{ID: uuid.New()}}, nil)
+	repo.On("CreateImage", ctx, mock.AnythingOfType("*products.Image")).Return(nil)
+
+	img, err := service.Upload(ctx, productID, testPNG(t, 800, 400))
+	require.NoError(t, err)
+	assert.Equal(t, 1, img.Position)
+	assert.Equal(t, "image/png", img.ContentType)
+	assert.Equal(t, 800, img.Width)
+	assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(img.BlobKey)))
+
+	thumbnailFile, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(img.ThumbnailKey)))
+	require.NoError(t, err)
+	thumbnail, err := png.DecodeConfig(bytes.NewReader(thumbnailFile))
+	require.NoError(t, err)
+	assert.Equal(t, thumbnailSize, thumbnail.Width)
+	assert.Equal(t, thumbnailSize/2, thumbnail.Height)
+
+	// Test case 2: The signed URL of an uploaded image opens it; altered URLs do not
+	u, err := url.Parse(img.URL)
+	require.NoError(t, err)
+	body, _, err := service.Open(ctx, img.BlobKey, u.Query().Get("expires"), u.Query().Get("signature"))
+	require.NoError(t, err)
+	content, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, int64(len(content)), img.Size)
+
+	_, _, err = service.Open(ctx, img.ThumbnailKey, u.Query().Get("expires"), u.Query().Get("signature"))
+	assert.ErrorIs(t, err, blobstore.ErrInvalidSignature)
+
+	// Test case 3: Content that is not an image is rejected, whatever its name
+	service, repo, _ = newService(t)
+	_, err = service.Upload(ctx, productID, []byte("<html><body>hello</body></html>"))
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+	repo.AssertNotCalled(t, "CreateImage", mock.Anything, mock.Anything)
+
+	// Test case 4: A product with the maximum number of images rejects uploads and keeps no files
+	service, repo, dir = newService(t)
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil)
+	repo.On("ListImages", ctx, productID).Return(make([]Image, maxProductImages), nil)
+
+	_, err = service.Upload(ctx, productID, testPNG(t, 10, 10))
+	assert.ErrorIs(t, err, ErrTooManyImages)
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "products", productID.String(), "images", "*"))
+	assert.Empty(t, leftovers)
+
+	// Test case 5: Reorder requires each image exactly once
+	first, second := uuid.New(), uuid.New()
+	service, repo, _ = newService(t)
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil)
+	repo.On("ListImages", ctx, productID).Return([]Image{{ID: first}, {ID: second}}, nil)
+	repo.On("SetImagePositions", ctx, productID, []uuid.UUID{second, first}).Return(nil)
+
+	_, err = service.Reorder(ctx, productID, []uuid.UUID{first, first})
+	assert.ErrorIs(t, err, ErrInvalidImageOrder)
+	_, err = service.Reorder(ctx, productID, []uuid.UUID{second, first})
+	assert.NoError(t, err)
+	repo.AssertCalled(t, "SetImagePositions", ctx, productID, []uuid.UUID{second, first})
+
+	// Test case 6: Delete removes the image, renumbers the rest and deletes the files
+	service, repo, dir = newService(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "p"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "p", "a.png"), []byte("a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "p", "a_thumb.png"), []byte("a"), 0o644))
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil)
+	repo.On("FindImage", ctx, productID, first).Return(&Image{ID: first, BlobKey: "p/a.png", ThumbnailKey: "p/a_thumb.png"}, nil)
+	repo.On("DeleteImage", ctx, productID, first).Return(nil)
+	repo.On("ListImages", ctx, productID).Return([]Image{{ID: second, Position: 1}}, nil)
+	repo.On("SetImagePositions", ctx, productID, []uuid.UUID{second}).Return(nil)
+
+	require.NoError(t, service.Delete(ctx, productID, first))
+	assert.NoFileExists(t, filepath.Join(dir, "p", "a.png"))
+	assert.NoFileExists(t, filepath.Join(dir, "p", "a_thumb.png"))
+	repo.AssertExpectations(t)
+}
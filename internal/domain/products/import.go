@@ -0,0 +1,155 @@
+package products
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// Import key columns used to match CSV rows with existing products.
+const (
+	ImportKeyID   = "id"
+	ImportKeyName = "name"
+)
+
+// importFields are the product fields a CSV import can set.
+var importFields = []string{"id", "name", "description", "sku", "gtin", "price", "currency", "stock"}
+
+// importRow is a data row of an imported CSV file, keyed by product field.
+type importRow struct {
+	Line   int
+	Values map[string]string
+}
+
+// ImportFieldError describes why a field of an imported row was rejected.
+type ImportFieldError struct {
+	Field   string `json:"field,omitempty" example:"price"`
+	Message string `json:"message" example:"failed on the 'money' rule"`
+}
+
+// parseImportCSV reads a CSV file whose first record is a header.
+// mapping maps product fields to header names; unmapped fields are read from the column
+// with the field's own name, if any. Header names are matched case-insensitively.
+func parseImportCSV(r io.Reader, mapping map[string]string, maxRows int) ([]importRow, error) {
+	for field := range mapping {
+		if !slices.Contains(importFields, field) {
+			return nil, fmt.Errorf("unknown field %q in column mapping", field)
+		}
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columnIndex := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet exports often start with a UTF-8 byte order mark.
+		name = strings.TrimPrefix(name, "\ufeff")
+		columnIndex[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int)
+	for _, field := range importFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+		i, ok := columnIndex[strings.ToLower(name)]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("column %q mapped to %q not found in header", name, field)
+			}
+			continue
+		}
+		columns[field] = i
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("file has more than %d rows", maxRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := importRow{Line: line, Values: make(map[string]string, len(columns))}
+		for field, i := range columns {
+			row.Values[field] = strings.TrimSpace(record[i])
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// productRequest converts an imported row into a product request.
+// Cells that cannot be parsed are reported as field errors and left empty,
+// so the validator reports the remaining fields.
+func (row importRow) productRequest() (CreateProductRequest, []ImportFieldError) {
+	req := CreateProductRequest{
+		Name:        row.Values["name"],
+		Description: row.Values["description"],
+		SKU:         row.Values["sku"],
+		GTIN:        row.Values["gtin"],
+		Currency:    strings.ToUpper(row.Values["currency"]),
+	}
+
+	var errs []ImportFieldError
+	if value := row.Values["price"]; value != "" {
+		price, err := decimal.NewFromString(value)
+		if err != nil {
+			errs = append(errs, ImportFieldError{Field: "price", Message: "not a decimal number"})
+		} else {
+			req.Price = &price
+		}
+	}
+
+	if value := row.Values["stock"]; value != "" {
+		stock, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, ImportFieldError{Field: "stock", Message: "not an integer"})
+		} else {
+			req.Stock = stock
+		}
+	}
+
+	return req, errs
+}
+
+// importFieldErrors converts a validation error into per-field errors.
+func importFieldErrors(err error) []ImportFieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []ImportFieldError{{Message: err.Error()}}
+	}
+
+	errs := make([]ImportFieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		errs = append(errs, ImportFieldError{
+			Field:   strings.ToLower(fe.Field()),
+			Message: fmt.Sprintf("failed on the '%s' rule", fe.Tag()),
+		})
+	}
+	return errs
+}
@@ -0,0 +1,62 @@
+package products
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	// Test case 1: Default mapping with a byte order mark and mixed-case headers
+	file := "\ufeffName,Price,Stock,Ignored\nWidget,19.90,5,x\n Gadget , 3 ,1,y\n"
+	rows, err := parseImportCSV(strings.NewReader(file), nil, 10)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, map[string]string{"name": "Widget", "price": "19.90", "stock": "5"}, rows[0].Values)
+	assert.Equal(t, "Gadget", rows[1].Values["name"])
+	assert.Equal(t, "3", rows[1].Values["price"])
+
+	// Test case 2: Custom column mapping
+	file = "Product,Unit Price,Qty\nWidget,19.90,5\n"
+	rows, err = parseImportCSV(strings.NewReader(file), map[string]string{"name": "Product", "price": "unit price", "stock": "Qty"}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "Widget", "price": "19.90", "stock": "5"}, rows[0].Values)
+
+	// Test case 3: Mapped column missing from the header
+	_, err = parseImportCSV(strings.NewReader(file), map[string]string{"price": "Cost"}, 10)
+	assert.ErrorContains(t, err, `"Cost"`)
+
+	// Test case 4: Unknown field in the mapping
+	_, err = parseImportCSV(strings.NewReader(file), map[string]string{"weight": "Qty"}, 10)
+	assert.ErrorContains(t, err, "unknown field")
+
+	// Test case 5: Too many rows
+	_, err = parseImportCSV(strings.NewReader("name\na\nb\nc\n"), nil, 2)
+	assert.ErrorContains(t, err, "more than 2 rows")
+
+	// Test case 6: Empty file
+	_, err = parseImportCSV(strings.NewReader(""), nil, 10)
+	assert.Error(t, err)
+}
+
+func TestImportRow_ProductRequest(t *testing.T) {
+	// Test case 1: Valid cells
+	row := importRow{Values: map[string]string{"name": "Widget", "price": "19.90", "currency": "eur", "stock": "5"}}
+	req, errs := row.productRequest()
+	assert.Empty(t, errs)
+	assert.Equal(t, "Widget", req.Name)
+	assert.True(t, decimal.RequireFromString("19.90").Equal(*req.Price))
+	assert.Equal(t, "EUR", req.Currency)
+	assert.Equal(t, 5, req.Stock)
+
+	// Test case 2: Unparseable cells
+	row = importRow{Values: map[string]string{"name": "Widget", "price": "abc", "stock": "1.5"}}
+	req, errs = row.productRequest()
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "price", errs[0].Field)
+	assert.Equal(t, "stock", errs[1].Field)
+	assert.Nil(t, req.Price)
+}
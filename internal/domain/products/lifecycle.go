@@ -0,0 +1,96 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// transitions lists the statuses a product can move to from each status.
+// Archived is final; a discontinued product can be brought back.
+var transitions = map[string][]string{
+	StatusDraft:        {StatusActive},
+	StatusActive:       {StatusDiscontinued},
+	StatusDiscontinued: {StatusActive, StatusArchived},
+}
+
+// ForSale reports whether the product can be ordered and is shown in the public catalogue.
+func (p *Product) ForSale() bool {
+	return p.Status == StatusActive || p.Status == StatusDiscontinued
+}
+
+// Activate puts a draft or discontinued product on sale.
+// version is the version the caller expects the product to be at; zero skips the precondition.
+func (s *Service) Activate(ctx context.Context, id uuid.UUID, version int, actorID uuid.UUID) (*Product, error) {
+	return s.transition(ctx, id, version, StatusActive, actorID)
+}
+
+// Discontinue stops restocking an active product. It stays on sale until its stock runs out.
+// version is the version the caller expects the product to be at; zero skips the precondition.
+func (s *Service) Discontinue(ctx context.Context, id uuid.UUID, version int, actorID uuid.UUID) (*Product, error) {
+	return s.transition(ctx, id, version, StatusDiscontinued, actorID)
+}
+
+// Archive retires a discontinued product. Archived products are kept for order history but are read-only.
+// version is the version the caller expects the product to be at; zero skips the precondition.
+func (s *Service) Archive(ctx context.Context, id uuid.UUID, version int, actorID uuid.UUID) (*Product, error) {
+	return s.transition(ctx, id, version, StatusArchived, actorID)
+}
+
+// transition moves a product to status, if its current status allows it, and records the
+// change as a revision made by actorID.
+func (s *Service) transition(ctx context.Context, id uuid.UUID, version int, status string, actorID uuid.UUID) (*Product, error) {
+	product, err := findAtVersion(ctx, s.repo, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(transitions[product.Status], status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, product.Status, status)
+	}
+
+	product.Status = status
+	err = s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		if err := tx.UpdateFields(ctx, product, []string{"status"}); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, product, RevisionUpdate, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// findEditable loads a product at the expected version and checks it is not archived.
+func findEditable(ctx context.Context, repo ProductRepository, id uuid.UUID, version int) (*Product, error) {
+	product, err := findAtVersion(ctx, repo, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkEditable(product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// checkEditable rejects changes to archived products.
+func checkEditable(product *Product) error {
+	if product.Status == StatusArchived {
+		return ErrProductArchived
+	}
+	return nil
+}
+
+// checkStockIncrease rejects raising the stock of a discontinued product to stock.
+func checkStockIncrease(product *Product, stock int) error {
+	if product.Status == StatusDiscontinued && stock > product.Stock {
+		return ErrProductDiscontinued
+	}
+	return nil
+}
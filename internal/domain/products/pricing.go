@@ -0,0 +1,271 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// recordPriceChange adds the product's current price to its price history when it differs
+// from previousPrice in previousCurrency. scheduleID is set for changes made by a price schedule.
+func recordPriceChange(ctx context.Context, tx ProductRepository, product *Product, previousPrice decimal.Decimal, previousCurrency string, actorID uuid.UUID, scheduleID *uuid.UUID) error {
+	if product.Price.Equal(previousPrice) && product.Currency == previousCurrency {
+		return nil
+	}
+
+	return tx.CreatePriceChange(ctx, &PriceChange{
+		ProductID:  product.ID,
+		Price:      product.Price,
+		Currency:   product.Currency,
+		ScheduleID: scheduleID,
+		ActorID:    actorRef(actorID),
+	})
+}
+
+// ListPriceHistory returns the prices a product has had, newest first.
+func (s *Service) ListPriceHistory(ctx context.Context, productID uuid.UUID) ([]PriceChange, error) {
+	if _, err := s.repo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPriceChanges(ctx, productID)
+}
+
+// ListPriceSchedules returns the price schedules of a product ordered by start.
+func (s *Service) ListPriceSchedules(ctx context.Context, productID uuid.UUID) ([]PriceSchedule, error) {
+	if _, err := s.repo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPriceSchedules(ctx, productID)
+}
+
+// SchedulePrice schedules a price change of a product in its current currency.
+// The schedule must start in the future and must not overlap the product's pending or active schedules.
+func (s *Service) SchedulePrice(ctx context.Context, productID uuid.UUID, input PriceScheduleInput, actorID uuid.UUID) (*PriceSchedule, error) {
+	if !input.EffectiveFrom.After(time.Now()) {
+		return nil, ErrInvalidSchedule
+	}
+	if input.EffectiveTo != nil && !input.EffectiveTo.After(input.EffectiveFrom) {
+		return nil, ErrInvalidSchedule
+	}
+
+	var schedule *PriceSchedule
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		// Locking the product serialises scheduling, so the overlap check holds until the insert.
+		product, err := tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+		if err := s.checkPrice(input.Price, product.Currency); err != nil {
+			return err
+		}
+
+		schedule = &PriceSchedule{
+			ProductID:     productID,
+			Price:         input.Price,
+			Currency:      product.Currency,
+			EffectiveFrom: input.EffectiveFrom,
+			EffectiveTo:   input.EffectiveTo,
+			Status:        ScheduleStatusPending,
+			CreatedBy:     actorRef(actorID),
+		}
+
+		existing, err := tx.ListPriceSchedules(ctx, productID)
+		if err != nil {
+			return err
+		}
+		for _, other := range existing {
+			if isOpenSchedule(other) && schedulesOverlap(*schedule, other) {
+				return ErrScheduleOverlap
+			}
+		}
+
+		return tx.CreatePriceSchedule(ctx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// CancelPriceSchedule cancels a price schedule that has not started yet.
+func (s *Service) CancelPriceSchedule(ctx context.Context, productID, id uuid.UUID) (*PriceSchedule, error) {
+	var schedule *PriceSchedule
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		if _, err := tx.FindByIDForUpdate(ctx, productID); err != nil {
+			return err
+		}
+
+		var err error
+		schedule, err = findPriceSchedule(ctx, tx, productID, id)
+		if err != nil {
+			return err
+		}
+		if schedule.Status != ScheduleStatusPending {
+			return ErrScheduleNotPending
+		}
+
+		now := time.Now()
+		schedule.Status = ScheduleStatusCancelled
+		schedule.EndedAt = &now
+		return tx.UpdatePriceSchedule(ctx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// ApplyDuePriceSchedules starts the pending schedules due at now and ends the active schedules
+// whose end has passed. It returns how many schedules were processed. A failing schedule does
+// not stop the others; it is retried on the next run.
+func (s *Service) ApplyDuePriceSchedules(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.ListDuePriceSchedules(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	var errs []error
+	for _, schedule := range due {
+		if err := s.runSchedule(ctx, schedule.ProductID, schedule.ID, now); err != nil {
+			errs = append(errs, fmt.Errorf("price schedule %s: %w", schedule.ID, err))
+			continue
+		}
+		processed++
+	}
+
+	return processed, errors.Join(errs...)
+}
+
+// runSchedule starts or ends a schedule. The schedule is re-read under the product lock,
+// so concurrent runs, such as those of several API instances, process it once.
+func (s *Service) runSchedule(ctx context.Context, productID, id uuid.UUID, now time.Time) error {
+	return s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		if _, err := tx.FindByIDForUpdate(ctx, productID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// Moved to the trash since the schedule was listed.
+				return nil
+			}
+			return err
+		}
+		product, err := tx.FindByID(ctx, productID)
+		if err != nil {
+			return err
+		}
+
+		schedule, err := findPriceSchedule(ctx, tx, productID, id)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case schedule.Status == ScheduleStatusPending && !schedule.EffectiveFrom.After(now):
+			return startSchedule(ctx, tx, product, schedule, now)
+		case schedule.Status == ScheduleStatusActive && schedule.EffectiveTo != nil && !schedule.EffectiveTo.After(now):
+			return endSchedule(ctx, tx, product, schedule, now)
+		}
+		return nil
+	})
+}
+
+// startSchedule sets the product's price to the scheduled one, remembering the price it replaces.
+// A schedule whose whole window has passed, for instance while the API was down, whose
+// currency is no longer the product's, or whose product has been archived, is skipped.
+func startSchedule(ctx context.Context, tx ProductRepository, product *Product, schedule *PriceSchedule, now time.Time) error {
+	expired := schedule.EffectiveTo != nil && !schedule.EffectiveTo.After(now)
+	if expired || product.Currency != schedule.Currency || product.Status == StatusArchived {
+		schedule.Status = ScheduleStatusSkipped
+		schedule.EndedAt = &now
+		return tx.UpdatePriceSchedule(ctx, schedule)
+	}
+
+	previous := product.Price
+	if err := setScheduledPrice(ctx, tx, product, schedule, schedule.Price); err != nil {
+		return err
+	}
+
+	schedule.PreviousPrice = &previous
+	schedule.AppliedAt = &now
+	schedule.Status = ScheduleStatusActive
+	if schedule.EffectiveTo == nil {
+		schedule.Status = ScheduleStatusCompleted
+		schedule.EndedAt = &now
+	}
+	return tx.UpdatePriceSchedule(ctx, schedule)
+}
+
+// endSchedule restores the price the product had before the schedule started. A price
+// changed by hand while the schedule was active, or the price of an archived product, is kept.
+func endSchedule(ctx context.Context, tx ProductRepository, product *Product, schedule *PriceSchedule, now time.Time) error {
+	unchanged := product.Price.Equal(schedule.Price) && product.Currency == schedule.Currency
+	if unchanged && schedule.PreviousPrice != nil && product.Status != StatusArchived {
+		if err := setScheduledPrice(ctx, tx, product, schedule, *schedule.PreviousPrice); err != nil {
+			return err
+		}
+	}
+
+	schedule.Status = ScheduleStatusCompleted
+	schedule.EndedAt = &now
+	return tx.UpdatePriceSchedule(ctx, schedule)
+}
+
+// setScheduledPrice writes a price set by a schedule and records it in the price history
+// and as a revision made by the schedule's creator.
+func setScheduledPrice(ctx context.Context, tx ProductRepository, product *Product, schedule *PriceSchedule, price decimal.Decimal) error {
+	actorID := uuid.Nil
+	if schedule.CreatedBy != nil {
+		actorID = *schedule.CreatedBy
+	}
+
+	previousPrice := product.Price
+	product.Price = price
+	if err := tx.UpdateFields(ctx, product, []string{"price"}); err != nil {
+		return err
+	}
+	if err := recordPriceChange(ctx, tx, product, previousPrice, product.Currency, actorID, &schedule.ID); err != nil {
+		return err
+	}
+	return recordRevision(ctx, tx, product, RevisionUpdate, actorID)
+}
+
+func findPriceSchedule(ctx context.Context, repo ProductRepository, productID, id uuid.UUID) (*PriceSchedule, error) {
+	schedule, err := repo.FindPriceSchedule(ctx, productID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrScheduleNotFound
+	}
+	return schedule, err
+}
+
+// isOpenSchedule reports whether a schedule has not started or not ended yet.
+func isOpenSchedule(schedule PriceSchedule) bool {
+	return schedule.Status == ScheduleStatusPending || schedule.Status == ScheduleStatusActive
+}
+
+// scheduleCovers reports whether t falls within a schedule's window. A permanent
+// change covers only its start; a temporary one covers [EffectiveFrom, EffectiveTo).
+func scheduleCovers(schedule PriceSchedule, t time.Time) bool {
+	if schedule.EffectiveTo == nil {
+		return t.Equal(schedule.EffectiveFrom)
+	}
+	return !t.Before(schedule.EffectiveFrom) && t.Before(*schedule.EffectiveTo)
+}
+
+// schedulesOverlap reports whether the windows of two schedules share an instant.
+// Both windows include their start, so they overlap if the later start falls in both.
+func schedulesOverlap(a, b PriceSchedule) bool {
+	start := a.EffectiveFrom
+	if b.EffectiveFrom.After(start) {
+		start = b.EffectiveFrom
+	}
+	return scheduleCovers(a, start) && scheduleCovers(b, start)
+}
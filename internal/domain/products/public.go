@@ -0,0 +1,76 @@
+package products
+
+import (
+	"context"
+	"time"
+
+	"go-crud-api/pkg/web"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PublicProduct is the view of a product on sale shown in the public catalogue.
+// It leaves out the owner, stock levels and other internal fields.
+type PublicProduct struct {
+	ID            uuid.UUID       `json:"id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	Price         decimal.Decimal `json:"price" swaggertype:"string" example:"19.90"`
+	Currency      string          `json:"currency" example:"USD"`
+	InStock       bool            `json:"in_stock"`
+	CategoryID    *uuid.UUID      `json:"category_id"`
+	Tags          []Tag           `json:"tags" swaggertype:"array,string" example:"summer,outdoor"`
+	RatingAverage float64         `json:"rating_average" example:"4.5"`
+	RatingCount   int             `json:"rating_count" example:"12"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// publicProductFields are the fields of a public product that can be requested with the fields query parameter.
+var publicProductFields = web.JSONFieldNames(PublicProduct{})
+
+// NewPublicProduct returns the public view of a product.
+func NewPublicProduct(product *Product) PublicProduct {
+	tags := product.Tags
+	if tags == nil {
+		tags = []Tag{}
+	}
+
+	return PublicProduct{
+		ID:            product.ID,
+		Name:          product.Name,
+		Description:   product.Description,
+		Price:         product.Price,
+		Currency:      product.Currency,
+		InStock:       product.Stock > 0,
+		CategoryID:    product.CategoryID,
+		Tags:          tags,
+		RatingAverage: product.RatingAverage,
+		RatingCount:   product.RatingCount,
+		CreatedAt:     product.CreatedAt,
+	}
+}
+
+// ListPublic returns the products on sale matching filter. The owner and status filters do not apply.
+func (s *Service) ListPublic(ctx context.Context, filter ListFilter) ([]Product, error) {
+	filter.OwnerID = nil
+	filter.WarehouseID = nil
+	filter.Statuses = []string{StatusActive, StatusDiscontinued}
+	return s.repo.List(ctx, filter)
+}
+
+// FindPublic finds a product on sale by its ID.
+// Drafts and archived products are reported as gorm.ErrRecordNotFound, like missing ones.
+func (s *Service) FindPublic(ctx context.Context, id uuid.UUID) (*Product, error) {
+	product, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !product.ForSale() {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return product, nil
+}
@@ -2,6 +2,7 @@ package products
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,8 +11,89 @@ import (
  type ProductRepository interface {
 	Create(ctx context.Context, product *Product) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Product, error)
+	// FindByName returns the oldest product of the owner with the given name.
+	FindByName(ctx context.Context, ownerID uuid.UUID, name string) (*Product, error)
+	// FindBySKU returns the owner's product with the given SKU, compared case-insensitively.
+	FindBySKU(ctx context.Context, ownerID uuid.UUID, sku string) (*Product, error)
+	// FindByGTIN returns the owner's product whose barcode, padded to GTIN-14, equals gtin.
+	FindByGTIN(ctx context.Context, ownerID uuid.UUID, gtin string) (*Product, error)
+	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*Product, error)
 	Update(ctx context.Context, product *Product) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context) ([]Product, error)
+	UpdateFields(ctx context.Context, product *Product, fields []string) error
+	Delete(ctx context.Context, id uuid.UUID, version int) error
+	List(ctx context.Context, filter ListFilter) ([]Product, error)
+	// Stream calls fn for each product matching filter, reading rows from a database cursor.
+	Stream(ctx context.Context, filter ListFilter, fn func(product *Product) error) error
+	FindDeletedByID(ctx context.Context, id uuid.UUID) (*Product, error)
+	ListDeleted(ctx context.Context, ownerID *uuid.UUID) ([]Product, error)
+	Restore(ctx context.Context, product *Product) error
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	CreateMovement(ctx context.Context, movement *StockMovement) error
+	ListMovements(ctx context.Context, productID uuid.UUID) ([]StockMovement, error)
+	CreateStockAlert(ctx context.Context, alert *StockAlert) error
+	// ListPendingStockAlerts returns up to limit undelivered alerts tried fewer than maxAttempts times, oldest first, with their owner's email.
+	ListPendingStockAlerts(ctx context.Context, maxAttempts, limit int) ([]StockAlert, error)
+	UpdateStockAlert(ctx context.Context, alert *StockAlert) error
+	// ListLowStock returns the products at or below their reorder threshold, lowest stock first.
+	// A nil ownerID lists the products of every owner.
+	ListLowStock(ctx context.Context, ownerID *uuid.UUID) ([]Product, error)
+	// ReplaceTags sets the product's tags to names, creating missing tags.
+	ReplaceTags(ctx context.Context, product *Product, names []string) error
+	CategoryExists(ctx context.Context, id uuid.UUID) (bool, error)
+	// ListStockLevels returns a product's stock levels with their warehouse, fullest first and then by warehouse code.
+	ListStockLevels(ctx context.Context, productID uuid.UUID) ([]StockLevel, error)
+	// SaveStockLevel creates or updates the quantity of a product held in a warehouse.
+	SaveStockLevel(ctx context.Context, level *StockLevel) error
+	DeleteStockLevel(ctx context.Context, productID, warehouseID uuid.UUID) error
+	WarehouseExists(ctx context.Context, id uuid.UUID) (bool, error)
+	CreateStockTransfer(ctx context.Context, transfer *StockTransfer) error
+	// ListStockTransfers returns the stock moved between warehouses for a product, most recent first.
+	ListStockTransfers(ctx context.Context, productID uuid.UUID) ([]StockTransfer, error)
+	CreateVariant(ctx context.Context, variant *Variant) error
+	FindVariant(ctx context.Context, productID, id uuid.UUID) (*Variant, error)
+	// UpdateVariant writes a variant if it is still at its current version and bumps the version.
+	UpdateVariant(ctx context.Context, variant *Variant) error
+	DeleteVariant(ctx context.Context, productID, id uuid.UUID, version int) error
+	ListVariants(ctx context.Context, productID uuid.UUID) ([]Variant, error)
+	// SumVariantStock returns the total stock of a product's variants and how many there are.
+	SumVariantStock(ctx context.Context, productID uuid.UUID) (total int, count int64, err error)
+	// SumReservedStock returns the stock of a product held by unexpired cart reservations of users other than exceptUserID.
+	SumReservedStock(ctx context.Context, productID, exceptUserID uuid.UUID) (int, error)
+	CreateImage(ctx context.Context, image *Image) error
+	FindImage(ctx context.Context, productID, id uuid.UUID) (*Image, error)
+	// ListImages returns a product's images ordered by position.
+	ListImages(ctx context.Context, productID uuid.UUID) ([]Image, error)
+	DeleteImage(ctx context.Context, productID, id uuid.UUID) error
+	// SetImagePositions numbers the given images of a product 0, 1, 2... in order.
+	SetImagePositions(ctx context.Context, productID uuid.UUID, ids []uuid.UUID) error
+	// ListImagesDeletedBefore returns the images of products moved to the trash before cutoff.
+	ListImagesDeletedBefore(ctx context.Context, cutoff time.Time) ([]Image, error)
+	// CreateRevision stores revision as the product's next revision, setting its number.
+	// It must run after the product row has been written in the same transaction, which serialises numbering.
+	CreateRevision(ctx context.Context, revision *Revision) error
+	// ListRevisions returns a product's revisions, newest first.
+	ListRevisions(ctx context.Context, productID uuid.UUID) ([]Revision, error)
+	FindRevision(ctx context.Context, productID uuid.UUID, revision int) (*Revision, error)
+	// CreatePriceSchedule stores a schedule, returning ErrScheduleOverlap if it overlaps another open schedule of the product.
+	CreatePriceSchedule(ctx context.Context, schedule *PriceSchedule) error
+	FindPriceSchedule(ctx context.Context, productID, id uuid.UUID) (*PriceSchedule, error)
+	// ListPriceSchedules returns a product's schedules ordered by start.
+	ListPriceSchedules(ctx context.Context, productID uuid.UUID) ([]PriceSchedule, error)
+	UpdatePriceSchedule(ctx context.Context, schedule *PriceSchedule) error
+	// ListDuePriceSchedules returns the pending schedules starting and the active schedules ending at or before now.
+	ListDuePriceSchedules(ctx context.Context, now time.Time) ([]PriceSchedule, error)
+	CreatePriceChange(ctx context.Context, change *PriceChange) error
+	// ListPriceChanges returns a product's price history, newest first.
+	ListPriceChanges(ctx context.Context, productID uuid.UUID) ([]PriceChange, error)
+	// ListOwners returns the public profiles of the users with the given IDs.
+	ListOwners(ctx context.Context, ids []uuid.UUID) ([]Owner, error)
+	// ListCollaborators returns a product's collaborators, oldest first.
+	ListCollaborators(ctx context.Context, productID uuid.UUID) ([]Collaborator, error)
+	FindCollaborator(ctx context.Context, productID, userID uuid.UUID) (*Collaborator, error)
+	// SaveCollaborator adds a collaborator, or changes the role of an existing one.
+	SaveCollaborator(ctx context.Context, collaborator *Collaborator) error
+	DeleteCollaborator(ctx context.Context, productID, userID uuid.UUID) error
+	// Transaction runs fn inside a database transaction, passing a repository bound to it.
+	Transaction(ctx context.Context, fn func(repo ProductRepository) error) error
 	// TODO: Add List method with filters and pagination
 }
@@ -0,0 +1,221 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// snapshotOf captures the editable state of a product.
+func snapshotOf(product *Product) ProductSnapshot {
+	tags := make([]string, len(product.Tags))
+	for i, tag := range product.Tags {
+		tags[i] = tag.Name
+	}
+
+	return ProductSnapshot{
+		Name:             product.Name,
+		Description:      product.Description,
+		SKU:              product.SKU,
+		GTIN:             product.GTIN,
+		Price:            product.Price,
+		Currency:         product.Currency,
+		Stock:            product.Stock,
+		CategoryID:       product.CategoryID,
+		Tags:             tags,
+		Status:           product.Status,
+		ReorderThreshold: product.ReorderThreshold,
+		OwnerID:          &product.OwnerID,
+		Version:          product.Version,
+	}
+}
+
+// recordRevision stores a revision of the product's current state made by actorID.
+// A nil actorID records a change made by the system, such as a price schedule.
+func recordRevision(ctx context.Context, repo ProductRepository, product *Product, action string, actorID uuid.UUID) error {
+	return repo.CreateRevision(ctx, &Revision{
+		ProductID: product.ID,
+		Action:    action,
+		Snapshot:  snapshotOf(product),
+		ActorID:   actorRef(actorID),
+	})
+}
+
+// actorRef returns a reference to actorID, or nil for uuid.Nil.
+func actorRef(actorID uuid.UUID) *uuid.UUID {
+	if actorID == uuid.Nil {
+		return nil
+	}
+	return &actorID
+}
+
+// ListRevisions returns the revisions of a product, newest first.
+func (s *Service) ListRevisions(ctx context.Context, productID uuid.UUID) ([]Revision, error) {
+	return s.repo.ListRevisions(ctx, productID)
+}
+
+// findRevision returns a revision of a product, or ErrRevisionNotFound.
+func findRevision(ctx context.Context, repo ProductRepository, productID uuid.UUID, revision int) (*Revision, error) {
+	rev, err := repo.FindRevision(ctx, productID, revision)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+// DiffRevisions returns the fields that changed between two revisions of a product.
+func (s *Service) DiffRevisions(ctx context.Context, productID uuid.UUID, from, to int) ([]FieldChange, error) {
+	fromRev, err := findRevision(ctx, s.repo, productID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := findRevision(ctx, s.repo, productID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffSnapshots(fromRev.Snapshot, toRev.Snapshot), nil
+}
+
+// diffSnapshots lists the fields that differ between two snapshots, in a fixed order.
+// Tags are compared as sets; the version is not compared, nor the status or the owner
+// when either snapshot predates them being recorded.
+func diffSnapshots(from, to ProductSnapshot) []FieldChange {
+	changes := []FieldChange{}
+	if from.Name != to.Name {
+		changes = append(changes, FieldChange{Field: "name", From: from.Name, To: to.Name})
+	}
+	if from.Description != to.Description {
+		changes = append(changes, FieldChange{Field: "description", From: from.Description, To: to.Description})
+	}
+	if !equalRefs(from.SKU, to.SKU) {
+		changes = append(changes, FieldChange{Field: "sku", From: from.SKU, To: to.SKU})
+	}
+	if !equalRefs(from.GTIN, to.GTIN) {
+		changes = append(changes, FieldChange{Field: "gtin", From: from.GTIN, To: to.GTIN})
+	}
+	if !from.Price.Equal(to.Price) {
+		changes = append(changes, FieldChange{Field: "price", From: from.Price, To: to.Price})
+	}
+	if from.Currency != to.Currency {
+		changes = append(changes, FieldChange{Field: "currency", From: from.Currency, To: to.Currency})
+	}
+	if from.Stock != to.Stock {
+		changes = append(changes, FieldChange{Field: "stock", From: from.Stock, To: to.Stock})
+	}
+	if !equalRefs(from.ReorderThreshold, to.ReorderThreshold) {
+		changes = append(changes, FieldChange{Field: "reorder_threshold", From: from.ReorderThreshold, To: to.ReorderThreshold})
+	}
+	if !equalIDs(from.CategoryID, to.CategoryID) {
+		changes = append(changes, FieldChange{Field: "category_id", From: from.CategoryID, To: to.CategoryID})
+	}
+	if from.Status != "" && to.Status != "" && from.Status != to.Status {
+		changes = append(changes, FieldChange{Field: "status", From: from.Status, To: to.Status})
+	}
+	if from.OwnerID != nil && to.OwnerID != nil && *from.OwnerID != *to.OwnerID {
+		changes = append(changes, FieldChange{Field: "owner_id", From: from.OwnerID, To: to.OwnerID})
+	}
+
+	fromTags, toTags := slices.Sorted(slices.Values(from.Tags)), slices.Sorted(slices.Values(to.Tags))
+	if !slices.Equal(fromTags, toTags) {
+		changes = append(changes, FieldChange{Field: "tags", From: from.Tags, To: to.Tags})
+	}
+
+	return changes
+}
+
+func equalIDs(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalRefs[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Rollback restores a product's fields, codes, category and tags to their state at a revision
+// and records the result as a new revision made by actorID; history is never rewritten.
+// version is the version the caller expects the product to be at; zero skips the precondition.
+// The status, reorder threshold and owner are not restored. The stock of a product with variants or stock levels stays the sum
+// of theirs, and a category deleted since the revision is left unset. A SKU or barcode that
+// another of the owner's products has taken since fails the rollback with ErrDuplicateCode.
+func (s *Service) Rollback(ctx context.Context, id uuid.UUID, version int, revision int, actorID uuid.UUID) (*Product, error) {
+	rev, err := findRevision(ctx, s.repo, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := rev.Snapshot
+
+	if err := s.checkPrice(snapshot.Price, snapshot.Currency); err != nil {
+		return nil, err
+	}
+
+	product, err := findEditable(ctx, s.repo, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		previousStock := product.Stock
+		_, variants, err := tx.SumVariantStock(ctx, id)
+		if err != nil {
+			return err
+		}
+		levels, err := tx.ListStockLevels(ctx, id)
+		if err != nil {
+			return err
+		}
+		if variants == 0 && len(levels) == 0 {
+			if err := checkStockIncrease(product, snapshot.Stock); err != nil {
+				return err
+			}
+			product.Stock = snapshot.Stock
+		}
+
+		product.CategoryID = nil
+		if snapshot.CategoryID != nil {
+			exists, err := tx.CategoryExists(ctx, *snapshot.CategoryID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				product.CategoryID = snapshot.CategoryID
+			}
+		}
+
+		previousPrice, previousCurrency := product.Price, product.Currency
+		product.Name = snapshot.Name
+		product.Description = snapshot.Description
+		product.SKU = snapshot.SKU
+		product.GTIN = snapshot.GTIN
+		product.Price = snapshot.Price
+		product.Currency = snapshot.Currency
+
+		if err := tx.Update(ctx, product); err != nil {
+			return translateCodeError(err)
+		}
+		if err := tx.ReplaceTags(ctx, product, snapshot.Tags); err != nil {
+			return err
+		}
+		if err := recordCorrection(ctx, tx, product, previousStock, actorID, correctionNoteRollback); err != nil {
+			return err
+		}
+		if err := recordPriceChange(ctx, tx, product, previousPrice, previousCurrency, actorID, nil); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, product, RevisionRollback, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
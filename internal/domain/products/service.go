@@ -2,67 +2,964 @@ package products
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-crud-api/internal/config"
+	"go-crud-api/pkg/barcode"
+	"go-crud-api/pkg/money"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
+// defaultTrashRetention is used when TRASH_RETENTION is unset or invalid.
+const defaultTrashRetention = 30 * 24 * time.Hour
+
 // Service defines the product service.
 type Service struct {
-	repo ProductRepository
+	repo   ProductRepository
+	config config.Config
 }
 
 // NewService creates a new product service.
-func NewService(repo ProductRepository) *Service {
-	return &Service{repo: repo}
+func NewService(repo ProductRepository, config config.Config) *Service {
+	return &Service{repo: repo, config: config}
 }
 
-// Create creates a new product.
-func (s *Service) Create(ctx context.Context, name, description string, price float64, stock int, ownerID uuid.UUID) (*Product, error) {
+// Create creates a new product and records its first revision.
+// An empty currency defaults to the configured default currency.
+func (s *Service) Create(ctx context.Context, input ProductInput, ownerID uuid.UUID) (*Product, error) {
+	var product *Product
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		var err error
+		product, err = s.create(ctx, tx, input, ownerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *Service) create(ctx context.Context, repo ProductRepository, input ProductInput, ownerID uuid.UUID) (*Product, error) {
+	if input.Currency == "" {
+		input.Currency = s.defaultCurrency()
+	}
+
+	if err := s.checkPrice(input.Price, input.Currency); err != nil {
+		return nil, err
+	}
+
 	product := &Product{
-		Name:        name,
-		Description: description,
-		Price:       price,
-		Stock:       stock,
+		Name:        input.Name,
+		Description: input.Description,
+		SKU:         optionalCode(input.SKU),
+		GTIN:        optionalCode(input.GTIN),
+		Price:       input.Price,
+		Currency:    input.Currency,
+		Stock:       input.Stock,
+		Status:      StatusDraft,
 		OwnerID:     ownerID,
 	}
 
-	if err := s.repo.Create(ctx, product); err != nil {
+	if err := repo.Create(ctx, product); err != nil {
+		return nil, translateCodeError(err)
+	}
+
+	if err := recordRevision(ctx, repo, product, RevisionCreate, ownerID); err != nil {
+		return nil, err
+	}
+	if err := recordPriceChange(ctx, repo, product, decimal.Zero, "", ownerID, nil); err != nil {
 		return nil, err
 	}
 
 	return product, nil
 }
 
+// FindByName finds the oldest of the owner's products with the given name.
+func (s *Service) FindByName(ctx context.Context, ownerID uuid.UUID, name string) (*Product, error) {
+	return s.repo.FindByName(ctx, ownerID, name)
+}
+
+// FindBySKU finds the owner's product with the given SKU, compared case-insensitively.
+func (s *Service) FindBySKU(ctx context.Context, ownerID uuid.UUID, sku string) (*Product, error) {
+	return s.repo.FindBySKU(ctx, ownerID, sku)
+}
+
+// FindByBarcode finds the owner's product with the given GTIN-8, UPC-A, EAN-13 or GTIN-14 barcode.
+// Codes are compared as GTIN-14, so a UPC-A code finds a product stored with its EAN-13 form.
+func (s *Service) FindByBarcode(ctx context.Context, ownerID uuid.UUID, code string) (*Product, error) {
+	return s.repo.FindByGTIN(ctx, ownerID, barcode.Normalize(code))
+}
+
 // FindByID finds a product by its ID.
 func (s *Service) FindByID(ctx context.Context, id uuid.UUID) (*Product, error) {
 	return s.repo.FindByID(ctx, id)
 }
 
 // Update updates a product.
-func (s *Service) Update(ctx context.Context, id uuid.UUID, name, description string, price float64, stock int) (*Product, error) {
-	product, err := s.repo.FindByID(ctx, id)
+// version is the version the caller expects the product to be at; zero skips the precondition.
+// An empty currency keeps the product's current currency. A stock change is recorded
+// in the movement ledger as a correction made by actorID.
+func (s *Service) Update(ctx context.Context, id uuid.UUID, version int, input ProductInput, actorID uuid.UUID) (*Product, error) {
+	product, err := findEditable(ctx, s.repo, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.update(ctx, s.repo, product, input, actorID); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (s *Service) update(ctx context.Context, repo ProductRepository, product *Product, input ProductInput, actorID uuid.UUID) error {
+	if input.Currency == "" {
+		input.Currency = product.Currency
+	}
+
+	if err := s.checkPrice(input.Price, input.Currency); err != nil {
+		return err
+	}
+	if err := checkStockIncrease(product, input.Stock); err != nil {
+		return err
+	}
+
+	previousStock := product.Stock
+	previousPrice, previousCurrency := product.Price, product.Currency
+	product.Name = input.Name
+	product.Description = input.Description
+	product.SKU = optionalCode(input.SKU)
+	product.GTIN = optionalCode(input.GTIN)
+	product.Price = input.Price
+	product.Currency = input.Currency
+	product.Stock = input.Stock
+
+	return repo.Transaction(ctx, func(tx ProductRepository) error {
+		if product.Stock != previousStock {
+			if err := checkStockEditable(ctx, tx, product.ID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Update(ctx, product); err != nil {
+			return translateCodeError(err)
+		}
+		if err := recordCorrection(ctx, tx, product, previousStock, actorID, correctionNoteProductEdit); err != nil {
+			return err
+		}
+		if err := recordPriceChange(ctx, tx, product, previousPrice, previousCurrency, actorID, nil); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, product, RevisionUpdate, actorID)
+	})
+}
+
+// Patch applies a partial update to a product, writing only the supplied fields.
+// version is the version the caller expects the product to be at; zero skips the precondition.
+// A stock change is recorded in the movement ledger as a correction made by actorID.
+func (s *Service) Patch(ctx context.Context, id uuid.UUID, version int, patch ProductPatch, actorID uuid.UUID) (*Product, error) {
+	product, err := findEditable(ctx, s.repo, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Stock != nil {
+		if err := checkStockIncrease(product, *patch.Stock); err != nil {
+			return nil, err
+		}
+	}
+
+	previousStock := product.Stock
+	previousPrice, previousCurrency := product.Price, product.Currency
+	var fields []string
+	if patch.Name != nil {
+		product.Name = *patch.Name
+		fields = append(fields, "name")
+	}
+	if patch.Description != nil {
+		product.Description = *patch.Description
+		fields = append(fields, "description")
+	}
+	if patch.SKU != nil {
+		product.SKU = optionalCode(*patch.SKU)
+		fields = append(fields, "sku")
+	}
+	if patch.GTIN != nil {
+		product.GTIN = optionalCode(*patch.GTIN)
+		fields = append(fields, "gtin")
+	}
+	if patch.Price != nil {
+		product.Price = *patch.Price
+		fields = append(fields, "price")
+	}
+	if patch.Currency != nil {
+		product.Currency = *patch.Currency
+		fields = append(fields, "currency")
+	}
+	if patch.Stock != nil {
+		product.Stock = *patch.Stock
+		fields = append(fields, "stock")
+	}
+
+	if len(fields) == 0 {
+		return product, nil
+	}
+
+	if patch.Price != nil || patch.Currency != nil {
+		if err := s.checkPrice(product.Price, product.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		if product.Stock != previousStock {
+			if err := checkStockEditable(ctx, tx, product.ID); err != nil {
+				return err
+			}
+		}
+		if err := tx.UpdateFields(ctx, product, fields); err != nil {
+			return translateCodeError(err)
+		}
+		if err := recordCorrection(ctx, tx, product, previousStock, actorID, correctionNoteProductEdit); err != nil {
+			return err
+		}
+		if err := recordPriceChange(ctx, tx, product, previousPrice, previousCurrency, actorID, nil); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, product, RevisionUpdate, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Delete moves a product to the trash, recording a revision of its state at deletion made by actorID.
+// version is the version the caller expects the product to be at; zero skips the precondition.
+func (s *Service) Delete(ctx context.Context, id uuid.UUID, version int, actorID uuid.UUID) error {
+	return s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		product, err := tx.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+		return deleteProduct(ctx, tx, product, version, actorID)
+	})
+}
+
+func deleteProduct(ctx context.Context, repo ProductRepository, product *Product, version int, actorID uuid.UUID) error {
+	if err := repo.Delete(ctx, product.ID, version); err != nil {
+		return err
+	}
+	return recordRevision(ctx, repo, product, RevisionDelete, actorID)
+}
+
+// SetCategory assigns a product to a category, or removes it from its category when categoryID is nil.
+// version is the version the caller expects the product to be at; zero skips the precondition.
+func (s *Service) SetCategory(ctx context.Context, id uuid.UUID, version int, categoryID *uuid.UUID, actorID uuid.UUID) (*Product, error) {
+	product, err := findEditable(ctx, s.repo, id, version)
 	if err != nil {
 		return nil, err
 	}
 
-	product.Name = name
-	product.Description = description
-	product.Price = price
+	if categoryID != nil {
+		exists, err := s.repo.CategoryExists(ctx, *categoryID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrCategoryNotFound
+		}
+	}
+
+	product.CategoryID = categoryID
+	err = s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		if err := tx.UpdateFields(ctx, product, []string{"category_id"}); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, product, RevisionUpdate, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// SetTags replaces a product's tags. Names are trimmed, lowercased and deduplicated.
+// version is the version the caller expects the product to be at; zero skips the precondition.
+func (s *Service) SetTags(ctx context.Context, id uuid.UUID, version int, names []string, actorID uuid.UUID) (*Product, error) {
+	names, err := NormalizeTags(names)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := findEditable(ctx, s.repo, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Changing tags bumps the product version like any other edit.
+	err = s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		if err := tx.UpdateFields(ctx, product, nil); err != nil {
+			return err
+		}
+		if err := tx.ReplaceTags(ctx, product, names); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, product, RevisionUpdate, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// maxTags caps the number of tags on a product.
+const maxTags = 20
+
+// NormalizeTags trims, lowercases and deduplicates tag names, keeping their order.
+func NormalizeTags(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || utf8.RuneCountInString(name) > 50 {
+			return nil, ErrInvalidTag
+		}
+		if !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+
+	if len(normalized) > maxTags {
+		return nil, ErrInvalidTag
+	}
+	return normalized, nil
+}
+
+// AdjustStock applies a relative stock change under a row lock and records it in the movement ledger.
+// Stock never goes below zero; sales must decrease stock and restocks and returns must increase it.
+// A change crossing the product's reorder threshold raises a low-stock alert.
+func (s *Service) AdjustStock(ctx context.Context, id uuid.UUID, adjustment StockAdjustment, actorID uuid.UUID) (*Product, *StockMovement, error) {
+	if err := checkAdjustment(adjustment); err != nil {
+		return nil, nil, err
+	}
+
+	var product *Product
+	var movement *StockMovement
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		var err error
+		product, movement, err = ChangeStock(ctx, tx, id, adjustment, actorID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, movement, nil
+}
+
+// ChangeStock applies a stock change inside the caller's transaction: the product row is locked,
+// the change is recorded in the movement ledger and the reorder threshold is checked.
+// It is how other domains, such as orders, consume and return stock. A change that would take
+// stock below zero, or into stock reserved by others, fails with an *InsufficientStockError.
+// Only products for sale can be sold, archived products cannot change, and discontinued
+// products only take stock back through returns. For a product kept in warehouses, the change
+// is made in the adjustment's warehouse, or allocated by allocateStock when none is given.
+func ChangeStock(ctx context.Context, tx ProductRepository, id uuid.UUID, adjustment StockAdjustment, actorID uuid.UUID) (*Product, *StockMovement, error) {
+	product, err := tx.FindByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if adjustment.Reason == ReasonSale && !product.ForSale() {
+		return nil, nil, ErrNotForSale
+	}
+	if err := checkEditable(product); err != nil {
+		return nil, nil, err
+	}
+	if adjustment.Reason != ReasonReturn {
+		if err := checkStockIncrease(product, product.Stock+adjustment.Delta); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := checkNoVariants(ctx, tx, product.ID); err != nil {
+		return nil, nil, err
+	}
+	levels, err := tx.ListStockLevels(ctx, product.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if adjustment.WarehouseID != nil && len(levels) == 0 {
+		return nil, nil, ErrNoStockLevels
+	}
+	if err := checkWarehouse(ctx, tx, levels, adjustment.WarehouseID); err != nil {
+		return nil, nil, err
+	}
+
+	available := product.Stock
+	if adjustment.RespectReservations && adjustment.Delta < 0 {
+		reserved, err := tx.SumReservedStock(ctx, product.ID, actorID)
+		if err != nil {
+			return nil, nil, err
+		}
+		available -= reserved
+	}
+
+	if available+adjustment.Delta < 0 {
+		return nil, nil, &InsufficientStockError{Available: max(available, 0)}
+	}
+
+	var warehouseID *uuid.UUID
+	if len(levels) > 0 {
+		changed, err := allocateStock(product.ID, levels, adjustment.Delta, adjustment.WarehouseID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, level := range changed {
+			if err := tx.SaveStockLevel(ctx, &level); err != nil {
+				return nil, nil, translateWarehouseError(err)
+			}
+		}
+		if len(changed) == 1 {
+			warehouseID = &changed[0].WarehouseID
+		}
+	}
+
+	stock := product.Stock + adjustment.Delta
+	previousStock := product.Stock
 	product.Stock = stock
+	if err := tx.UpdateFields(ctx, product, []string{"stock"}); err != nil {
+		return nil, nil, err
+	}
+
+	movement := &StockMovement{
+		ProductID:   product.ID,
+		Delta:       adjustment.Delta,
+		Reason:      adjustment.Reason,
+		StockAfter:  stock,
+		ActorID:     &actorID,
+		WarehouseID: warehouseID,
+		Note:        adjustment.Note,
+	}
+	if err := tx.CreateMovement(ctx, movement); err != nil {
+		return nil, nil, err
+	}
+	if err := checkLowStock(ctx, tx, product, previousStock); err != nil {
+		return nil, nil, err
+	}
+
+	return product, movement, nil
+}
 
-	if err := s.repo.Update(ctx, product); err != nil {
+// TransferOwnership gives a product of fromUserID to toUserID inside the caller's transaction.
+// It is how the transfers domain moves products: the product row is locked, the version is bumped
+// and the change is recorded as a revision made by actorID. It reports false, changing nothing,
+// when the product no longer belongs to fromUserID, is in the trash or is archived.
+// A product whose SKU or barcode the new owner already uses fails with ErrDuplicateCode.
+func TransferOwnership(ctx context.Context, tx ProductRepository, id, fromUserID, toUserID, actorID uuid.UUID) (bool, error) {
+	if _, err := tx.FindByIDForUpdate(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	// Read again with the tags, which the revision snapshots.
+	product, err := tx.FindByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if product.OwnerID != fromUserID || checkEditable(product) != nil {
+		return false, nil
+	}
+
+	product.OwnerID = toUserID
+	if err := tx.UpdateFields(ctx, product, []string{"owner_id"}); err != nil {
+		return false, translateCodeError(err)
+	}
+	if err := recordRevision(ctx, tx, product, RevisionUpdate, actorID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListVariants returns the variants of a product, ordered by SKU.
+func (s *Service) ListVariants(ctx context.Context, productID uuid.UUID) ([]Variant, error) {
+	if _, err := s.repo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVariants(ctx, productID)
+}
+
+// FindVariant finds a variant of a product by its ID.
+func (s *Service) FindVariant(ctx context.Context, productID, id uuid.UUID) (*Variant, error) {
+	return s.repo.FindVariant(ctx, productID, id)
+}
+
+// CreateVariant adds a variant to a product. The product's stock becomes the sum of its
+// variants' stock, and the change is recorded in the movement ledger as a correction by actorID.
+func (s *Service) CreateVariant(ctx context.Context, productID uuid.UUID, input VariantInput, actorID uuid.UUID) (*Variant, error) {
+	variant := &Variant{ProductID: productID}
+
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		product, err := tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+		if err := checkNoStockLevels(ctx, tx, productID); err != nil {
+			return err
+		}
+		if err := s.applyVariant(product, variant, input); err != nil {
+			return err
+		}
+		if err := tx.CreateVariant(ctx, variant); err != nil {
+			return translateVariantError(err)
+		}
+		return syncVariantStock(ctx, tx, product, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+// UpdateVariant updates a variant and recalculates the product's stock.
+// version is the version the caller expects the variant to be at; zero skips the precondition.
+func (s *Service) UpdateVariant(ctx context.Context, productID, id uuid.UUID, version int, input VariantInput, actorID uuid.UUID) (*Variant, error) {
+	var variant *Variant
+
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		product, err := tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+
+		variant, err = tx.FindVariant(ctx, productID, id)
+		if err != nil {
+			return err
+		}
+		if version != 0 && variant.Version != version {
+			return ErrVersionMismatch
+		}
+
+		if err := s.applyVariant(product, variant, input); err != nil {
+			return err
+		}
+		if err := tx.UpdateVariant(ctx, variant); err != nil {
+			return translateVariantError(err)
+		}
+		return syncVariantStock(ctx, tx, product, actorID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	return variant, nil
+}
+
+// DeleteVariant deletes a variant and recalculates the product's stock.
+// version is the version the caller expects the variant to be at; zero skips the precondition.
+func (s *Service) DeleteVariant(ctx context.Context, productID, id uuid.UUID, version int, actorID uuid.UUID) error {
+	return s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		product, err := tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+		if err := tx.DeleteVariant(ctx, productID, id, version); err != nil {
+			return err
+		}
+		return syncVariantStock(ctx, tx, product, actorID)
+	})
+}
+
+// applyVariant validates input against the product and copies it onto variant.
+func (s *Service) applyVariant(product *Product, variant *Variant, input VariantInput) error {
+	if input.Price != nil && !money.FitsCurrency(*input.Price, product.Currency) {
+		return ErrInvalidPriceScale
+	}
+
+	variant.SKU = input.SKU
+	variant.Options = input.Options
+	variant.Price = input.Price
+	variant.Stock = input.Stock
+	return nil
+}
+
+// syncVariantStock sets a product's stock to the sum of its variants' stock.
+// The change bumps the product version and is recorded in the movement ledger.
+func syncVariantStock(ctx context.Context, tx ProductRepository, product *Product, actorID uuid.UUID) error {
+	total, _, err := tx.SumVariantStock(ctx, product.ID)
+	if err != nil {
+		return err
+	}
+	if total == product.Stock {
+		return nil
+	}
+	if err := checkStockIncrease(product, total); err != nil {
+		return err
+	}
+
+	previousStock := product.Stock
+	product.Stock = total
+	if err := tx.UpdateFields(ctx, product, []string{"stock"}); err != nil {
+		return err
+	}
+	return recordCorrection(ctx, tx, product, previousStock, actorID, correctionNoteVariants)
+}
+
+// translateVariantError maps a unique violation on the SKU or options to ErrDuplicateVariant.
+func translateVariantError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrDuplicateVariant
+	}
+	return err
+}
+
+// translateCodeError maps a unique violation on the SKU or barcode to ErrDuplicateCode.
+func translateCodeError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrDuplicateCode
+	}
+	return err
+}
+
+// optionalCode returns nil for an empty SKU or barcode, which clears it.
+func optionalCode(code string) *string {
+	if code == "" {
+		return nil
+	}
+	return &code
+}
+
+// ListMovements returns a product's stock movement history, most recent first.
+func (s *Service) ListMovements(ctx context.Context, productID uuid.UUID) ([]StockMovement, error) {
+	return s.repo.ListMovements(ctx, productID)
+}
+
+// checkAdjustment ensures the sign of the delta matches the adjustment reason.
+func checkAdjustment(adjustment StockAdjustment) error {
+	switch adjustment.Reason {
+	case ReasonSale:
+		if adjustment.Delta >= 0 {
+			return ErrInvalidAdjustment
+		}
+	case ReasonRestock, ReasonReturn:
+		if adjustment.Delta <= 0 {
+			return ErrInvalidAdjustment
+		}
+	case ReasonCorrection:
+		if adjustment.Delta == 0 {
+			return ErrInvalidAdjustment
+		}
+	default:
+		return ErrInvalidAdjustment
+	}
+	return nil
+}
+
+// Notes of the corrections recorded when stock changes outside of an adjustment.
+const (
+	correctionNoteProductEdit = "Stock edited on product update"
+	correctionNoteVariants    = "Stock recalculated from variants"
+	correctionNoteRollback    = "Stock restored by rollback"
+	correctionNoteStockCount  = "Stock counted in warehouse"
+)
+
+// recordCorrection adds a correction to the movement ledger when an edit changed a product's stock,
+// and raises a low-stock alert if the change crossed the product's reorder threshold.
+func recordCorrection(ctx context.Context, tx ProductRepository, product *Product, previousStock int, actorID uuid.UUID, note string) error {
+	if product.Stock == previousStock {
+		return nil
+	}
+
+	err := tx.CreateMovement(ctx, &StockMovement{
+		ProductID:  product.ID,
+		Delta:      product.Stock - previousStock,
+		Reason:     ReasonCorrection,
+		StockAfter: product.Stock,
+		ActorID:    &actorID,
+		Note:       note,
+	})
+	if err != nil {
+		return err
+	}
+	return checkLowStock(ctx, tx, product, previousStock)
+}
+
+// checkStockEditable rejects direct stock changes on a product that has variants or stock levels.
+func checkStockEditable(ctx context.Context, tx ProductRepository, productID uuid.UUID) error {
+	if err := checkNoVariants(ctx, tx, productID); err != nil {
+		return err
+	}
+	return checkNoStockLevels(ctx, tx, productID)
+}
+
+// checkNoVariants rejects changes that need a product's stock not to be kept in variants.
+func checkNoVariants(ctx context.Context, tx ProductRepository, productID uuid.UUID) error {
+	_, count, err := tx.SumVariantStock(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrStockManagedByVariants
+	}
+	return nil
+}
+
+// checkPrice ensures the currency is allowed and the price fits its minor unit.
+func (s *Service) checkPrice(price decimal.Decimal, currency string) error {
+	if allowed := money.ParseCurrencies(s.config.AllowedCurrencies); len(allowed) > 0 && !slices.Contains(allowed, currency) {
+		return ErrUnsupportedCurrency
+	}
+
+	if !money.FitsCurrency(price, currency) {
+		return ErrInvalidPriceScale
+	}
+
+	return nil
+}
+
+// defaultCurrency returns the currency used when a product is created without one.
+func (s *Service) defaultCurrency() string {
+	if s.config.DefaultCurrency == "" {
+		return "USD"
+	}
+	return strings.ToUpper(s.config.DefaultCurrency)
+}
+
+// findAtVersion loads a product and checks it is still at the expected version.
+func findAtVersion(ctx context.Context, repo ProductRepository, id uuid.UUID, version int) (*Product, error) {
+	product, err := repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 0 && product.Version != version {
+		return nil, ErrVersionMismatch
+	}
+
 	return product, nil
 }
 
-// Delete deletes a product.
-func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+// findModifiable loads a product and checks the actor owns it, edits it as a collaborator or is an admin.
+func findModifiable(ctx context.Context, repo ProductRepository, actor Actor, id uuid.UUID, version int) (*Product, error) {
+	product, err := findEditable(ctx, repo, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := canAccess(ctx, repo, actor, product, CollaboratorEditor)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrForbidden
+	}
+
+	return product, nil
+}
+
+// Batch applies create, update and delete operations on behalf of actor, enforcing the
+// same ownership rules as the single-product endpoints for every operation.
+// In atomic mode all operations share one transaction and nothing is kept if any of them
+// fails: failed operations report their own error, the others report ErrBatchRolledBack,
+// and Batch returns ErrBatchRolledBack. In best-effort mode each operation is applied on its own.
+func (s *Service) Batch(ctx context.Context, actor Actor, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	results := make([]BatchResult, len(ops))
+
+	if !atomic {
+		for i, op := range ops {
+			results[i] = s.applyOperation(ctx, s.repo, actor, op)
+		}
+		return results, nil
+	}
+
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		if s.applyInSavepoints(ctx, tx, actor, ops, results) {
+			return ErrBatchRolledBack
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = BatchResult{Err: ErrBatchRolledBack}
+			}
+		}
+		return results, err
+	}
+
+	return results, nil
+}
+
+// DryRunBatch reports what an atomic Batch would do without keeping any change:
+// the operations run in a transaction that is always rolled back.
+func (s *Service) DryRunBatch(ctx context.Context, actor Actor, ops []BatchOperation) ([]BatchResult, error) {
+	results := make([]BatchResult, len(ops))
+
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		s.applyInSavepoints(ctx, tx, actor, ops, results)
+		return errDryRun
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// applyInSavepoints applies ops inside tx, each in its own savepoint, so a failure does not
+// abort the transaction and the remaining operations still report their own outcome.
+// It reports whether any operation failed.
+func (s *Service) applyInSavepoints(ctx context.Context, tx ProductRepository, actor Actor, ops []BatchOperation, results []BatchResult) bool {
+	failed := false
+	for i, op := range ops {
+		_ = tx.Transaction(ctx, func(sp ProductRepository) error {
+			results[i] = s.applyOperation(ctx, sp, actor, op)
+			return results[i].Err
+		})
+		if results[i].Err != nil {
+			failed = true
+		}
+	}
+	return failed
+}
+
+// applyOperation applies a single batch operation using repo.
+func (s *Service) applyOperation(ctx context.Context, repo ProductRepository, actor Actor, op BatchOperation) BatchResult {
+	switch op.Op {
+	case BatchCreate:
+		product, err := s.create(ctx, repo, op.Input, actor.UserID)
+		return BatchResult{Product: product, Err: err}
+	case BatchUpdate:
+		product, err := findModifiable(ctx, repo, actor, op.ID, op.Version)
+		if err != nil {
+			return BatchResult{Err: err}
+		}
+		if err := s.update(ctx, repo, product, op.Input, actor.UserID); err != nil {
+			return BatchResult{Err: err}
+		}
+		return BatchResult{Product: product}
+	case BatchDelete:
+		product, err := findModifiable(ctx, repo, actor, op.ID, op.Version)
+		if err != nil {
+			return BatchResult{Err: err}
+		}
+		return BatchResult{Err: deleteProduct(ctx, repo, product, product.Version, actor.UserID)}
+	default:
+		return BatchResult{Err: ErrUnknownBatchOperation}
+	}
+}
+
+// List returns the products matching filter.
+func (s *Service) List(ctx context.Context, filter ListFilter) ([]Product, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// ownerBatchSize is the number of owners loaded per query when expanding products.
+const ownerBatchSize = 500
+
+// ExpandOwners sets the Owner of each product, loading the distinct owners in batches
+// rather than one query per product. Products whose owner no longer exists are left without one.
+func (s *Service) ExpandOwners(ctx context.Context, products []Product) error {
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, product := range products {
+		if !seen[product.OwnerID] {
+			seen[product.OwnerID] = true
+			ids = append(ids, product.OwnerID)
+		}
+	}
+
+	owners := make(map[uuid.UUID]*Owner, len(ids))
+	for batch := range slices.Chunk(ids, ownerBatchSize) {
+		found, err := s.repo.ListOwners(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for i := range found {
+			owners[found[i].ID] = &found[i]
+		}
+	}
+
+	for i := range products {
+		products[i].Owner = owners[products[i].OwnerID]
+	}
+	return nil
+}
+
+// Export calls fn for each product matching filter without loading them all into memory.
+func (s *Service) Export(ctx context.Context, filter ListFilter, fn func(product *Product) error) error {
+	return s.repo.Stream(ctx, filter, fn)
+}
+
+// TrashRetention returns how long deleted products stay restorable before being purged.
+func (s *Service) TrashRetention() time.Duration {
+	retention, err := time.ParseDuration(s.config.TrashRetention)
+	if err != nil || retention <= 0 {
+		return defaultTrashRetention
+	}
+	return retention
+}
+
+// FindDeletedByID finds a product in the trash by its ID.
+func (s *Service) FindDeletedByID(ctx context.Context, id uuid.UUID) (*Product, error) {
+	return s.repo.FindDeletedByID(ctx, id)
+}
+
+// ListTrash returns deleted products, most recently deleted first.
+// A nil ownerID lists the trash of every owner.
+func (s *Service) ListTrash(ctx context.Context, ownerID *uuid.UUID) ([]Product, error) {
+	return s.repo.ListDeleted(ctx, ownerID)
+}
+
+// Restore takes a product out of the trash, provided it is still within the retention window,
+// and records a revision made by actorID.
+func (s *Service) Restore(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (*Product, error) {
+	product, err := s.repo.FindDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Since(product.DeletedAt.Time) > s.TrashRetention() {
+		return nil, ErrRetentionExpired
+	}
+
+	err = s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		if err := tx.Restore(ctx, product); err != nil {
+			return translateCodeError(err)
+		}
+		return recordRevision(ctx, tx, product, RevisionRestore, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
 }
 
-// List returns all products.
-func (s *Service) List(ctx context.Context) ([]Product, error) {
-	return s.repo.List(ctx)
+// PurgeExpiredTrash permanently deletes products that have been in the trash for longer than the retention window.
+func (s *Service) PurgeExpiredTrash(ctx context.Context) (int64, error) {
+	return s.repo.PurgeDeletedBefore(ctx, time.Now().Add(-s.TrashRetention()))
 }
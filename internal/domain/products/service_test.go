@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
+
+	"go-crud-api/internal/config"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -29,48 +33,386 @@ func (m *MockProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*Pr
 	return args.Get(0).(*Product), args.Error(1)
 }
 
+func (m *MockProductRepository) FindByName(ctx context.Context, ownerID uuid.UUID, name string) (*Product, error) {
+	args := m.Called(ctx, ownerID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Product), args.Error(1)
+}
+
+func (m *MockProductRepository) FindBySKU(ctx context.Context, ownerID uuid.UUID, sku string) (*Product, error) {
+	args := m.Called(ctx, ownerID, sku)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Product), args.Error(1)
+}
+
+func (m *MockProductRepository) FindByGTIN(ctx context.Context, ownerID uuid.UUID, gtin string) (*Product, error) {
+	args := m.Called(ctx, ownerID, gtin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Product), args.Error(1)
+}
+
 func (m *MockProductRepository) Update(ctx context.Context, product *Product) error {
 	args := m.Called(ctx, product)
 	return args.Error(0)
 }
 
-func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (m *MockProductRepository) UpdateFields(ctx context.Context, product *Product, fields []string) error {
+	args := m.Called(ctx, product, fields)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID, version int) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) List(ctx context.Context, filter ListFilter) ([]Product, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Product), args.Error(1)
+}
+
+// Stream calls fn for each product given to Return, stopping at the first error.
+func (m *MockProductRepository) Stream(ctx context.Context, filter ListFilter, fn func(product *Product) error) error {
+	args := m.Called(ctx, filter)
+	for _, product := range args.Get(0).([]Product) {
+		if err := fn(&product); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockProductRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Product), args.Error(1)
+}
+
+func (m *MockProductRepository) ListDeleted(ctx context.Context, ownerID *uuid.UUID) ([]Product, error) {
+	args := m.Called(ctx, ownerID)
+	return args.Get(0).([]Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Restore(ctx context.Context, product *Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockProductRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Product), args.Error(1)
+}
+
+func (m *MockProductRepository) CreateMovement(ctx context.Context, movement *StockMovement) error {
+	args := m.Called(ctx, movement)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListMovements(ctx context.Context, productID uuid.UUID) ([]StockMovement, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]StockMovement), args.Error(1)
+}
+
+func (m *MockProductRepository) ReplaceTags(ctx context.Context, product *Product, names []string) error {
+	args := m.Called(ctx, product, names)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) CategoryExists(ctx context.Context, id uuid.UUID) (bool, error) {
 	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProductRepository) CreateVariant(ctx context.Context, variant *Variant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) FindVariant(ctx context.Context, productID, id uuid.UUID) (*Variant, error) {
+	args := m.Called(ctx, productID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Variant), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdateVariant(ctx context.Context, variant *Variant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) DeleteVariant(ctx context.Context, productID, id uuid.UUID, version int) error {
+	args := m.Called(ctx, productID, id, version)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListVariants(ctx context.Context, productID uuid.UUID) ([]Variant, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]Variant), args.Error(1)
+}
+
+func (m *MockProductRepository) SumVariantStock(ctx context.Context, productID uuid.UUID) (int, int64, error) {
+	args := m.Called(ctx, productID)
+	return args.Int(0), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockProductRepository) SumReservedStock(ctx context.Context, productID, exceptUserID uuid.UUID) (int, error) {
+	args := m.Called(ctx, productID, exceptUserID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProductRepository) CreateImage(ctx context.Context, image *Image) error {
+	args := m.Called(ctx, image)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) FindImage(ctx context.Context, productID, id uuid.UUID) (*Image, error) {
+	args := m.Called(ctx, productID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Image), args.Error(1)
+}
+
+func (m *MockProductRepository) ListImages(ctx context.Context, productID uuid.UUID) ([]Image, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]Image), args.Error(1)
+}
+
+func (m *MockProductRepository) DeleteImage(ctx context.Context, productID, id uuid.UUID) error {
+	args := m.Called(ctx, productID, id)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) SetImagePositions(ctx context.Context, productID uuid.UUID, ids []uuid.UUID) error {
+	args := m.Called(ctx, productID, ids)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListImagesDeletedBefore(ctx context.Context, cutoff time.Time) ([]Image, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).([]Image), args.Error(1)
+}
+
+func (m *MockProductRepository) CreateRevision(ctx context.Context, revision *Revision) error {
+	args := m.Called(ctx, revision)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListRevisions(ctx context.Context, productID uuid.UUID) ([]Revision, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]Revision), args.Error(1)
+}
+
+func (m *MockProductRepository) FindRevision(ctx context.Context, productID uuid.UUID, revision int) (*Revision, error) {
+	args := m.Called(ctx, productID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Revision), args.Error(1)
+}
+
+func (m *MockProductRepository) CreatePriceSchedule(ctx context.Context, schedule *PriceSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) FindPriceSchedule(ctx context.Context, productID, id uuid.UUID) (*PriceSchedule, error) {
+	args := m.Called(ctx, productID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PriceSchedule), args.Error(1)
+}
+
+func (m *MockProductRepository) ListPriceSchedules(ctx context.Context, productID uuid.UUID) ([]PriceSchedule, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]PriceSchedule), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdatePriceSchedule(ctx context.Context, schedule *PriceSchedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListDuePriceSchedules(ctx context.Context, now time.Time) ([]PriceSchedule, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]PriceSchedule), args.Error(1)
+}
+
+func (m *MockProductRepository) CreatePriceChange(ctx context.Context, change *PriceChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListPriceChanges(ctx context.Context, productID uuid.UUID) ([]PriceChange, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]PriceChange), args.Error(1)
+}
+
+func (m *MockProductRepository) CreateStockAlert(ctx context.Context, alert *StockAlert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListPendingStockAlerts(ctx context.Context, maxAttempts, limit int) ([]StockAlert, error) {
+	args := m.Called(ctx, maxAttempts, limit)
+	return args.Get(0).([]StockAlert), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdateStockAlert(ctx context.Context, alert *StockAlert) error {
+	args := m.Called(ctx, alert)
 	return args.Error(0)
 }
 
-func (m *MockProductRepository) List(ctx context.Context) ([]Product, error) {
-	args := m.Called(ctx)
+func (m *MockProductRepository) ListLowStock(ctx context.Context, ownerID *uuid.UUID) ([]Product, error) {
+	args := m.Called(ctx, ownerID)
 	return args.Get(0).([]Product), args.Error(1)
 }
 
+func (m *MockProductRepository) ListOwners(ctx context.Context, ids []uuid.UUID) ([]Owner, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]Owner), args.Error(1)
+}
+
+func (m *MockProductRepository) ListCollaborators(ctx context.Context, productID uuid.UUID) ([]Collaborator, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]Collaborator), args.Error(1)
+}
+
+func (m *MockProductRepository) FindCollaborator(ctx context.Context, productID, userID uuid.UUID) (*Collaborator, error) {
+	args := m.Called(ctx, productID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Collaborator), args.Error(1)
+}
+
+func (m *MockProductRepository) SaveCollaborator(ctx context.Context, collaborator *Collaborator) error {
+	args := m.Called(ctx, collaborator)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) DeleteCollaborator(ctx context.Context, productID, userID uuid.UUID) error {
+	args := m.Called(ctx, productID, userID)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListStockLevels(ctx context.Context, productID uuid.UUID) ([]StockLevel, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]StockLevel), args.Error(1)
+}
+
+func (m *MockProductRepository) SaveStockLevel(ctx context.Context, level *StockLevel) error {
+	args := m.Called(ctx, level)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) DeleteStockLevel(ctx context.Context, productID, warehouseID uuid.UUID) error {
+	args := m.Called(ctx, productID, warehouseID)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) CreateStockTransfer(ctx context.Context, transfer *StockTransfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) ListStockTransfers(ctx context.Context, productID uuid.UUID) ([]StockTransfer, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]StockTransfer), args.Error(1)
+}
+
+func (m *MockProductRepository) WarehouseExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// Transaction runs fn against the mock itself, so expectations set on it apply inside the transaction.
+func (m *MockProductRepository) Transaction(ctx context.Context, fn func(repo ProductRepository) error) error {
+	return fn(m)
+}
+
 func TestProductService_Create(t *testing.T) {
 	repo := new(MockProductRepository)
-	service := NewService(repo)
+	service := NewService(repo, config.Config{})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
 
 	ctx := context.Background()
 	ownerID := uuid.New()
 
+	input := ProductInput{Name: "Test Product", Description: "Desc", Price: decimal.RequireFromString("10.50"), Stock: 5}
+
 	// Test case 1: Successful creation
 	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
-	product, err := service.Create(ctx, "Test Product", "Desc", 10.50, 5, ownerID)
+	product, err := service.Create(ctx, input, ownerID)
 	assert.NoError(t, err)
 	assert.NotNil(t, product)
 	assert.Equal(t, "Test Product", product.Name)
+	assert.Equal(t, "USD", product.Currency)
+	assert.Equal(t, StatusDraft, product.Status)
+	assert.True(t, product.Price.Equal(decimal.RequireFromString("10.50")))
 	repo.AssertExpectations(t)
 
 	// Test case 2: Repository returns an error
 	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(errors.New("db error")).Once()
-	product, err = service.Create(ctx, "Test Product", "Desc", 10.50, 5, ownerID)
+	product, err = service.Create(ctx, input, ownerID)
 	assert.Error(t, err)
 	assert.Nil(t, product)
 	assert.Contains(t, err.Error(), "db error")
 	repo.AssertExpectations(t)
 }
 
+func TestProductService_Create_MoneyValidation(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{DefaultCurrency: "brl", AllowedCurrencies: "BRL,JPY"})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+
+	// Test case 1: Defaults to the configured currency
+	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
+	product, err := service.Create(ctx, ProductInput{Name: "Caneca", Price: decimal.RequireFromString("29.90"), Stock: 1}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, "BRL", product.Currency)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Currency not in the allowed list
+	product, err = service.Create(ctx, ProductInput{Name: "Mug", Price: decimal.RequireFromString("9.90"), Currency: "USD", Stock: 1}, ownerID)
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+	assert.Nil(t, product)
+
+	// Test case 3: More decimal places than the currency allows
+	product, err = service.Create(ctx, ProductInput{Name: "Cup", Price: decimal.RequireFromString("100.5"), Currency: "JPY", Stock: 1}, ownerID)
+	assert.ErrorIs(t, err, ErrInvalidPriceScale)
+	assert.Nil(t, product)
+	repo.AssertExpectations(t)
+}
+
 func TestProductService_FindByID(t *testing.T) {
 	repo := new(MockProductRepository)
-	service := NewService(repo)
+	service := NewService(repo, config.Config{})
 
 	ctx := context.Background()
 	productID := uuid.New()
@@ -94,25 +436,37 @@ func TestProductService_FindByID(t *testing.T) {
 
 func TestProductService_Update(t *testing.T) {
 	repo := new(MockProductRepository)
-	service := NewService(repo)
+	service := NewService(repo, config.Config{})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+	// None of these products has variants, so their stock can be edited directly.
+	repo.On("SumVariantStock", mock.Anything, mock.Anything).Return(0, int64(0), nil).Maybe()
+	repo.On("ListStockLevels", mock.Anything, mock.Anything).Return([]StockLevel(nil), nil).Maybe()
 
 	ctx := context.Background()
 	productID := uuid.New()
 	ownerID := uuid.New()
 
+	input := ProductInput{Name: "New Name", Description: "New Desc", Price: decimal.NewFromInt(20), Stock: 10}
+
 	// Test case 1: Successful update
-	existingProduct := &Product{ID: productID, Name: "Old Name", OwnerID: ownerID}
+	existingProduct := &Product{ID: productID, Name: "Old Name", Currency: "EUR", OwnerID: ownerID}
 	repo.On("FindByID", ctx, productID).Return(existingProduct, nil).Once()
 	repo.On("Update", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
-	updatedProduct, err := service.Update(ctx, productID, "New Name", "New Desc", 20.0, 10)
+	repo.On("CreateMovement", ctx, mock.MatchedBy(func(movement *StockMovement) bool {
+		return movement.Delta == 10 && movement.Reason == ReasonCorrection && movement.StockAfter == 10 && *movement.ActorID == ownerID
+	})).Return(nil).Once()
+	updatedProduct, err := service.Update(ctx, productID, 0, input, ownerID)
 	assert.NoError(t, err)
 	assert.NotNil(t, updatedProduct)
 	assert.Equal(t, "New Name", updatedProduct.Name)
+	assert.Equal(t, "EUR", updatedProduct.Currency)
 	repo.AssertExpectations(t)
 
 	// Test case 2: Product not found
 	repo.On("FindByID", ctx, productID).Return(nil, gorm.ErrRecordNotFound).Once()
-	updatedProduct, err = service.Update(ctx, productID, "New Name", "New Desc", 20.0, 10)
+	updatedProduct, err = service.Update(ctx, productID, 0, input, ownerID)
 	assert.Error(t, err)
 	assert.Nil(t, updatedProduct)
 	assert.Contains(t, err.Error(), gorm.ErrRecordNotFound.Error())
@@ -121,56 +475,1354 @@ func TestProductService_Update(t *testing.T) {
 	// Test case 3: Repository update error
 	repo.On("FindByID", ctx, productID).Return(existingProduct, nil).Once()
 	repo.On("Update", ctx, mock.AnythingOfType("*products.Product")).Return(errors.New("db error")).Once()
-	updatedProduct, err = service.Update(ctx, productID, "New Name", "New Desc", 20.0, 10)
+	updatedProduct, err = service.Update(ctx, productID, 0, input, ownerID)
 	assert.Error(t, err)
 	assert.Nil(t, updatedProduct)
 	assert.Contains(t, err.Error(), "db error")
 	repo.AssertExpectations(t)
+
+	// Test case 4: Expected version is stale
+	versionedProduct := &Product{ID: productID, Name: "Old Name", OwnerID: ownerID, Version: 3}
+	repo.On("FindByID", ctx, productID).Return(versionedProduct, nil).Once()
+	updatedProduct, err = service.Update(ctx, productID, 2, input, ownerID)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	assert.Nil(t, updatedProduct)
+	repo.AssertExpectations(t)
+
+	// Test case 5: Concurrent write detected by the repository
+	repo.On("FindByID", ctx, productID).Return(versionedProduct, nil).Once()
+	repo.On("Update", ctx, mock.AnythingOfType("*products.Product")).Return(ErrVersionMismatch).Once()
+	updatedProduct, err = service.Update(ctx, productID, 3, input, ownerID)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	assert.Nil(t, updatedProduct)
+	repo.AssertExpectations(t)
 }
 
-func TestProductService_Delete(t *testing.T) {
+func TestProductService_Patch(t *testing.T) {
 	repo := new(MockProductRepository)
-	service := NewService(repo)
+	service := NewService(repo, config.Config{})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+	// None of these products has variants, so their stock can be edited directly.
+	repo.On("SumVariantStock", mock.Anything, mock.Anything).Return(0, int64(0), nil).Maybe()
+	repo.On("ListStockLevels", mock.Anything, mock.Anything).Return([]StockLevel(nil), nil).Maybe()
 
 	ctx := context.Background()
 	productID := uuid.New()
+	actorID := uuid.New()
 
-	// Test case 1: Successful delete
-	repo.On("Delete", ctx, productID).Return(nil).Once()
-	err := service.Delete(ctx, productID)
+	// Test case 1: Only supplied fields are written, including zero values
+	existingProduct := &Product{ID: productID, Name: "Old Name", Description: "Desc", Price: decimal.NewFromInt(10), Currency: "USD", Stock: 7}
+	stock := 0
+	repo.On("FindByID", ctx, productID).Return(existingProduct, nil).Once()
+	repo.On("UpdateFields", ctx, existingProduct, []string{"stock"}).Return(nil).Once()
+	repo.On("CreateMovement", ctx, mock.MatchedBy(func(movement *StockMovement) bool {
+		return movement.Delta == -7 && movement.Reason == ReasonCorrection && movement.StockAfter == 0
+	})).Return(nil).Once()
+	patchedProduct, err := service.Patch(ctx, productID, 0, ProductPatch{Stock: &stock}, actorID)
 	assert.NoError(t, err)
+	assert.Equal(t, 0, patchedProduct.Stock)
+	assert.Equal(t, "Old Name", patchedProduct.Name)
+	assert.True(t, patchedProduct.Price.Equal(decimal.NewFromInt(10)))
 	repo.AssertExpectations(t)
 
-	// Test case 2: Repository returns an error
-	repo.On("Delete", ctx, productID).Return(errors.New("db error")).Once()
-	err = service.Delete(ctx, productID)
+	// Test case 2: Empty patch does not write
+	repo.On("FindByID", ctx, productID).Return(existingProduct, nil).Once()
+	patchedProduct, err = service.Patch(ctx, productID, 0, ProductPatch{}, actorID)
+	assert.NoError(t, err)
+	assert.Equal(t, existingProduct, patchedProduct)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Product not found
+	repo.On("FindByID", ctx, productID).Return(nil, gorm.ErrRecordNotFound).Once()
+	patchedProduct, err = service.Patch(ctx, productID, 0, ProductPatch{Stock: &stock}, actorID)
+	assert.Error(t, err)
+	assert.Nil(t, patchedProduct)
+	repo.AssertExpectations(t)
+
+	// Test case 4: Price no longer fits after a currency change
+	jpy := "JPY"
+	fractional := &Product{ID: productID, Name: "Old Name", Price: decimal.RequireFromString("10.50"), Currency: "USD"}
+	repo.On("FindByID", ctx, productID).Return(fractional, nil).Once()
+	patchedProduct, err = service.Patch(ctx, productID, 0, ProductPatch{Currency: &jpy}, actorID)
+	assert.ErrorIs(t, err, ErrInvalidPriceScale)
+	assert.Nil(t, patchedProduct)
+	repo.AssertExpectations(t)
+
+	// Test case 5: Repository update error
+	name := "New Name"
+	repo.On("FindByID", ctx, productID).Return(existingProduct, nil).Once()
+	repo.On("UpdateFields", ctx, existingProduct, []string{"name"}).Return(errors.New("db error")).Once()
+	patchedProduct, err = service.Patch(ctx, productID, 0, ProductPatch{Name: &name}, actorID)
 	assert.Error(t, err)
+	assert.Nil(t, patchedProduct)
 	assert.Contains(t, err.Error(), "db error")
 	repo.AssertExpectations(t)
 }
 
-func TestProductService_List(t *testing.T) {
+func TestProductService_SetCategory(t *testing.T) {
 	repo := new(MockProductRepository)
-	service := NewService(repo)
+	service := NewService(repo, config.Config{})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
 
 	ctx := context.Background()
+	productID := uuid.New()
+	categoryID := uuid.New()
 
-	// Test case 1: Successful list
-	expectedProducts := []Product{
-		{ID: uuid.New(), Name: "Product 1"},
-		{ID: uuid.New(), Name: "Product 2"},
-	}
-	repo.On("List", ctx).Return(expectedProducts, nil).Once()
-	products, err := service.List(ctx)
+	// Test case 1: Assign a category
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, Version: 2}, nil).Once()
+	repo.On("CategoryExists", ctx, categoryID).Return(true, nil).Once()
+	repo.On("UpdateFields", ctx, mock.AnythingOfType("*products.Product"), []string{"category_id"}).Return(nil).Once()
+	product, err := service.SetCategory(ctx, productID, 2, &categoryID, uuid.New())
 	assert.NoError(t, err)
-	assert.Equal(t, expectedProducts, products)
+	assert.Equal(t, &categoryID, product.CategoryID)
 	repo.AssertExpectations(t)
 
-	// Test case 2: Repository returns an error
-	repo.On("List", ctx).Return([]Product{}, errors.New("db error")).Once()
-	products, err = service.List(ctx)
-	assert.Error(t, err)
-	assert.Empty(t, products)
-	assert.Contains(t, err.Error(), "db error")
+	// Test case 2: Unknown category
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID}, nil).Once()
+	repo.On("CategoryExists", ctx, categoryID).Return(false, nil).Once()
+	_, err = service.SetCategory(ctx, productID, 0, &categoryID, uuid.New())
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Remove the category
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, CategoryID: &categoryID}, nil).Once()
+	repo.On("UpdateFields", ctx, mock.AnythingOfType("*products.Product"), []string{"category_id"}).Return(nil).Once()
+	product, err = service.SetCategory(ctx, productID, 0, nil, uuid.New())
+	assert.NoError(t, err)
+	assert.Nil(t, product.CategoryID)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_SetTags(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	// Test case 1: Tags are normalised before being stored
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, Version: 1}, nil).Once()
+	repo.On("UpdateFields", ctx, mock.AnythingOfType("*products.Product"), []string(nil)).Return(nil).Once()
+	repo.On("ReplaceTags", ctx, mock.AnythingOfType("*products.Product"), []string{"summer", "outdoor"}).Return(nil).Once()
+	_, err := service.SetTags(ctx, productID, 1, []string{" Summer", "outdoor", "SUMMER"}, uuid.New())
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Invalid tag
+	_, err = service.SetTags(ctx, productID, 1, []string{"  "}, uuid.New())
+	assert.ErrorIs(t, err, ErrInvalidTag)
+
+	// Test case 3: Stale version
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, Version: 3}, nil).Once()
+	_, err = service.SetTags(ctx, productID, 1, []string{"summer"}, uuid.New())
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_Revisions(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	productID := uuid.New()
+	categoryID := uuid.New()
+	// Price history is covered by TestProductService_PriceSchedules.
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	// Test case 1: Create records the first revision, made by the owner
+	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.MatchedBy(func(rev *Revision) bool {
+		return rev.Action == RevisionCreate && rev.Snapshot.Name == "Lamp" && *rev.ActorID == ownerID
+	})).Return(nil).Once()
+	_, err := service.Create(ctx, ProductInput{Name: "Lamp", Price: decimal.RequireFromString("10"), Currency: "USD", Stock: 1}, ownerID)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Update records the new state
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, Name: "Lamp", Currency: "USD", Version: 1}, nil).Once()
+	repo.On("Update", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.MatchedBy(func(rev *Revision) bool {
+		return rev.Action == RevisionUpdate && rev.Snapshot.Price.Equal(decimal.RequireFromString("12.50"))
+	})).Return(nil).Once()
+	_, err = service.Update(ctx, productID, 1, ProductInput{Name: "Lamp", Price: decimal.RequireFromString("12.50")}, ownerID)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Diff lists the changed fields; tag order does not matter
+	first := ProductSnapshot{Name: "Lamp", Price: decimal.RequireFromString("10"), Currency: "USD", Stock: 1, Tags: []string{"a", "b"}}
+	second := ProductSnapshot{Name: "Lamp", Price: decimal.RequireFromString("12.5"), Currency: "USD", Stock: 1, CategoryID: &categoryID, Tags: []string{"b", "a"}}
+	repo.On("FindRevision", ctx, productID, 1).Return(&Revision{Revision: 1, Snapshot: first}, nil)
+	repo.On("FindRevision", ctx, productID, 2).Return(&Revision{Revision: 2, Snapshot: second}, nil)
+	changes, err := service.DiffRevisions(ctx, productID, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"price", "category_id"}, []string{changes[0].Field, changes[1].Field})
+	assert.Len(t, changes, 2)
+
+	// Test case 4: Unknown revision
+	repo.On("FindRevision", ctx, productID, 9).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.DiffRevisions(ctx, productID, 1, 9)
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+
+	// Test case 5: Rollback restores the snapshot and records it as a new revision;
+	// a category deleted since is left unset
+	current := &Product{ID: productID, Name: "Desk lamp", Price: decimal.RequireFromString("15"), Currency: "USD", Stock: 4, Version: 3}
+	repo.On("FindByID", ctx, productID).Return(current, nil).Once()
+	repo.On("SumVariantStock", ctx, productID).Return(0, int64(0), nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return([]StockLevel(nil), nil).Once()
+	repo.On("CategoryExists", ctx, categoryID).Return(false, nil).Once()
+	repo.On("Update", ctx, current).Return(nil).Once()
+	repo.On("ReplaceTags", ctx, current, []string{"b", "a"}).Return(nil).Once()
+	repo.On("CreateMovement", ctx, mock.MatchedBy(func(m *StockMovement) bool {
+		return m.Delta == -3 && m.Note == correctionNoteRollback
+	})).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.MatchedBy(func(rev *Revision) bool {
+		return rev.Action == RevisionRollback && rev.Snapshot.Name == "Lamp"
+	})).Return(nil).Once()
+	product, err := service.Rollback(ctx, productID, 3, 2, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Lamp", product.Name)
+	assert.True(t, product.Price.Equal(decimal.RequireFromString("12.5")))
+	assert.Equal(t, 1, product.Stock)
+	assert.Nil(t, product.CategoryID)
 	repo.AssertExpectations(t)
+
+	// Test case 6: The stock of a product with variants is kept
+	current = &Product{ID: productID, Name: "Desk lamp", Currency: "USD", Stock: 7, Version: 4}
+	repo.On("FindByID", ctx, productID).Return(current, nil).Once()
+	repo.On("SumVariantStock", ctx, productID).Return(7, int64(2), nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return([]StockLevel(nil), nil).Once()
+	repo.On("Update", ctx, current).Return(nil).Once()
+	repo.On("ReplaceTags", ctx, current, []string{"a", "b"}).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.AnythingOfType("*products.Revision")).Return(nil).Once()
+	product, err = service.Rollback(ctx, productID, 0, 1, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, product.Stock)
+	repo.AssertExpectations(t)
+
+	// Test case 7: A change of SKU shows in the diff and is undone by a rollback
+	oldSKU, newSKU := "LAMP-01", "LAMP-02"
+	third := ProductSnapshot{Name: "Lamp", SKU: &oldSKU, Price: decimal.RequireFromString("10"), Currency: "USD", Stock: 1}
+	fourth := third
+	fourth.SKU = &newSKU
+	repo.On("FindRevision", ctx, productID, 3).Return(&Revision{Revision: 3, Snapshot: third}, nil)
+	repo.On("FindRevision", ctx, productID, 4).Return(&Revision{Revision: 4, Snapshot: fourth}, nil)
+	changes, err = service.DiffRevisions(ctx, productID, 3, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldChange{{Field: "sku", From: &oldSKU, To: &newSKU}}, changes)
+
+	current = &Product{ID: productID, Name: "Lamp", SKU: &newSKU, Price: decimal.RequireFromString("10"), Currency: "USD", Stock: 1, Version: 5}
+	repo.On("FindByID", ctx, productID).Return(current, nil).Once()
+	repo.On("SumVariantStock", ctx, productID).Return(0, int64(0), nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return([]StockLevel(nil), nil).Once()
+	repo.On("Update", ctx, current).Return(nil).Once()
+	repo.On("ReplaceTags", ctx, current, []string(nil)).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.MatchedBy(func(rev *Revision) bool {
+		return rev.Action == RevisionRollback && *rev.Snapshot.SKU == oldSKU
+	})).Return(nil).Once()
+	product, err = service.Rollback(ctx, productID, 5, 3, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, oldSKU, *product.SKU)
+	repo.AssertExpectations(t)
+
+	// Test case 8: Rolling back to a SKU another of the owner's products has taken since
+	current = &Product{ID: productID, Name: "Lamp", SKU: &newSKU, Price: decimal.RequireFromString("10"), Currency: "USD", Stock: 1, Version: 6}
+	repo.On("FindByID", ctx, productID).Return(current, nil).Once()
+	repo.On("SumVariantStock", ctx, productID).Return(0, int64(0), nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return([]StockLevel(nil), nil).Once()
+	repo.On("Update", ctx, current).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.Rollback(ctx, productID, 6, 3, ownerID)
+	assert.ErrorIs(t, err, ErrDuplicateCode)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_Variants(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	// None of these products is kept in warehouses.
+	repo.On("ListStockLevels", mock.Anything, mock.Anything).Return([]StockLevel(nil), nil).Maybe()
+
+	ctx := context.Background()
+	actorID := uuid.New()
+	productID := uuid.New()
+	variantID := uuid.New()
+	input := VariantInput{SKU: "TSHIRT-RED-M", Options: VariantOptions{"size": "M", "colour": "red"}, Stock: 4}
+
+	// Test case 1: Creating a variant sets the product stock to the variants' total
+	product := &Product{ID: productID, Currency: "USD", Stock: 10}
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	repo.On("CreateVariant", ctx, mock.AnythingOfType("*products.Variant")).Return(nil).Once()
+	repo.On("SumVariantStock", ctx, productID).Return(4, int64(1), nil).Once()
+	repo.On("UpdateFields", ctx, product, []string{"stock"}).Return(nil).Once()
+	repo.On("CreateMovement", ctx, mock.MatchedBy(func(movement *StockMovement) bool {
+		return movement.Delta == -6 && movement.StockAfter == 4 && movement.Note == correctionNoteVariants
+	})).Return(nil).Once()
+	variant, err := service.CreateVariant(ctx, productID, input, actorID)
+	assert.NoError(t, err)
+	assert.Equal(t, productID, variant.ProductID)
+	assert.Equal(t, 4, product.Stock)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Duplicate SKU or options
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Currency: "USD"}, nil).Once()
+	repo.On("CreateVariant", ctx, mock.AnythingOfType("*products.Variant")).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.CreateVariant(ctx, productID, input, actorID)
+	assert.ErrorIs(t, err, ErrDuplicateVariant)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Price override must fit the product currency
+	price := decimal.RequireFromString("1.005")
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Currency: "USD"}, nil).Once()
+	_, err = service.CreateVariant(ctx, productID, VariantInput{SKU: "X", Options: input.Options, Price: &price}, actorID)
+	assert.ErrorIs(t, err, ErrInvalidPriceScale)
+	repo.AssertExpectations(t)
+
+	// Test case 4: Stale variant version
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID}, nil).Once()
+	repo.On("FindVariant", ctx, productID, variantID).Return(&Variant{ID: variantID, ProductID: productID, Version: 3}, nil).Once()
+	_, err = service.UpdateVariant(ctx, productID, variantID, 2, input, actorID)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	repo.AssertExpectations(t)
+
+	// Test case 5: Deleting a variant recalculates the stock
+	product = &Product{ID: productID, Stock: 4}
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	repo.On("DeleteVariant", ctx, productID, variantID, 1).Return(nil).Once()
+	repo.On("SumVariantStock", ctx, productID).Return(0, int64(0), nil).Once()
+	repo.On("UpdateFields", ctx, product, []string{"stock"}).Return(nil).Once()
+	repo.On("CreateMovement", ctx, mock.AnythingOfType("*products.StockMovement")).Return(nil).Once()
+	assert.NoError(t, service.DeleteVariant(ctx, productID, variantID, 1, actorID))
+	assert.Equal(t, 0, product.Stock)
+	repo.AssertExpectations(t)
+
+	// Test case 6: Stock of a product with variants cannot be edited directly
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, Currency: "USD", Stock: 4}, nil).Once()
+	repo.On("SumVariantStock", ctx, productID).Return(4, int64(1), nil).Once()
+	stock := 9
+	_, err = service.Patch(ctx, productID, 0, ProductPatch{Stock: &stock}, actorID)
+	assert.ErrorIs(t, err, ErrStockManagedByVariants)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_AdjustStock(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	// None of these products has variants, so their stock can be edited directly.
+	repo.On("SumVariantStock", mock.Anything, mock.Anything).Return(0, int64(0), nil).Maybe()
+	repo.On("ListStockLevels", mock.Anything, mock.Anything).Return([]StockLevel(nil), nil).Maybe()
+
+	ctx := context.Background()
+	productID := uuid.New()
+	actorID := uuid.New()
+
+	// Test case 1: Sale decrements stock and is recorded in the ledger
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive, Stock: 5}, nil).Once()
+	repo.On("UpdateFields", ctx, mock.AnythingOfType("*products.Product"), []string{"stock"}).Return(nil).Once()
+	repo.On("CreateMovement", ctx, mock.AnythingOfType("*products.StockMovement")).Return(nil).Once()
+	product, movement, err := service.AdjustStock(ctx, productID, StockAdjustment{Delta: -2, Reason: ReasonSale, Note: "order 42"}, actorID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, product.Stock)
+	assert.Equal(t, -2, movement.Delta)
+	assert.Equal(t, 3, movement.StockAfter)
+	assert.Equal(t, ReasonSale, movement.Reason)
+	assert.Equal(t, actorID, *movement.ActorID)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Stock never goes below zero
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive, Stock: 1}, nil).Once()
+	product, movement, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -2, Reason: ReasonSale}, actorID)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.Nil(t, product)
+	assert.Nil(t, movement)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Delta sign must match the reason
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -2, Reason: ReasonRestock}, actorID)
+	assert.ErrorIs(t, err, ErrInvalidAdjustment)
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: 2, Reason: ReasonSale}, actorID)
+	assert.ErrorIs(t, err, ErrInvalidAdjustment)
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: 2, Reason: "gift"}, actorID)
+	assert.ErrorIs(t, err, ErrInvalidAdjustment)
+
+	// Test case 4: Product not found
+	repo.On("FindByIDForUpdate", ctx, productID).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: 4, Reason: ReasonRestock}, actorID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_ListMovements(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	expected := []StockMovement{{ID: uuid.New(), ProductID: productID, Delta: 3, Reason: ReasonRestock, StockAfter: 3}}
+	repo.On("ListMovements", ctx, productID).Return(expected, nil).Once()
+	movements, err := service.ListMovements(ctx, productID)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, movements)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_Delete(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+
+	ctx := context.Background()
+	productID := uuid.New()
+	actorID := uuid.New()
+	product := &Product{ID: productID, Name: "Lamp", Version: 2}
+
+	// Test case 1: Successful delete records a revision of the deleted state
+	repo.On("FindByID", ctx, productID).Return(product, nil).Once()
+	repo.On("Delete", ctx, productID, 0).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.MatchedBy(func(rev *Revision) bool {
+		return rev.Action == RevisionDelete && rev.Snapshot.Name == "Lamp" && *rev.ActorID == actorID
+	})).Return(nil).Once()
+	err := service.Delete(ctx, productID, 0, actorID)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Repository returns an error
+	repo.On("FindByID", ctx, productID).Return(product, nil).Once()
+	repo.On("Delete", ctx, productID, 0).Return(errors.New("db error")).Once()
+	err = service.Delete(ctx, productID, 0, actorID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "db error")
+	repo.AssertExpectations(t)
+
+	// Test case 3: Stale version
+	repo.On("FindByID", ctx, productID).Return(product, nil).Once()
+	repo.On("Delete", ctx, productID, 1).Return(ErrVersionMismatch).Once()
+	err = service.Delete(ctx, productID, 1, actorID)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_Batch(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+	// None of these products has variants, so their stock can be edited directly.
+	repo.On("SumVariantStock", mock.Anything, mock.Anything).Return(0, int64(0), nil).Maybe()
+	repo.On("ListStockLevels", mock.Anything, mock.Anything).Return([]StockLevel(nil), nil).Maybe()
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	owner := Actor{UserID: ownerID, Role: "user"}
+	productID := uuid.New()
+	otherID := uuid.New()
+
+	input := ProductInput{Name: "Widget", Price: decimal.NewFromInt(10), Stock: 5}
+	ops := []BatchOperation{
+		{Op: BatchCreate, Input: input},
+		{Op: BatchDelete, ID: productID},
+	}
+
+	// Test case 1: Best-effort batch applies every operation
+	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, OwnerID: ownerID, Version: 2}, nil).Once()
+	repo.On("Delete", ctx, productID, 2).Return(nil).Once()
+	results, err := service.Batch(ctx, owner, ops, false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, ownerID, results[0].Product.OwnerID)
+	assert.NoError(t, results[1].Err)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Best-effort batch keeps going after a forbidden operation
+	repo.On("FindByID", ctx, otherID).Return(&Product{ID: otherID, OwnerID: uuid.New()}, nil).Once()
+	repo.On("FindCollaborator", ctx, otherID, ownerID).Return(nil, gorm.ErrRecordNotFound).Once()
+	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
+	results, err = service.Batch(ctx, owner, []BatchOperation{
+		{Op: BatchUpdate, ID: otherID, Input: input},
+		{Op: BatchCreate, Input: input},
+	}, false)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, ErrForbidden)
+	assert.NoError(t, results[1].Err)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Admins may modify products they do not own
+	repo.On("FindByID", ctx, otherID).Return(&Product{ID: otherID, OwnerID: uuid.New(), Version: 1}, nil).Once()
+	repo.On("Delete", ctx, otherID, 1).Return(nil).Once()
+	results, err = service.Batch(ctx, Actor{UserID: ownerID, Role: "admin"}, []BatchOperation{{Op: BatchDelete, ID: otherID}}, true)
+	assert.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	repo.AssertExpectations(t)
+
+	// Test case 4: Atomic batch is rolled back when an operation fails
+	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, OwnerID: ownerID, Version: 3}, nil).Once()
+	results, err = service.Batch(ctx, owner, []BatchOperation{
+		{Op: BatchCreate, Input: input},
+		{Op: BatchDelete, ID: productID, Version: 2},
+	}, true)
+	assert.ErrorIs(t, err, ErrBatchRolledBack)
+	assert.ErrorIs(t, results[0].Err, ErrBatchRolledBack)
+	assert.Nil(t, results[0].Product)
+	assert.ErrorIs(t, results[1].Err, ErrVersionMismatch)
+	repo.AssertExpectations(t)
+
+	// Test case 5: Unknown operation
+	results, err = service.Batch(ctx, owner, []BatchOperation{{Op: "upsert"}}, false)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, ErrUnknownBatchOperation)
+}
+
+func TestProductService_DryRunBatch(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	productID := uuid.New()
+
+	// Test case 1: Reports each outcome, including failures
+	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
+	repo.On("FindByID", ctx, productID).Return(nil, gorm.ErrRecordNotFound).Once()
+	results, err := service.DryRunBatch(ctx, Actor{UserID: ownerID}, []BatchOperation{
+		{Op: BatchCreate, Input: ProductInput{Name: "Widget", Price: decimal.NewFromInt(10), Stock: 5}},
+		{Op: BatchUpdate, ID: productID},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "Widget", results[0].Product.Name)
+	assert.ErrorIs(t, results[1].Err, gorm.ErrRecordNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_List(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+
+	ctx := context.Background()
+
+	// Test case 1: Successful list
+	expectedProducts := []Product{
+		{ID: uuid.New(), Name: "Product 1"},
+		{ID: uuid.New(), Name: "Product 2"},
+	}
+	filter := ListFilter{Currency: "EUR", InStock: true}
+	repo.On("List", ctx, filter).Return(expectedProducts, nil).Once()
+	products, err := service.List(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedProducts, products)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Repository returns an error
+	repo.On("List", ctx, filter).Return([]Product{}, errors.New("db error")).Once()
+	products, err = service.List(ctx, filter)
+	assert.Error(t, err)
+	assert.Empty(t, products)
+	assert.Contains(t, err.Error(), "db error")
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_Export(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	filter := ListFilter{OwnerID: &ownerID}
+	streamed := []Product{{Name: "Product 1"}, {Name: "Product 2"}}
+
+	// Test case 1: Every product is passed to the callback
+	repo.On("Stream", ctx, filter).Return(streamed, nil).Once()
+	var names []string
+	err := service.Export(ctx, filter, func(product *Product) error {
+		names = append(names, product.Name)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Product 1", "Product 2"}, names)
+	repo.AssertExpectations(t)
+
+	// Test case 2: A callback error stops the export
+	repo.On("Stream", ctx, filter).Return(streamed, nil).Once()
+	calls := 0
+	err = service.Export(ctx, filter, func(product *Product) error {
+		calls++
+		return errors.New("client gone")
+	})
+	assert.EqualError(t, err, "client gone")
+	assert.Equal(t, 1, calls)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_TrashRetention(t *testing.T) {
+	service := NewService(new(MockProductRepository), config.Config{TrashRetention: "48h"})
+	assert.Equal(t, 48*time.Hour, service.TrashRetention())
+
+	service = NewService(new(MockProductRepository), config.Config{TrashRetention: "invalid"})
+	assert.Equal(t, defaultTrashRetention, service.TrashRetention())
+}
+
+func TestProductService_ListTrash(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+
+	deleted := []Product{{ID: uuid.New(), OwnerID: ownerID, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}}
+	repo.On("ListDeleted", ctx, &ownerID).Return(deleted, nil).Once()
+	products, err := service.ListTrash(ctx, &ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, deleted, products)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_Restore(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{TrashRetention: "24h"})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	ctx := context.Background()
+	productID := uuid.New()
+
+	// Test case 1: Restored within the retention window
+	recent := &Product{ID: productID, DeletedAt: gorm.DeletedAt{Time: time.Now().Add(-time.Hour), Valid: true}}
+	repo.On("FindDeletedByID", ctx, productID).Return(recent, nil).Once()
+	repo.On("Restore", ctx, recent).Return(nil).Once()
+	product, err := service.Restore(ctx, productID, uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, recent, product)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Retention window has expired
+	expired := &Product{ID: productID, DeletedAt: gorm.DeletedAt{Time: time.Now().Add(-48 * time.Hour), Valid: true}}
+	repo.On("FindDeletedByID", ctx, productID).Return(expired, nil).Once()
+	product, err = service.Restore(ctx, productID, uuid.New())
+	assert.ErrorIs(t, err, ErrRetentionExpired)
+	assert.Nil(t, product)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Product is not in the trash
+	repo.On("FindDeletedByID", ctx, productID).Return(nil, gorm.ErrRecordNotFound).Once()
+	product, err = service.Restore(ctx, productID, uuid.New())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, product)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_PurgeExpiredTrash(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{TrashRetention: "24h"})
+
+	ctx := context.Background()
+
+	repo.On("PurgeDeletedBefore", ctx, mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) >= 24*time.Hour && time.Since(cutoff) < 25*time.Hour
+	})).Return(int64(3), nil).Once()
+	purged, err := service.PurgeExpiredTrash(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_PriceSchedules(t *testing.T) {
+	ctx := context.Background()
+	ownerID := uuid.New()
+	productID := uuid.New()
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	newProduct := func(price string) *Product {
+		return &Product{ID: productID, Price: decimal.RequireFromString(price), Currency: "USD", Version: 1}
+	}
+
+	// Test case 1: Schedules must start in the future and end after they start
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	_, err := service.SchedulePrice(ctx, productID, PriceScheduleInput{Price: decimal.RequireFromString("9"), EffectiveFrom: now.Add(-time.Hour)}, ownerID)
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
+	_, err = service.SchedulePrice(ctx, productID, PriceScheduleInput{Price: decimal.RequireFromString("9"), EffectiveFrom: *at(2 * time.Hour), EffectiveTo: at(time.Hour)}, ownerID)
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
+
+	// Test case 2: A schedule overlapping an open one is rejected; one after it ends is not
+	sale := PriceSchedule{ID: uuid.New(), EffectiveFrom: *at(time.Hour), EffectiveTo: at(3 * time.Hour), Status: ScheduleStatusPending}
+	past := PriceSchedule{ID: uuid.New(), EffectiveFrom: *at(time.Hour), EffectiveTo: at(5 * time.Hour), Status: ScheduleStatusCancelled}
+	repo.On("FindByIDForUpdate", ctx, productID).Return(newProduct("20"), nil)
+	repo.On("ListPriceSchedules", ctx, productID).Return([]PriceSchedule{sale, past}, nil)
+	repo.On("CreatePriceSchedule", ctx, mock.AnythingOfType("*products.PriceSchedule")).Return(nil).Once()
+
+	_, err = service.SchedulePrice(ctx, productID, PriceScheduleInput{Price: decimal.RequireFromString("15"), EffectiveFrom: *at(2 * time.Hour), EffectiveTo: at(4 * time.Hour)}, ownerID)
+	assert.ErrorIs(t, err, ErrScheduleOverlap)
+	_, err = service.SchedulePrice(ctx, productID, PriceScheduleInput{Price: decimal.RequireFromString("15"), EffectiveFrom: *at(2 * time.Hour)}, ownerID)
+	assert.ErrorIs(t, err, ErrScheduleOverlap)
+
+	schedule, err := service.SchedulePrice(ctx, productID, PriceScheduleInput{Price: decimal.RequireFromString("15"), EffectiveFrom: *at(3 * time.Hour)}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", schedule.Currency)
+	assert.Equal(t, ScheduleStatusPending, schedule.Status)
+	repo.AssertNumberOfCalls(t, "CreatePriceSchedule", 1)
+
+	// Test case 3: A due schedule sets the price, keeps the previous one and records the change
+	repo = new(MockProductRepository)
+	service = NewService(repo, config.Config{})
+	pending := &PriceSchedule{ID: uuid.New(), ProductID: productID, Price: decimal.RequireFromString("15"), Currency: "USD", EffectiveFrom: *at(-time.Minute), EffectiveTo: at(time.Hour), Status: ScheduleStatusPending, CreatedBy: &ownerID}
+	product := newProduct("20")
+	repo.On("ListDuePriceSchedules", ctx, now).Return([]PriceSchedule{*pending}, nil)
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil)
+	repo.On("FindByID", ctx, productID).Return(product, nil)
+	repo.On("FindPriceSchedule", ctx, productID, pending.ID).Return(pending, nil)
+	repo.On("UpdateFields", ctx, product, []string{"price"}).Return(nil)
+	repo.On("CreatePriceChange", ctx, mock.MatchedBy(func(change *PriceChange) bool {
+		return change.Price.Equal(decimal.RequireFromString("15")) && *change.ScheduleID == pending.ID && *change.ActorID == ownerID
+	})).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.AnythingOfType("*products.Revision")).Return(nil)
+	repo.On("UpdatePriceSchedule", ctx, pending).Return(nil)
+
+	processed, err := service.ApplyDuePriceSchedules(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, "15", product.Price.String())
+	assert.Equal(t, ScheduleStatusActive, pending.Status)
+	assert.Equal(t, "20", pending.PreviousPrice.String())
+	repo.AssertExpectations(t)
+
+	// Test case 4: An ended schedule restores the previous price
+	repo = new(MockProductRepository)
+	service = NewService(repo, config.Config{})
+	previous := decimal.RequireFromString("20")
+	active := &PriceSchedule{ID: uuid.New(), ProductID: productID, Price: decimal.RequireFromString("15"), Currency: "USD", EffectiveFrom: *at(-2 * time.Hour), EffectiveTo: at(-time.Minute), Status: ScheduleStatusActive, PreviousPrice: &previous}
+	product = newProduct("15")
+	repo.On("ListDuePriceSchedules", ctx, now).Return([]PriceSchedule{*active}, nil)
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil)
+	repo.On("FindByID", ctx, productID).Return(product, nil)
+	repo.On("FindPriceSchedule", ctx, productID, active.ID).Return(active, nil)
+	repo.On("UpdateFields", ctx, product, []string{"price"}).Return(nil)
+	repo.On("CreatePriceChange", ctx, mock.MatchedBy(func(change *PriceChange) bool {
+		return change.Price.Equal(previous) && change.ActorID == nil
+	})).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.AnythingOfType("*products.Revision")).Return(nil)
+	repo.On("UpdatePriceSchedule", ctx, active).Return(nil)
+
+	_, err = service.ApplyDuePriceSchedules(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "20", product.Price.String())
+	assert.Equal(t, ScheduleStatusCompleted, active.Status)
+	repo.AssertExpectations(t)
+
+	// Test case 5: A price changed by hand during the schedule is kept when it ends
+	repo = new(MockProductRepository)
+	service = NewService(repo, config.Config{})
+	active.Status = ScheduleStatusActive
+	product = newProduct("17")
+	repo.On("ListDuePriceSchedules", ctx, now).Return([]PriceSchedule{*active}, nil)
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil)
+	repo.On("FindByID", ctx, productID).Return(product, nil)
+	repo.On("FindPriceSchedule", ctx, productID, active.ID).Return(active, nil)
+	repo.On("UpdatePriceSchedule", ctx, active).Return(nil)
+
+	_, err = service.ApplyDuePriceSchedules(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "17", product.Price.String())
+	assert.Equal(t, ScheduleStatusCompleted, active.Status)
+	repo.AssertNotCalled(t, "UpdateFields", mock.Anything, mock.Anything, mock.Anything)
+
+	// Test case 6: A schedule whose whole window was missed is skipped
+	repo = new(MockProductRepository)
+	service = NewService(repo, config.Config{})
+	missed := &PriceSchedule{ID: uuid.New(), ProductID: productID, Price: decimal.RequireFromString("15"), Currency: "USD", EffectiveFrom: *at(-2 * time.Hour), EffectiveTo: at(-time.Hour), Status: ScheduleStatusPending}
+	product = newProduct("20")
+	repo.On("ListDuePriceSchedules", ctx, now).Return([]PriceSchedule{*missed}, nil)
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil)
+	repo.On("FindByID", ctx, productID).Return(product, nil)
+	repo.On("FindPriceSchedule", ctx, productID, missed.ID).Return(missed, nil)
+	repo.On("UpdatePriceSchedule", ctx, missed).Return(nil)
+
+	_, err = service.ApplyDuePriceSchedules(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, ScheduleStatusSkipped, missed.Status)
+	assert.Equal(t, "20", product.Price.String())
+
+	// Test case 7: Only pending schedules can be cancelled
+	repo.On("FindPriceSchedule", ctx, productID, active.ID).Return(active, nil)
+	_, err = service.CancelPriceSchedule(ctx, productID, active.ID)
+	assert.ErrorIs(t, err, ErrScheduleNotPending)
+
+	// Test case 8: Edits record the price history only when the price changes
+	repo = new(MockProductRepository)
+	service = NewService(repo, config.Config{})
+	product = newProduct("20")
+	repo.On("FindByID", ctx, productID).Return(product, nil)
+	repo.On("UpdateFields", ctx, product, mock.Anything).Return(nil)
+	repo.On("CreateRevision", ctx, mock.AnythingOfType("*products.Revision")).Return(nil)
+	repo.On("CreatePriceChange", ctx, mock.MatchedBy(func(change *PriceChange) bool {
+		return change.Price.Equal(decimal.RequireFromString("25")) && *change.ActorID == ownerID && change.ScheduleID == nil
+	})).Return(nil).Once()
+
+	name, price := "Renamed", decimal.RequireFromString("25")
+	_, err = service.Patch(ctx, productID, 0, ProductPatch{Name: &name}, ownerID)
+	assert.NoError(t, err)
+	_, err = service.Patch(ctx, productID, 0, ProductPatch{Price: &price}, ownerID)
+	assert.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "CreatePriceChange", 1)
+}
+
+func TestProductService_LowStock(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	ownerID := uuid.New()
+	threshold := 5
+	newProduct := func(stock int) *Product {
+		return &Product{ID: productID, Name: "Lamp", OwnerID: ownerID, Status: StatusActive, Stock: stock, ReorderThreshold: &threshold, Price: decimal.RequireFromString("10"), Currency: "USD", Version: 1}
+	}
+	newRepo := func() *MockProductRepository {
+		repo := new(MockProductRepository)
+		repo.On("SumVariantStock", mock.Anything, mock.Anything).Return(0, int64(0), nil).Maybe()
+		repo.On("ListStockLevels", mock.Anything, mock.Anything).Return([]StockLevel(nil), nil).Maybe()
+		repo.On("UpdateFields", ctx, mock.AnythingOfType("*products.Product"), mock.Anything).Return(nil).Maybe()
+		repo.On("Update", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Maybe()
+		repo.On("CreateMovement", ctx, mock.AnythingOfType("*products.StockMovement")).Return(nil).Maybe()
+		// Revisions and price history are covered by their own tests.
+		repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+		repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+		return repo
+	}
+
+	// Test case 1: A sale taking stock to the threshold raises an alert for the owner
+	repo := newRepo()
+	service := NewService(repo, config.Config{})
+	repo.On("FindByIDForUpdate", ctx, productID).Return(newProduct(7), nil).Once()
+	repo.On("CreateStockAlert", ctx, mock.MatchedBy(func(alert *StockAlert) bool {
+		return alert.OwnerID == ownerID && alert.Stock == 5 && alert.Threshold == 5 && alert.ProductName == "Lamp"
+	})).Return(nil).Once()
+	_, _, err := service.AdjustStock(ctx, productID, StockAdjustment{Delta: -2, Reason: ReasonSale}, ownerID)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Stock that was already low, or stays above the threshold, raises no alert
+	repo.On("FindByIDForUpdate", ctx, productID).Return(newProduct(4), nil).Once()
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -1, Reason: ReasonSale}, ownerID)
+	assert.NoError(t, err)
+	repo.On("FindByIDForUpdate", ctx, productID).Return(newProduct(9), nil).Once()
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -3, Reason: ReasonSale}, ownerID)
+	assert.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "CreateStockAlert", 1)
+
+	// Test case 3: Editing the stock through Update is checked too
+	repo = newRepo()
+	service = NewService(repo, config.Config{})
+	repo.On("FindByID", ctx, productID).Return(newProduct(10), nil).Once()
+	repo.On("CreateStockAlert", ctx, mock.AnythingOfType("*products.StockAlert")).Return(nil).Once()
+	_, err = service.Update(ctx, productID, 0, ProductInput{Name: "Lamp", Price: decimal.RequireFromString("10"), Stock: 0}, ownerID)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	// Test case 4: Products without a threshold never alert
+	repo = newRepo()
+	service = NewService(repo, config.Config{})
+	product := newProduct(10)
+	product.ReorderThreshold = nil
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -10, Reason: ReasonSale}, ownerID)
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "CreateStockAlert", mock.Anything, mock.Anything)
+
+	// Test case 5: The threshold can be set and cleared
+	repo.On("FindByID", ctx, productID).Return(product, nil)
+	product, err = service.SetReorderThreshold(ctx, productID, 1, &threshold, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, *product.ReorderThreshold)
+	product, err = service.SetReorderThreshold(ctx, productID, 0, nil, ownerID)
+	assert.NoError(t, err)
+	assert.Nil(t, product.ReorderThreshold)
+	repo.AssertCalled(t, "UpdateFields", ctx, product, []string{"reorder_threshold"})
+	repo.AssertCalled(t, "CreateRevision", ctx, mock.MatchedBy(func(rev *Revision) bool {
+		return rev.Action == RevisionUpdate && rev.Snapshot.ReorderThreshold == nil && *rev.ActorID == ownerID
+	}))
+	assert.Equal(t, []FieldChange{{Field: "reorder_threshold", From: &threshold, To: (*int)(nil)}}, diffSnapshots(ProductSnapshot{ReorderThreshold: &threshold}, ProductSnapshot{}))
+}
+
+func TestProductService_PublicCatalogue(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	productID := uuid.New()
+	product := &Product{ID: productID, Name: "Lamp", OwnerID: ownerID, Stock: 3, Status: StatusDraft, Version: 1}
+
+	// Test case 1: Drafts are not found in the public catalogue
+	repo.On("FindByID", ctx, productID).Return(product, nil)
+	_, err := service.FindPublic(ctx, productID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// Test case 2: Activating a product makes it visible
+	repo.On("UpdateFields", ctx, product, []string{"status"}).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.MatchedBy(func(rev *Revision) bool {
+		return rev.Action == RevisionUpdate && rev.Snapshot.Status == StatusActive && *rev.ActorID == ownerID
+	})).Return(nil).Once()
+	product, err = service.Activate(ctx, productID, 1, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusActive, product.Status)
+	assert.Equal(t, []FieldChange{{Field: "status", From: StatusDraft, To: StatusActive}}, diffSnapshots(ProductSnapshot{Status: StatusDraft}, ProductSnapshot{Status: StatusActive}))
+	assert.Empty(t, diffSnapshots(ProductSnapshot{}, ProductSnapshot{Status: StatusActive}))
+	found, err := service.FindPublic(ctx, productID)
+	assert.NoError(t, err)
+	view := NewPublicProduct(found)
+	assert.True(t, view.InStock)
+	assert.Equal(t, []Tag{}, view.Tags)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Public listings only include products on sale, whatever the owner and status filters
+	repo.On("List", ctx, ListFilter{Statuses: []string{StatusActive, StatusDiscontinued}, InStock: true}).Return([]Product{*product}, nil).Once()
+	products, err := service.ListPublic(ctx, ListFilter{OwnerID: &ownerID, Statuses: []string{StatusDraft}, InStock: true})
+	assert.NoError(t, err)
+	assert.Len(t, products, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	ownerID := uuid.New()
+	productID := uuid.New()
+	newProduct := func(status string) *Product {
+		return &Product{ID: productID, Name: "Lamp", OwnerID: ownerID, Stock: 5, Status: status, Price: decimal.RequireFromString("10"), Currency: "USD", Version: 1}
+	}
+	newRepo := func(product *Product) *MockProductRepository {
+		repo := new(MockProductRepository)
+		repo.On("FindByID", ctx, productID).Return(product, nil).Maybe()
+		repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Maybe()
+		repo.On("SumVariantStock", mock.Anything, mock.Anything).Return(0, int64(0), nil).Maybe()
+		repo.On("ListStockLevels", mock.Anything, mock.Anything).Return([]StockLevel(nil), nil).Maybe()
+		repo.On("UpdateFields", ctx, mock.AnythingOfType("*products.Product"), mock.Anything).Return(nil).Maybe()
+		repo.On("Update", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Maybe()
+		repo.On("CreateMovement", ctx, mock.AnythingOfType("*products.StockMovement")).Return(nil).Maybe()
+		// Revisions and price history are covered by their own tests.
+		repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+		repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+		return repo
+	}
+
+	// Test case 1: A product moves through draft, active, discontinued and archived
+	product := newProduct(StatusDraft)
+	service := NewService(newRepo(product), config.Config{})
+	_, err := service.Activate(ctx, productID, 0, ownerID)
+	assert.NoError(t, err)
+	_, err = service.Discontinue(ctx, productID, 0, ownerID)
+	assert.NoError(t, err)
+	_, err = service.Archive(ctx, productID, 0, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusArchived, product.Status)
+
+	// Test case 2: Transitions outside the lifecycle are rejected
+	_, err = service.Activate(ctx, productID, 0, ownerID)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	service = NewService(newRepo(newProduct(StatusDraft)), config.Config{})
+	_, err = service.Archive(ctx, productID, 0, ownerID)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	_, err = service.Discontinue(ctx, productID, 2, ownerID)
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+
+	// Test case 3: Archived products are read-only
+	service = NewService(newRepo(newProduct(StatusArchived)), config.Config{})
+	_, err = service.Update(ctx, productID, 0, ProductInput{Name: "Lamp", Price: decimal.RequireFromString("12"), Stock: 5}, ownerID)
+	assert.ErrorIs(t, err, ErrProductArchived)
+	_, err = service.SetTags(ctx, productID, 0, []string{"sale"}, ownerID)
+	assert.ErrorIs(t, err, ErrProductArchived)
+	err = service.Delete(ctx, productID, 0, ownerID)
+	assert.ErrorIs(t, err, ErrProductArchived)
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: 1, Reason: ReasonReturn}, ownerID)
+	assert.ErrorIs(t, err, ErrProductArchived)
+	_, err = service.CreateVariant(ctx, productID, VariantInput{SKU: "LAMP-1", Stock: 1}, ownerID)
+	assert.ErrorIs(t, err, ErrProductArchived)
+
+	// Test case 4: Discontinued products sell and take returns but cannot be restocked
+	product = newProduct(StatusDiscontinued)
+	service = NewService(newRepo(product), config.Config{})
+	more, less := 8, 1
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: 3, Reason: ReasonRestock}, ownerID)
+	assert.ErrorIs(t, err, ErrProductDiscontinued)
+	_, err = service.Patch(ctx, productID, 0, ProductPatch{Stock: &more}, ownerID)
+	assert.ErrorIs(t, err, ErrProductDiscontinued)
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -2, Reason: ReasonSale}, ownerID)
+	assert.NoError(t, err)
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: 1, Reason: ReasonReturn}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, product.Stock)
+	_, err = service.Patch(ctx, productID, 0, ProductPatch{Stock: &less}, ownerID)
+	assert.NoError(t, err)
+
+	// Test case 5: Drafts cannot be sold
+	service = NewService(newRepo(newProduct(StatusDraft)), config.Config{})
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -1, Reason: ReasonSale}, ownerID)
+	assert.ErrorIs(t, err, ErrNotForSale)
+}
+
+func TestProductService_Collaborators(t *testing.T) {
+	ctx := context.Background()
+	owner := Actor{UserID: uuid.New(), Role: "user"}
+	editor := Actor{UserID: uuid.New(), Role: "user"}
+	viewer := Actor{UserID: uuid.New(), Role: "user"}
+	stranger := Actor{UserID: uuid.New(), Role: "user"}
+	productID := uuid.New()
+	product := &Product{ID: productID, OwnerID: owner.UserID, Status: StatusActive, Version: 1}
+
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	repo.On("FindByID", ctx, productID).Return(product, nil)
+	repo.On("FindCollaborator", ctx, productID, editor.UserID).Return(&Collaborator{ProductID: productID, UserID: editor.UserID, Role: CollaboratorEditor}, nil)
+	repo.On("FindCollaborator", ctx, productID, viewer.UserID).Return(&Collaborator{ProductID: productID, UserID: viewer.UserID, Role: CollaboratorViewer}, nil)
+	repo.On("FindCollaborator", ctx, productID, stranger.UserID).Return(nil, gorm.ErrRecordNotFound)
+
+	// Test case 1: Editors can do what viewers can; viewers cannot edit
+	for _, tc := range []struct {
+		actor   Actor
+		role    string
+		allowed bool
+	}{
+		{owner, CollaboratorEditor, true},
+		{editor, CollaboratorEditor, true},
+		{editor, CollaboratorViewer, true},
+		{viewer, CollaboratorViewer, true},
+		{viewer, CollaboratorEditor, false},
+		{stranger, CollaboratorViewer, false},
+	} {
+		allowed, err := service.CanAccess(ctx, tc.actor, product, tc.role)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.allowed, allowed)
+	}
+
+	// Test case 2: The owner shares the product
+	repo.On("SaveCollaborator", ctx, mock.AnythingOfType("*products.Collaborator")).Return(nil).Once()
+	collaborator, err := service.SetCollaborator(ctx, owner, productID, stranger.UserID, CollaboratorViewer)
+	assert.NoError(t, err)
+	assert.Equal(t, CollaboratorViewer, collaborator.Role)
+	assert.Equal(t, owner.UserID, *collaborator.GrantedBy)
+
+	// Test case 3: Editors cannot manage collaborators, and the owner cannot be one
+	_, err = service.SetCollaborator(ctx, editor, productID, stranger.UserID, CollaboratorEditor)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.SetCollaborator(ctx, owner, productID, owner.UserID, CollaboratorEditor)
+	assert.ErrorIs(t, err, ErrCollaboratorIsOwner)
+	_, err = service.SetCollaborator(ctx, owner, productID, stranger.UserID, "admin")
+	assert.ErrorIs(t, err, ErrInvalidCollaboratorRole)
+
+	// Test case 4: Unknown user
+	missingID := uuid.New()
+	repo.On("SaveCollaborator", ctx, mock.AnythingOfType("*products.Collaborator")).Return(gorm.ErrForeignKeyViolated).Once()
+	_, err = service.SetCollaborator(ctx, owner, productID, missingID, CollaboratorEditor)
+	assert.ErrorIs(t, err, ErrCollaboratorUserNotFound)
+	repo.AssertNumberOfCalls(t, "SaveCollaborator", 2)
+
+	// Test case 5: Collaborators can list collaborators and leave; others cannot remove them
+	repo.On("ListCollaborators", ctx, productID).Return([]Collaborator{{UserID: viewer.UserID, Role: CollaboratorViewer}}, nil).Once()
+	collaborators, err := service.ListCollaborators(ctx, viewer, productID)
+	assert.NoError(t, err)
+	assert.Len(t, collaborators, 1)
+	_, err = service.ListCollaborators(ctx, stranger, productID)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.On("DeleteCollaborator", ctx, productID, viewer.UserID).Return(nil).Once()
+	assert.NoError(t, service.RemoveCollaborator(ctx, viewer, productID, viewer.UserID))
+	assert.ErrorIs(t, service.RemoveCollaborator(ctx, editor, productID, viewer.UserID), ErrForbidden)
+	repo.On("DeleteCollaborator", ctx, productID, stranger.UserID).Return(gorm.ErrRecordNotFound).Once()
+	assert.ErrorIs(t, service.RemoveCollaborator(ctx, owner, productID, stranger.UserID), ErrCollaboratorNotFound)
+
+	// Test case 6: Editors can modify the product in a batch; viewers cannot
+	repo.On("Delete", ctx, productID, 1).Return(nil).Once()
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	results, err := service.Batch(ctx, editor, []BatchOperation{{Op: BatchDelete, ID: productID}}, false)
+	assert.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	results, err = service.Batch(ctx, viewer, []BatchOperation{{Op: BatchDelete, ID: productID}}, false)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, ErrForbidden)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_ExpandOwners(t *testing.T) {
+	ctx := context.Background()
+	aliceID, bobID := uuid.New(), uuid.New()
+	products := []Product{
+		{ID: uuid.New(), OwnerID: aliceID},
+		{ID: uuid.New(), OwnerID: bobID},
+		{ID: uuid.New(), OwnerID: aliceID},
+	}
+
+	// Test case 1: Owners are loaded once each, in a single query
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	repo.On("ListOwners", ctx, []uuid.UUID{aliceID, bobID}).Return([]Owner{{ID: aliceID, Name: "Alice"}}, nil).Once()
+	err := service.ExpandOwners(ctx, products)
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", products[0].Owner.Name)
+	assert.Same(t, products[0].Owner, products[2].Owner)
+	// Bob no longer exists.
+	assert.Nil(t, products[1].Owner)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Large listings are loaded in batches
+	many := make([]Product, ownerBatchSize+1)
+	for i := range many {
+		many[i].OwnerID = uuid.New()
+	}
+	repo.On("ListOwners", ctx, mock.MatchedBy(func(ids []uuid.UUID) bool { return len(ids) == ownerBatchSize })).Return([]Owner{}, nil).Once()
+	repo.On("ListOwners", ctx, []uuid.UUID{many[ownerBatchSize].OwnerID}).Return([]Owner{}, nil).Once()
+	err = service.ExpandOwners(ctx, many)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Empty listings do not query owners
+	err = service.ExpandOwners(ctx, nil)
+	assert.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "ListOwners", 3)
+}
+
+func TestProductService_Codes(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	// Revisions and price history are covered by their own tests.
+	repo.On("CreateRevision", mock.Anything, mock.Anything).Return(nil).Maybe()
+	repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	productID := uuid.New()
+
+	// Test case 1: SKU and barcode are stored; empty ones are left unset
+	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(nil).Once()
+	product, err := service.Create(ctx, ProductInput{Name: "Lamp", SKU: "LAMP-OAK-01", Price: decimal.NewFromInt(40), Stock: 1}, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, "LAMP-OAK-01", *product.SKU)
+	assert.Nil(t, product.GTIN)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Another of the owner's products already uses the code
+	repo.On("Create", ctx, mock.AnythingOfType("*products.Product")).Return(gorm.ErrDuplicatedKey).Once()
+	product, err = service.Create(ctx, ProductInput{Name: "Lamp", GTIN: "4006381333931", Price: decimal.NewFromInt(40), Stock: 1}, ownerID)
+	assert.ErrorIs(t, err, ErrDuplicateCode)
+	assert.Nil(t, product)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Patching an empty SKU clears it
+	sku, gtin := "LAMP-OAK-01", "036000291452"
+	existing := &Product{ID: productID, Name: "Lamp", SKU: &sku, Price: decimal.NewFromInt(40), Currency: "USD", OwnerID: ownerID}
+	empty := ""
+	repo.On("FindByID", ctx, productID).Return(existing, nil).Once()
+	repo.On("UpdateFields", ctx, existing, []string{"sku"}).Return(nil).Once()
+	product, err = service.Patch(ctx, productID, 0, ProductPatch{SKU: &empty}, ownerID)
+	assert.NoError(t, err)
+	assert.Nil(t, product.SKU)
+	repo.AssertExpectations(t)
+
+	// Test case 4: Patching a barcode in use
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, Name: "Lamp", Currency: "USD", OwnerID: ownerID}, nil).Once()
+	repo.On("UpdateFields", ctx, mock.AnythingOfType("*products.Product"), []string{"gtin"}).Return(gorm.ErrDuplicatedKey).Once()
+	product, err = service.Patch(ctx, productID, 0, ProductPatch{GTIN: &gtin}, ownerID)
+	assert.ErrorIs(t, err, ErrDuplicateCode)
+	assert.Nil(t, product)
+	repo.AssertExpectations(t)
+
+	// Test case 5: Barcodes are looked up in their GTIN-14 form
+	repo.On("FindByGTIN", ctx, ownerID, "00036000291452").Return(existing, nil).Once()
+	product, err = service.FindByBarcode(ctx, ownerID, gtin)
+	assert.NoError(t, err)
+	assert.Equal(t, existing, product)
+	repo.AssertExpectations(t)
+}
+
+func TestProductService_Warehouses(t *testing.T) {
+	repo := new(MockProductRepository)
+	service := NewService(repo, config.Config{})
+	// None of these products has variants.
+	repo.On("SumVariantStock", mock.Anything, mock.Anything).Return(0, int64(0), nil).Maybe()
+
+	ctx := context.Background()
+	actorID := uuid.New()
+	productID := uuid.New()
+	saoPaulo, recife, curitiba := uuid.New(), uuid.New(), uuid.New()
+	levels := func() []StockLevel {
+		return []StockLevel{
+			{ProductID: productID, WarehouseID: saoPaulo, Quantity: 6},
+			{ProductID: productID, WarehouseID: recife, Quantity: 4},
+		}
+	}
+	saved := func(warehouseID uuid.UUID, quantity int) any {
+		return mock.MatchedBy(func(level *StockLevel) bool {
+			return level.WarehouseID == warehouseID && level.Quantity == quantity
+		})
+	}
+
+	// Test case 1: Counting stock in a warehouse sets the product's stock to the levels' total
+	product := &Product{ID: productID, Status: StatusActive, Stock: 10}
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return(levels(), nil).Once()
+	repo.On("SaveStockLevel", ctx, saved(curitiba, 5)).Return(nil).Once()
+	repo.On("UpdateFields", ctx, product, []string{"stock"}).Return(nil).Once()
+	repo.On("CreateMovement", ctx, mock.MatchedBy(func(movement *StockMovement) bool {
+		return movement.Delta == 5 && movement.StockAfter == 15 && *movement.WarehouseID == curitiba && movement.Note == correctionNoteStockCount
+	})).Return(nil).Once()
+	product, level, err := service.SetStockLevel(ctx, productID, curitiba, 5, "", actorID)
+	assert.NoError(t, err)
+	assert.Equal(t, 15, product.Stock)
+	assert.Equal(t, 5, level.Quantity)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Unknown warehouse
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive}, nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return([]StockLevel(nil), nil).Once()
+	repo.On("SaveStockLevel", ctx, mock.AnythingOfType("*products.StockLevel")).Return(gorm.ErrForeignKeyViolated).Once()
+	_, _, err = service.SetStockLevel(ctx, productID, uuid.New(), 5, "", actorID)
+	assert.ErrorIs(t, err, ErrWarehouseNotFound)
+	repo.AssertExpectations(t)
+
+	// Test case 3: A sale without a warehouse is drawn from the fullest warehouses first
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive, Stock: 10}, nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return(levels(), nil).Once()
+	repo.On("SaveStockLevel", ctx, saved(saoPaulo, 0)).Return(nil).Once()
+	repo.On("SaveStockLevel", ctx, saved(recife, 2)).Return(nil).Once()
+	repo.On("UpdateFields", ctx, mock.AnythingOfType("*products.Product"), []string{"stock"}).Return(nil).Once()
+	repo.On("CreateMovement", ctx, mock.AnythingOfType("*products.StockMovement")).Return(nil).Once()
+	product, movement, err := service.AdjustStock(ctx, productID, StockAdjustment{Delta: -8, Reason: ReasonSale}, actorID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, product.Stock)
+	assert.Nil(t, movement.WarehouseID)
+	repo.AssertExpectations(t)
+
+	// Test case 4: A restock into a named warehouse
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive, Stock: 10}, nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return(levels(), nil).Once()
+	repo.On("SaveStockLevel", ctx, saved(recife, 7)).Return(nil).Once()
+	repo.On("UpdateFields", ctx, mock.AnythingOfType("*products.Product"), []string{"stock"}).Return(nil).Once()
+	repo.On("CreateMovement", ctx, mock.AnythingOfType("*products.StockMovement")).Return(nil).Once()
+	_, movement, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: 3, Reason: ReasonRestock, WarehouseID: &recife}, actorID)
+	assert.NoError(t, err)
+	assert.Equal(t, recife, *movement.WarehouseID)
+	repo.AssertExpectations(t)
+
+	// Test case 5: A sale from a warehouse cannot take more than it holds
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive, Stock: 10}, nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return(levels(), nil).Once()
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -5, Reason: ReasonSale, WarehouseID: &recife}, actorID)
+	var insufficient *InsufficientStockError
+	assert.ErrorAs(t, err, &insufficient)
+	assert.Equal(t, 4, insufficient.Available)
+	repo.AssertExpectations(t)
+
+	// Test case 6: A sale from a warehouse without the product has no stock to take,
+	// while one from an unknown warehouse reports the warehouse as not found
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive, Stock: 10}, nil).Twice()
+	repo.On("ListStockLevels", ctx, productID).Return(levels(), nil).Twice()
+	repo.On("WarehouseExists", ctx, curitiba).Return(true, nil).Once()
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -1, Reason: ReasonSale, WarehouseID: &curitiba}, actorID)
+	assert.ErrorAs(t, err, &insufficient)
+	assert.Equal(t, 0, insufficient.Available)
+	missingID := uuid.New()
+	repo.On("WarehouseExists", ctx, missingID).Return(false, nil).Once()
+	_, _, err = service.AdjustStock(ctx, productID, StockAdjustment{Delta: -1, Reason: ReasonSale, WarehouseID: &missingID}, actorID)
+	assert.ErrorIs(t, err, ErrWarehouseNotFound)
+	repo.AssertExpectations(t)
+
+	// Test case 7: Stock kept in warehouses cannot be edited directly
+	repo.On("FindByID", ctx, productID).Return(&Product{ID: productID, Currency: "USD", Stock: 10}, nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return(levels(), nil).Once()
+	stock := 12
+	_, err = service.Patch(ctx, productID, 0, ProductPatch{Stock: &stock}, actorID)
+	assert.ErrorIs(t, err, ErrStockManagedByWarehouses)
+	repo.AssertExpectations(t)
+
+	// Test case 8: Transfers move stock between warehouses and are recorded
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive, Stock: 10}, nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return(levels(), nil).Once()
+	repo.On("SaveStockLevel", ctx, saved(saoPaulo, 2)).Return(nil).Once()
+	repo.On("WarehouseExists", ctx, curitiba).Return(true, nil).Once()
+	repo.On("SaveStockLevel", ctx, saved(curitiba, 4)).Return(nil).Once()
+	repo.On("CreateStockTransfer", ctx, mock.AnythingOfType("*products.StockTransfer")).Return(nil).Once()
+	transfer, err := service.TransferStock(ctx, productID, StockTransferInput{FromWarehouseID: saoPaulo, ToWarehouseID: curitiba, Quantity: 4}, actorID)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, transfer.Quantity)
+	assert.Equal(t, actorID, *transfer.ActorID)
+	repo.AssertExpectations(t)
+
+	// Test case 9: Transfers need enough stock in the source warehouse
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive, Stock: 10}, nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return(levels(), nil).Once()
+	_, err = service.TransferStock(ctx, productID, StockTransferInput{FromWarehouseID: recife, ToWarehouseID: saoPaulo, Quantity: 5}, actorID)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	repo.AssertExpectations(t)
+
+	// Test case 10: Transfers within a warehouse are rejected
+	_, err = service.TransferStock(ctx, productID, StockTransferInput{FromWarehouseID: recife, ToWarehouseID: recife, Quantity: 1}, actorID)
+	assert.ErrorIs(t, err, ErrSameWarehouse)
+
+	// Test case 11: Only empty stock levels can be removed
+	repo.On("FindByIDForUpdate", ctx, productID).Return(&Product{ID: productID, Status: StatusActive, Stock: 10}, nil).Once()
+	repo.On("ListStockLevels", ctx, productID).Return(levels(), nil).Once()
+	err = service.RemoveStockLevel(ctx, productID, recife)
+	assert.ErrorIs(t, err, ErrStockLevelNotEmpty)
+	repo.AssertExpectations(t)
+}
+
+func TestTransferOwnership(t *testing.T) {
+	repo := new(MockProductRepository)
+	ctx := context.Background()
+	fromID, toID, actorID := uuid.New(), uuid.New(), uuid.New()
+	productID := uuid.New()
+
+	// Test case 1: The product changes owner and the change is recorded as a revision
+	product := &Product{ID: productID, Name: "Lamp", Status: StatusActive, Currency: "USD", OwnerID: fromID}
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	repo.On("FindByID", ctx, productID).Return(product, nil).Once()
+	repo.On("UpdateFields", ctx, product, []string{"owner_id"}).Return(nil).Once()
+	repo.On("CreateRevision", ctx, mock.MatchedBy(func(rev *Revision) bool {
+		return rev.Action == RevisionUpdate && *rev.ActorID == actorID && *rev.Snapshot.OwnerID == toID
+	})).Return(nil).Once()
+	moved, err := TransferOwnership(ctx, repo, productID, fromID, toID, actorID)
+	assert.NoError(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, toID, product.OwnerID)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Archived products and products of another owner are left alone
+	archived := &Product{ID: productID, Status: StatusArchived, OwnerID: fromID}
+	repo.On("FindByIDForUpdate", ctx, productID).Return(archived, nil).Once()
+	repo.On("FindByID", ctx, productID).Return(archived, nil).Once()
+	moved, err = TransferOwnership(ctx, repo, productID, fromID, toID, actorID)
+	assert.NoError(t, err)
+	assert.False(t, moved)
+	other := &Product{ID: productID, Status: StatusActive, OwnerID: uuid.New()}
+	repo.On("FindByIDForUpdate", ctx, productID).Return(other, nil).Once()
+	repo.On("FindByID", ctx, productID).Return(other, nil).Once()
+	moved, err = TransferOwnership(ctx, repo, productID, fromID, toID, actorID)
+	assert.NoError(t, err)
+	assert.False(t, moved)
+	repo.AssertNumberOfCalls(t, "UpdateFields", 1)
+
+	// Test case 3: Products in the trash are not found
+	repo.On("FindByIDForUpdate", ctx, productID).Return(nil, gorm.ErrRecordNotFound).Once()
+	moved, err = TransferOwnership(ctx, repo, productID, fromID, toID, actorID)
+	assert.NoError(t, err)
+	assert.False(t, moved)
+
+	// Test case 4: The new owner already uses the product's SKU or barcode
+	product = &Product{ID: productID, Status: StatusActive, OwnerID: fromID}
+	repo.On("FindByIDForUpdate", ctx, productID).Return(product, nil).Once()
+	repo.On("FindByID", ctx, productID).Return(product, nil).Once()
+	repo.On("UpdateFields", ctx, product, []string{"owner_id"}).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = TransferOwnership(ctx, repo, productID, fromID, toID, actorID)
+	assert.ErrorIs(t, err, ErrDuplicateCode)
 }
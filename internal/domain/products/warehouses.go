@@ -0,0 +1,279 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListStockLevels returns a product's stock per warehouse, fullest first.
+func (s *Service) ListStockLevels(ctx context.Context, productID uuid.UUID) ([]StockLevel, error) {
+	if _, err := s.repo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStockLevels(ctx, productID)
+}
+
+// SetStockLevel sets the quantity of a product held in a warehouse, as after a stock count,
+// and recalculates the product's stock as the sum of its stock levels. From its first stock
+// level on, a product's stock is managed through its warehouses. A change to the product's
+// stock is recorded in the movement ledger as a correction made by actorID.
+func (s *Service) SetStockLevel(ctx context.Context, productID, warehouseID uuid.UUID, quantity int, note string, actorID uuid.UUID) (*Product, *StockLevel, error) {
+	var product *Product
+	var level *StockLevel
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		var err error
+		product, err = tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+		if err := checkNoVariants(ctx, tx, productID); err != nil {
+			return err
+		}
+
+		levels, err := tx.ListStockLevels(ctx, productID)
+		if err != nil {
+			return err
+		}
+
+		level = &StockLevel{ProductID: productID, WarehouseID: warehouseID}
+		total := quantity
+		for _, existing := range levels {
+			if existing.WarehouseID == warehouseID {
+				*level = existing
+			} else {
+				total += existing.Quantity
+			}
+		}
+		if err := checkStockIncrease(product, total); err != nil {
+			return err
+		}
+
+		level.Quantity = quantity
+		if err := tx.SaveStockLevel(ctx, level); err != nil {
+			return translateWarehouseError(err)
+		}
+
+		if note == "" {
+			note = correctionNoteStockCount
+		}
+		return syncWarehouseStock(ctx, tx, product, total, &warehouseID, actorID, note)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, level, nil
+}
+
+// RemoveStockLevel removes an empty stock level. Once a product has no stock levels left,
+// its stock is edited directly again.
+func (s *Service) RemoveStockLevel(ctx context.Context, productID, warehouseID uuid.UUID) error {
+	return s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		product, err := tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+
+		levels, err := tx.ListStockLevels(ctx, productID)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(levels, func(level StockLevel) bool { return level.WarehouseID == warehouseID })
+		if i < 0 {
+			return ErrStockLevelNotFound
+		}
+		if levels[i].Quantity > 0 {
+			return ErrStockLevelNotEmpty
+		}
+
+		return tx.DeleteStockLevel(ctx, productID, warehouseID)
+	})
+}
+
+// TransferStock moves stock of a product from one warehouse to another and records the
+// transfer, all in one transaction. The product's stock does not change.
+func (s *Service) TransferStock(ctx context.Context, productID uuid.UUID, input StockTransferInput, actorID uuid.UUID) (*StockTransfer, error) {
+	if input.FromWarehouseID == input.ToWarehouseID {
+		return nil, ErrSameWarehouse
+	}
+
+	transfer := &StockTransfer{
+		ProductID:       productID,
+		FromWarehouseID: &input.FromWarehouseID,
+		ToWarehouseID:   &input.ToWarehouseID,
+		Quantity:        input.Quantity,
+		ActorID:         &actorID,
+		Note:            input.Note,
+	}
+
+	err := s.repo.Transaction(ctx, func(tx ProductRepository) error {
+		product, err := tx.FindByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if err := checkEditable(product); err != nil {
+			return err
+		}
+
+		levels, err := tx.ListStockLevels(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if len(levels) == 0 {
+			return ErrNoStockLevels
+		}
+		if err := checkWarehouse(ctx, tx, levels, &input.FromWarehouseID); err != nil {
+			return err
+		}
+		if err := checkWarehouse(ctx, tx, levels, &input.ToWarehouseID); err != nil {
+			return err
+		}
+
+		from, err := allocateStock(productID, levels, -input.Quantity, &input.FromWarehouseID)
+		if err != nil {
+			return err
+		}
+		to, err := allocateStock(productID, levels, input.Quantity, &input.ToWarehouseID)
+		if err != nil {
+			return err
+		}
+		for _, level := range slices.Concat(from, to) {
+			if err := tx.SaveStockLevel(ctx, &level); err != nil {
+				return translateWarehouseError(err)
+			}
+		}
+
+		return tx.CreateStockTransfer(ctx, transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// ListStockTransfers returns the stock moved between warehouses for a product, most recent first.
+func (s *Service) ListStockTransfers(ctx context.Context, productID uuid.UUID) ([]StockTransfer, error) {
+	if _, err := s.repo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStockTransfers(ctx, productID)
+}
+
+// allocateStock returns the stock levels changed by a stock change of delta, given a product's
+// levels ordered fullest first. With a warehouse, the whole change is made there. Without one,
+// an increase goes to the warehouse holding the most stock and a decrease is drawn from the
+// fullest warehouses first, possibly from several of them.
+func allocateStock(productID uuid.UUID, levels []StockLevel, delta int, warehouseID *uuid.UUID) ([]StockLevel, error) {
+	if warehouseID != nil {
+		level := StockLevel{ProductID: productID, WarehouseID: *warehouseID}
+		if i := slices.IndexFunc(levels, func(l StockLevel) bool { return l.WarehouseID == *warehouseID }); i >= 0 {
+			level = levels[i]
+		}
+		if level.Quantity+delta < 0 {
+			return nil, &InsufficientStockError{Available: level.Quantity}
+		}
+		level.Quantity += delta
+		return []StockLevel{level}, nil
+	}
+
+	if delta > 0 {
+		level := levels[0]
+		level.Quantity += delta
+		return []StockLevel{level}, nil
+	}
+
+	var changed []StockLevel
+	available := 0
+	for _, level := range levels {
+		available += level.Quantity
+		if delta == 0 || level.Quantity == 0 {
+			continue
+		}
+		taken := min(level.Quantity, -delta)
+		level.Quantity -= taken
+		delta += taken
+		changed = append(changed, level)
+	}
+	if delta < 0 {
+		return nil, &InsufficientStockError{Available: available}
+	}
+	return changed, nil
+}
+
+// syncWarehouseStock sets a product's stock to total, the sum of its stock levels.
+// The change bumps the product version and is recorded in the movement ledger as a
+// correction in the given warehouse.
+func syncWarehouseStock(ctx context.Context, tx ProductRepository, product *Product, total int, warehouseID *uuid.UUID, actorID uuid.UUID, note string) error {
+	if total == product.Stock {
+		return nil
+	}
+
+	previousStock := product.Stock
+	product.Stock = total
+	if err := tx.UpdateFields(ctx, product, []string{"stock"}); err != nil {
+		return err
+	}
+
+	err := tx.CreateMovement(ctx, &StockMovement{
+		ProductID:   product.ID,
+		Delta:       product.Stock - previousStock,
+		Reason:      ReasonCorrection,
+		StockAfter:  product.Stock,
+		ActorID:     &actorID,
+		WarehouseID: warehouseID,
+		Note:        note,
+	})
+	if err != nil {
+		return err
+	}
+	return checkLowStock(ctx, tx, product, previousStock)
+}
+
+// checkWarehouse returns ErrWarehouseNotFound when a stock change names a warehouse that
+// the product has no stock level in and that does not exist, rather than reporting that
+// the warehouse has no stock.
+func checkWarehouse(ctx context.Context, tx ProductRepository, levels []StockLevel, warehouseID *uuid.UUID) error {
+	if warehouseID == nil || slices.ContainsFunc(levels, func(level StockLevel) bool { return level.WarehouseID == *warehouseID }) {
+		return nil
+	}
+
+	exists, err := tx.WarehouseExists(ctx, *warehouseID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrWarehouseNotFound
+	}
+	return nil
+}
+
+// checkNoStockLevels rejects changes that need a product's stock not to be kept in warehouses.
+func checkNoStockLevels(ctx context.Context, tx ProductRepository, productID uuid.UUID) error {
+	levels, err := tx.ListStockLevels(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if len(levels) > 0 {
+		return ErrStockManagedByWarehouses
+	}
+	return nil
+}
+
+// translateWarehouseError maps a foreign key violation on the warehouse to ErrWarehouseNotFound.
+func translateWarehouseError(err error) error {
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrWarehouseNotFound
+	}
+	return err
+}
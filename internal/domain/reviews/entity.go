@@ -0,0 +1,34 @@
+package reviews
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review is a user's rating and comment on a product. Each user reviews a product at most once.
+// Reviews hidden by a moderator have HiddenAt set and do not count towards the product's rating.
+type Review struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID uuid.UUID  `gorm:"type:uuid;not null" json:"product_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	Rating    int        `gorm:"type:smallint;not null" json:"rating" example:"4"`
+	Title     string     `gorm:"type:varchar(120);not null" json:"title" example:"Does the job"`
+	Body      string     `gorm:"type:text;not null" json:"body" example:"Sturdy and keeps coffee hot for hours."`
+	HiddenAt  *time.Time `json:"hidden_at,omitempty"`
+	HiddenBy  *uuid.UUID `gorm:"type:uuid" json:"hidden_by,omitempty"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName overrides the table name used by Review.
+func (Review) TableName() string {
+	return "product_reviews"
+}
+
+// ReviewInput holds the editable fields of a review.
+type ReviewInput struct {
+	Rating int
+	Title  string
+	Body   string
+}
@@ -0,0 +1,16 @@
+package reviews
+
+import "errors"
+
+var (
+	// ErrInvalidRating is returned when a rating is not between 1 and 5 stars.
+	ErrInvalidRating = errors.New("rating must be between 1 and 5")
+	// ErrAlreadyReviewed is returned when the user has already reviewed the product.
+	ErrAlreadyReviewed = errors.New("you have already reviewed this product")
+	// ErrReviewNotFound is returned when a review does not exist or belongs to another product.
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrProductNotFound is returned when reviewing a product that does not exist.
+	ErrProductNotFound = errors.New("product not found")
+	// ErrForbidden is returned when the actor is not the author of the review.
+	ErrForbidden = errors.New("not allowed to modify this review")
+)
@@ -0,0 +1,302 @@
+package reviews
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go-crud-api/internal/http/middleware"
+	"go-crud-api/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ReviewHandler handles review-related requests.
+type ReviewHandler struct {
+	service  *Service
+	validate *validator.Validate
+}
+
+// NewReviewHandler creates a new ReviewHandler.
+func NewReviewHandler(service *Service) *ReviewHandler {
+	return &ReviewHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// ReviewRequest is the request payload for creating or updating a review.
+type ReviewRequest struct {
+	Rating int    `json:"rating" validate:"required,min=1,max=5" example:"4"`
+	Title  string `json:"title" validate:"required,min=2,max=120" example:"Does the job"`
+	Body   string `json:"body" validate:"max=5000" example:"Sturdy and keeps coffee hot for hours."`
+}
+
+// ListReviews handles listing the reviews of a product.
+// @Summary List product reviews
+// @Description List a product's reviews, newest first. Hidden reviews are only listed for admins.
+// @Tags Reviews
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Success 200 {object} web.Response{data=[]Review} "Reviews"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid product ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/reviews [get]
+func (h *ReviewHandler) ListReviews(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.service.List(r.Context(), actor, productID)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch reviews")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: reviews})
+}
+
+// CreateReview handles reviewing a product.
+// @Summary Review a product
+// @Description Rate a product from 1 to 5 stars with a title and optional text. Each user can review a product once; the product's rating_average and rating_count are updated with the review.
+// @Tags Reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param review body ReviewRequest true "Review"
+// @Success 201 {object} web.Response{data=Review} "Review created successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Product already reviewed by the user"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/reviews [post]
+func (h *ReviewHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := h.service.Create(r.Context(), actor.UserID, productID, ReviewInput(req))
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not create review")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: review})
+}
+
+// UpdateReview handles editing a review.
+// @Summary Update a review
+// @Description Edit a review. Only its author can edit it.
+// @Tags Reviews
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param reviewID path string true "Review ID"
+// @Param review body ReviewRequest true "Review"
+// @Success 200 {object} web.Response{data=Review} "Review updated successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not the author)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or review not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/reviews/{reviewID} [put]
+func (h *ReviewHandler) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	productID, id, ok := parseReviewPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := h.service.Update(r.Context(), actor, productID, id, ReviewInput(req))
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update review")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: review})
+}
+
+// DeleteReview handles deleting a review.
+// @Summary Delete a review
+// @Description Delete a review. Only its author can delete it.
+// @Tags Reviews
+// @Security BearerAuth
+// @Param productID path string true "Product ID"
+// @Param reviewID path string true "Review ID"
+// @Success 204 "Review deleted successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not the author)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or review not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/reviews/{reviewID} [delete]
+func (h *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	productID, id, ok := parseReviewPath(w, r)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), actor, productID, id); err != nil {
+		respondWithWriteError(w, r, err, "Could not delete review")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// HideReview handles hiding a review.
+// @Summary Hide a review
+// @Description Hide a review from listings and the product's rating (Admin only)
+// @Tags Reviews
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param reviewID path string true "Review ID"
+// @Success 200 {object} web.Response{data=Review} "Review hidden"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or review not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/reviews/{reviewID}/hide [post]
+func (h *ReviewHandler) HideReview(w http.ResponseWriter, r *http.Request) {
+	h.setHidden(w, r, true)
+}
+
+// UnhideReview handles showing a hidden review again.
+// @Summary Unhide a review
+// @Description Show a hidden review again and count it in the product's rating (Admin only)
+// @Tags Reviews
+// @Security BearerAuth
+// @Produce json
+// @Param productID path string true "Product ID"
+// @Param reviewID path string true "Review ID"
+// @Success 200 {object} web.Response{data=Review} "Review shown"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Product or review not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/products/{productID}/reviews/{reviewID}/unhide [post]
+func (h *ReviewHandler) UnhideReview(w http.ResponseWriter, r *http.Request) {
+	h.setHidden(w, r, false)
+}
+
+// setHidden hides or shows the review in the request path.
+func (h *ReviewHandler) setHidden(w http.ResponseWriter, r *http.Request, hidden bool) {
+	productID, id, ok := parseReviewPath(w, r)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := h.service.SetHidden(r.Context(), actor.UserID, productID, id, hidden)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not moderate review")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: review})
+}
+
+// parseReviewPath reads the product and review IDs from the request path.
+// It writes a 400 response and returns false when either is malformed.
+func parseReviewPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid product ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "reviewID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid review ID format", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return productID, id, true
+}
+
+// respondWithWriteError maps service errors to HTTP responses.
+func respondWithWriteError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	apiErr, status := writeError(err, message)
+	if status == http.StatusInternalServerError {
+		web.RespondWithServerError(w, r, err, message)
+		return
+	}
+	web.RespondWithError(w, apiErr.Code, apiErr.Message, status)
+}
+
+// writeError maps a service error to the API error and status code reported for it.
+func writeError(err error, message string) (*web.ApiError, int) {
+	switch {
+	case errors.Is(err, ErrInvalidRating):
+		return &web.ApiError{Code: "validation_error", Message: err.Error()}, http.StatusBadRequest
+	case errors.Is(err, ErrAlreadyReviewed):
+		return &web.ApiError{Code: "conflict", Message: err.Error()}, http.StatusConflict
+	case errors.Is(err, ErrForbidden):
+		return &web.ApiError{Code: "forbidden", Message: "You can only modify your own reviews"}, http.StatusForbidden
+	case errors.Is(err, ErrProductNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Product not found"}, http.StatusNotFound
+	case errors.Is(err, ErrReviewNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Review not found"}, http.StatusNotFound
+	default:
+		return &web.ApiError{Code: "internal_error", Message: message}, http.StatusInternalServerError
+	}
+}
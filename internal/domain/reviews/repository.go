@@ -0,0 +1,26 @@
+package reviews
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ReviewRepository defines the interface for review data operations.
+type ReviewRepository interface {
+	// Create inserts a review, returning gorm.ErrDuplicatedKey if the user has already reviewed the product.
+	Create(ctx context.Context, review *Review) error
+	FindByID(ctx context.Context, productID, id uuid.UUID) (*Review, error)
+	// Update saves the rating, title, body and moderation state of a review.
+	Update(ctx context.Context, review *Review) error
+	Delete(ctx context.Context, productID, id uuid.UUID) error
+	// List returns a product's reviews, newest first. Hidden reviews are only included with includeHidden.
+	List(ctx context.Context, productID uuid.UUID, includeHidden bool) ([]Review, error)
+	// LockProduct locks the product row until the transaction ends, so rating refreshes of a product
+	// are serialised. It returns gorm.ErrRecordNotFound for products that do not exist or are in the trash.
+	LockProduct(ctx context.Context, productID uuid.UUID) error
+	// RefreshProductRating recomputes the product's average rating and review count from its visible reviews.
+	RefreshProductRating(ctx context.Context, productID uuid.UUID) error
+	// Transaction runs fn inside a database transaction, passing a repository bound to it.
+	Transaction(ctx context.Context, fn func(repo ReviewRepository) error) error
+}
@@ -0,0 +1,163 @@
+package reviews
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Service defines the review service. Every write locks the product and refreshes its
+// aggregate rating in the same transaction.
+type Service struct {
+	repo ReviewRepository
+	now  func() time.Time
+}
+
+// NewService creates a new review service.
+func NewService(repo ReviewRepository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// Create adds userID's review of a product.
+func (s *Service) Create(ctx context.Context, userID, productID uuid.UUID, input ReviewInput) (*Review, error) {
+	review := &Review{ProductID: productID, UserID: userID}
+	if err := apply(review, input); err != nil {
+		return nil, err
+	}
+
+	err := s.write(ctx, productID, func(tx ReviewRepository) error {
+		if err := tx.Create(ctx, review); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrAlreadyReviewed
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// List returns a product's reviews, newest first. Hidden reviews are only listed for admins.
+func (s *Service) List(ctx context.Context, actor products.Actor, productID uuid.UUID) ([]Review, error) {
+	return s.repo.List(ctx, productID, actor.IsAdmin())
+}
+
+// Update changes a review. Only its author can edit it.
+func (s *Service) Update(ctx context.Context, actor products.Actor, productID, id uuid.UUID, input ReviewInput) (*Review, error) {
+	var review *Review
+	err := s.write(ctx, productID, func(tx ReviewRepository) error {
+		var err error
+		review, err = findOwn(ctx, tx, actor, productID, id)
+		if err != nil {
+			return err
+		}
+
+		if err := apply(review, input); err != nil {
+			return err
+		}
+		return tx.Update(ctx, review)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// Delete deletes a review. Only its author can delete it.
+func (s *Service) Delete(ctx context.Context, actor products.Actor, productID, id uuid.UUID) error {
+	return s.write(ctx, productID, func(tx ReviewRepository) error {
+		if _, err := findOwn(ctx, tx, actor, productID, id); err != nil {
+			return err
+		}
+		return tx.Delete(ctx, productID, id)
+	})
+}
+
+// SetHidden hides a review from listings and the product's rating, or shows it again.
+// It is meant for moderators.
+func (s *Service) SetHidden(ctx context.Context, moderatorID, productID, id uuid.UUID, hidden bool) (*Review, error) {
+	var review *Review
+	err := s.write(ctx, productID, func(tx ReviewRepository) error {
+		var err error
+		review, err = findReview(ctx, tx, productID, id)
+		if err != nil {
+			return err
+		}
+
+		review.HiddenAt, review.HiddenBy = nil, nil
+		if hidden {
+			now := s.now()
+			review.HiddenAt, review.HiddenBy = &now, &moderatorID
+		}
+		return tx.Update(ctx, review)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// write runs fn in a transaction holding the product's lock and then refreshes the product's rating.
+func (s *Service) write(ctx context.Context, productID uuid.UUID, fn func(tx ReviewRepository) error) error {
+	return s.repo.Transaction(ctx, func(tx ReviewRepository) error {
+		if err := tx.LockProduct(ctx, productID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		return tx.RefreshProductRating(ctx, productID)
+	})
+}
+
+// findReview finds a review of the product.
+func findReview(ctx context.Context, repo ReviewRepository, productID, id uuid.UUID) (*Review, error) {
+	review, err := repo.FindByID(ctx, productID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReviewNotFound
+	}
+	return review, err
+}
+
+// findOwn finds a review of the product and checks the actor wrote it.
+func findOwn(ctx context.Context, repo ReviewRepository, actor products.Actor, productID, id uuid.UUID) (*Review, error) {
+	review, err := findReview(ctx, repo, productID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.UserID != actor.UserID {
+		return nil, ErrForbidden
+	}
+
+	return review, nil
+}
+
+// apply validates input and copies it onto review.
+func apply(review *Review, input ReviewInput) error {
+	if input.Rating < 1 || input.Rating > 5 {
+		return ErrInvalidRating
+	}
+
+	review.Rating = input.Rating
+	review.Title = strings.TrimSpace(input.Title)
+	review.Body = strings.TrimSpace(input.Body)
+	return nil
+}
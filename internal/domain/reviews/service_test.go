@@ -0,0 +1,186 @@
+package reviews
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockReviewRepository is a mock implementation of ReviewRepository.
+// Its transactions hand out the mock itself.
+type MockReviewRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewRepository) Create(ctx context.Context, review *Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) FindByID(ctx context.Context, productID, id uuid.UUID) (*Review, error) {
+	args := m.Called(ctx, productID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) Update(ctx context.Context, review *Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) Delete(ctx context.Context, productID, id uuid.UUID) error {
+	args := m.Called(ctx, productID, id)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) List(ctx context.Context, productID uuid.UUID, includeHidden bool) ([]Review, error) {
+	args := m.Called(ctx, productID, includeHidden)
+	return args.Get(0).([]Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) LockProduct(ctx context.Context, productID uuid.UUID) error {
+	args := m.Called(ctx, productID)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) RefreshProductRating(ctx context.Context, productID uuid.UUID) error {
+	args := m.Called(ctx, productID)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) Transaction(ctx context.Context, fn func(repo ReviewRepository) error) error {
+	return fn(m)
+}
+
+func TestReviewService_Create(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	productID := uuid.New()
+
+	// Test case 1: Successful review refreshes the product's rating
+	mockRepo := new(MockReviewRepository)
+	service := NewService(mockRepo)
+	mockRepo.On("LockProduct", ctx, productID).Return(nil).Once()
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*reviews.Review")).Return(nil).Once()
+	mockRepo.On("RefreshProductRating", ctx, productID).Return(nil).Once()
+	review, err := service.Create(ctx, userID, productID, ReviewInput{Rating: 4, Title: "  Does the job ", Body: "Keeps coffee hot. "})
+	assert.NoError(t, err)
+	assert.Equal(t, userID, review.UserID)
+	assert.Equal(t, 4, review.Rating)
+	assert.Equal(t, "Does the job", review.Title)
+	assert.Equal(t, "Keeps coffee hot.", review.Body)
+	mockRepo.AssertExpectations(t)
+
+	// Test case 2: Reviewing the same product twice
+	mockRepo.On("LockProduct", ctx, productID).Return(nil).Once()
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*reviews.Review")).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.Create(ctx, userID, productID, ReviewInput{Rating: 5, Title: "Again"})
+	assert.ErrorIs(t, err, ErrAlreadyReviewed)
+	mockRepo.AssertNumberOfCalls(t, "RefreshProductRating", 1)
+
+	// Test case 3: Unknown product
+	missingID := uuid.New()
+	mockRepo.On("LockProduct", ctx, missingID).Return(gorm.ErrRecordNotFound).Once()
+	_, err = service.Create(ctx, userID, missingID, ReviewInput{Rating: 5, Title: "Great"})
+	assert.ErrorIs(t, err, ErrProductNotFound)
+
+	// Test case 4: Rating out of range
+	_, err = service.Create(ctx, userID, productID, ReviewInput{Rating: 6, Title: "Great"})
+	assert.ErrorIs(t, err, ErrInvalidRating)
+	mockRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestReviewService_UpdateAndDelete(t *testing.T) {
+	ctx := context.Background()
+	author := products.Actor{UserID: uuid.New(), Role: "user"}
+	other := products.Actor{UserID: uuid.New(), Role: "admin"}
+	productID := uuid.New()
+	reviewID := uuid.New()
+	existing := func() *Review {
+		return &Review{ID: reviewID, ProductID: productID, UserID: author.UserID, Rating: 2, Title: "Meh"}
+	}
+
+	// Test case 1: The author edits their review
+	mockRepo := new(MockReviewRepository)
+	service := NewService(mockRepo)
+	mockRepo.On("LockProduct", ctx, productID).Return(nil).Once()
+	mockRepo.On("FindByID", ctx, productID, reviewID).Return(existing(), nil).Once()
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*reviews.Review")).Return(nil).Once()
+	mockRepo.On("RefreshProductRating", ctx, productID).Return(nil).Once()
+	review, err := service.Update(ctx, author, productID, reviewID, ReviewInput{Rating: 5, Title: "Grew on me"})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, review.Rating)
+	assert.Equal(t, "Grew on me", review.Title)
+	mockRepo.AssertExpectations(t)
+
+	// Test case 2: Someone else, even an admin, cannot edit or delete it
+	mockRepo.On("LockProduct", ctx, productID).Return(nil).Twice()
+	mockRepo.On("FindByID", ctx, productID, reviewID).Return(existing(), nil).Twice()
+	_, err = service.Update(ctx, other, productID, reviewID, ReviewInput{Rating: 1, Title: "Bad"})
+	assert.ErrorIs(t, err, ErrForbidden)
+	err = service.Delete(ctx, other, productID, reviewID)
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+
+	// Test case 3: The author deletes their review
+	mockRepo.On("LockProduct", ctx, productID).Return(nil).Once()
+	mockRepo.On("FindByID", ctx, productID, reviewID).Return(existing(), nil).Once()
+	mockRepo.On("Delete", ctx, productID, reviewID).Return(nil).Once()
+	mockRepo.On("RefreshProductRating", ctx, productID).Return(nil).Once()
+	err = service.Delete(ctx, author, productID, reviewID)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// Test case 4: Review not found
+	mockRepo.On("LockProduct", ctx, productID).Return(nil).Once()
+	mockRepo.On("FindByID", ctx, productID, reviewID).Return(nil, gorm.ErrRecordNotFound).Once()
+	err = service.Delete(ctx, author, productID, reviewID)
+	assert.ErrorIs(t, err, ErrReviewNotFound)
+}
+
+func TestReviewService_SetHidden(t *testing.T) {
+	ctx := context.Background()
+	moderatorID := uuid.New()
+	productID := uuid.New()
+	reviewID := uuid.New()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mockRepo := new(MockReviewRepository)
+	service := NewService(mockRepo)
+	service.now = func() time.Time { return now }
+
+	// Test case 1: Hiding a review records the moderator and refreshes the rating
+	mockRepo.On("LockProduct", ctx, productID).Return(nil).Twice()
+	mockRepo.On("FindByID", ctx, productID, reviewID).Return(&Review{ID: reviewID, ProductID: productID, Rating: 1}, nil).Twice()
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*reviews.Review")).Return(nil).Twice()
+	mockRepo.On("RefreshProductRating", ctx, productID).Return(nil).Twice()
+	review, err := service.SetHidden(ctx, moderatorID, productID, reviewID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, now, *review.HiddenAt)
+	assert.Equal(t, moderatorID, *review.HiddenBy)
+
+	// Test case 2: Unhiding clears the moderation state
+	review, err = service.SetHidden(ctx, moderatorID, productID, reviewID, false)
+	assert.NoError(t, err)
+	assert.Nil(t, review.HiddenAt)
+	assert.Nil(t, review.HiddenBy)
+	mockRepo.AssertExpectations(t)
+
+	// Test case 3: Hidden reviews are only listed for admins
+	mockRepo.On("List", ctx, productID, true).Return([]Review{}, nil).Once()
+	mockRepo.On("List", ctx, productID, false).Return([]Review{}, nil).Once()
+	_, err = service.List(ctx, products.Actor{UserID: moderatorID, Role: "admin"}, productID)
+	assert.NoError(t, err)
+	_, err = service.List(ctx, products.Actor{UserID: uuid.New(), Role: "user"}, productID)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
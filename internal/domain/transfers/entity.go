@@ -0,0 +1,73 @@
+package transfers
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transfer statuses. Transfers start pending and are accepted or declined by the
+// recipient, or cancelled by the sender; transfers created by admins are accepted at once.
+const (
+	StatusPending   = "pending"
+	StatusAccepted  = "accepted"
+	StatusDeclined  = "declined"
+	StatusCancelled = "cancelled"
+)
+
+// Transfer moves the ownership of one or more products from one user to another.
+// Resolved transfers are kept as the audit trail of ownership changes; user IDs are nil once the user is deleted.
+type Transfer struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	FromUserID  *uuid.UUID `gorm:"type:uuid" json:"from_user_id"`
+	ToUserID    *uuid.UUID `gorm:"type:uuid" json:"to_user_id"`
+	Status      string     `gorm:"type:product_transfer_status;not null;default:pending" json:"status" example:"pending"`
+	RequestedBy *uuid.UUID `gorm:"type:uuid" json:"requested_by"`
+	ResolvedBy  *uuid.UUID `gorm:"type:uuid" json:"resolved_by"`
+	ResolvedAt  *time.Time `json:"resolved_at"`
+	Items       []Item     `gorm:"foreignKey:TransferID" json:"items"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName overrides the table name used by Transfer.
+func (Transfer) TableName() string {
+	return "product_transfers"
+}
+
+// Item is a product included in a transfer, with its name when the transfer was requested.
+// Transferred is set when the transfer is accepted and the product still belonged to the sender.
+// ProductID is nil once the product has been purged.
+type Item struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"-"`
+	TransferID  uuid.UUID  `gorm:"type:uuid;not null" json:"-"`
+	ProductID   *uuid.UUID `gorm:"type:uuid" json:"product_id"`
+	ProductName string     `gorm:"type:varchar(120);not null" json:"product_name"`
+	Transferred bool       `gorm:"not null;default:false" json:"transferred"`
+}
+
+// TableName overrides the table name used by Item.
+func (Item) TableName() string {
+	return "product_transfer_items"
+}
+
+// TransferInput describes the products to transfer. A nil FromUserID means the
+// requesting user; an empty ProductIDs means all of the sender's products.
+type TransferInput struct {
+	FromUserID *uuid.UUID
+	ToUserID   uuid.UUID
+	ProductIDs []uuid.UUID
+}
+
+// ListFilter narrows a transfer listing. Nil fields are not filtered on.
+type ListFilter struct {
+	// UserID lists the transfers the user sends, receives or requested.
+	UserID *uuid.UUID
+	Status *string
+}
+
+// OwnedProduct is a product of the sender selected for a transfer.
+type OwnedProduct struct {
+	ID   uuid.UUID
+	Name string
+}
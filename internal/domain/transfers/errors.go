@@ -0,0 +1,20 @@
+package transfers
+
+import "errors"
+
+var (
+	// ErrNoProducts is returned when a transfer would not include any product.
+	ErrNoProducts = errors.New("transfer must include at least one product")
+	// ErrProductNotOwned is returned when a product to transfer does not exist, does not belong to the sender or is archived.
+	ErrProductNotOwned = errors.New("product does not belong to the sender or is archived")
+	// ErrRecipientNotFound is returned when the receiving user does not exist.
+	ErrRecipientNotFound = errors.New("recipient not found")
+	// ErrSameUser is returned when the sender and the recipient are the same user.
+	ErrSameUser = errors.New("sender and recipient must be different users")
+	// ErrNotPending is returned when accepting, declining or cancelling a transfer that has already been resolved.
+	ErrNotPending = errors.New("transfer is no longer pending")
+	// ErrCodeConflict is returned when accepting a transfer of a product whose SKU or barcode the recipient already uses.
+	ErrCodeConflict = errors.New("recipient already has a product with the same SKU or barcode")
+	// ErrForbidden is returned when the actor may not view or act on the transfer.
+	ErrForbidden = errors.New("not allowed to access this transfer")
+)
@@ -0,0 +1,272 @@
+package transfers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go-crud-api/internal/http/middleware"
+	"go-crud-api/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TransferHandler handles product transfer requests.
+type TransferHandler struct {
+	service  *Service
+	validate *validator.Validate
+}
+
+// NewTransferHandler creates a new TransferHandler.
+func NewTransferHandler(service *Service) *TransferHandler {
+	return &TransferHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreateTransferRequest is the request payload for transferring products.
+type CreateTransferRequest struct {
+	FromUserID *uuid.UUID  `json:"from_user_id,omitempty"`
+	ToUserID   uuid.UUID   `json:"to_user_id" validate:"required" example:"8f14e45f-ceea-467f-a8a8-0a1b2c3d4e5f"`
+	ProductIDs []uuid.UUID `json:"product_ids,omitempty" validate:"max=1000"`
+}
+
+// CreateTransfer handles requesting a product transfer.
+// @Summary Transfer products
+// @Description Transfer products to another user. Omit product_ids to transfer all of the sender's products. The sender defaults to the requesting user; only admins can transfer another user's products (from_user_id). Transfers stay pending until the recipient accepts them, except transfers created by admins, which are accepted at once.
+// @Tags Transfers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param transfer body CreateTransferRequest true "Transfer"
+// @Success 201 {object} web.Response{data=Transfer} "Transfer created successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request, validation error or products not owned by the sender"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not the sender)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Recipient not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "The recipient already uses a SKU or barcode of the products (transfers accepted at once)"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/transfers [post]
+func (h *TransferHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	var req CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.service.Create(r.Context(), actor, TransferInput(req))
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not create transfer")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: transfer})
+}
+
+// ListTransfers handles listing product transfers.
+// @Summary List transfers
+// @Description List product transfers, newest first. Users see the transfers they send, receive or requested; admins see every transfer, optionally filtered by user_id.
+// @Tags Transfers
+// @Security BearerAuth
+// @Produce json
+// @Param user_id query string false "User ID (admin only)"
+// @Param status query string false "Transfer status" Enums(pending, accepted, declined, cancelled)
+// @Success 200 {object} web.Response{data=[]Transfer} "List of transfers"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid user ID or status"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/transfers [get]
+func (h *TransferHandler) ListTransfers(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	filter := ListFilter{UserID: &actor.UserID}
+	if actor.IsAdmin() {
+		filter.UserID = nil
+		if userParam := r.URL.Query().Get("user_id"); userParam != "" {
+			id, err := uuid.Parse(userParam)
+			if err != nil {
+				web.RespondWithError(w, "bad_request", "Invalid user ID format", http.StatusBadRequest)
+				return
+			}
+			filter.UserID = &id
+		}
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		switch status {
+		case StatusPending, StatusAccepted, StatusDeclined, StatusCancelled:
+			filter.Status = &status
+		default:
+			web.RespondWithError(w, "bad_request", "Invalid transfer status", http.StatusBadRequest)
+			return
+		}
+	}
+
+	transfers, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch transfers")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: transfers})
+}
+
+// GetTransferByID handles fetching a transfer by its ID.
+// @Summary Get transfer by ID
+// @Description Get a product transfer with its products. Users can only see the transfers they send, receive or requested.
+// @Tags Transfers
+// @Security BearerAuth
+// @Produce json
+// @Param transferID path string true "Transfer ID"
+// @Success 200 {object} web.Response{data=Transfer} "Transfer details"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid transfer ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not party to the transfer)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Transfer not found"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/transfers/{transferID} [get]
+func (h *TransferHandler) GetTransferByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "transferID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid transfer ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.service.FindByID(r.Context(), actor, id)
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not fetch transfer")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: transfer})
+}
+
+// AcceptTransfer handles accepting a pending transfer.
+// @Summary Accept a transfer
+// @Description Accept a pending transfer and take ownership of its products. Only the recipient or an admin can accept it. Products that no longer belong to the sender are left out and reported with transferred=false.
+// @Tags Transfers
+// @Security BearerAuth
+// @Produce json
+// @Param transferID path string true "Transfer ID"
+// @Success 200 {object} web.Response{data=Transfer} "Transfer accepted"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid transfer ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not the recipient)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Transfer not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Transfer is no longer pending, or the recipient already uses a SKU or barcode of its products"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/transfers/{transferID}/accept [post]
+func (h *TransferHandler) AcceptTransfer(w http.ResponseWriter, r *http.Request) {
+	h.resolveTransfer(w, r, h.service.Accept, "Could not accept transfer")
+}
+
+// DeclineTransfer handles declining a pending transfer.
+// @Summary Decline a transfer
+// @Description Decline a pending transfer; its products stay with the sender. Only the recipient or an admin can decline it.
+// @Tags Transfers
+// @Security BearerAuth
+// @Produce json
+// @Param transferID path string true "Transfer ID"
+// @Success 200 {object} web.Response{data=Transfer} "Transfer declined"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid transfer ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not the recipient)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Transfer not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Transfer is no longer pending"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/transfers/{transferID}/decline [post]
+func (h *TransferHandler) DeclineTransfer(w http.ResponseWriter, r *http.Request) {
+	h.resolveTransfer(w, r, h.service.Decline, "Could not decline transfer")
+}
+
+// CancelTransfer handles cancelling a pending transfer.
+// @Summary Cancel a transfer
+// @Description Withdraw a pending transfer. Only the sender, the user who requested it or an admin can cancel it.
+// @Tags Transfers
+// @Security BearerAuth
+// @Produce json
+// @Param transferID path string true "Transfer ID"
+// @Success 200 {object} web.Response{data=Transfer} "Transfer cancelled"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid transfer ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not the sender)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Transfer not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Transfer is no longer pending"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/transfers/{transferID}/cancel [post]
+func (h *TransferHandler) CancelTransfer(w http.ResponseWriter, r *http.Request) {
+	h.resolveTransfer(w, r, h.service.Cancel, "Could not cancel transfer")
+}
+
+// resolveTransfer applies resolve to the transfer in the request path.
+func (h *TransferHandler) resolveTransfer(w http.ResponseWriter, r *http.Request, resolve func(ctx context.Context, actor middleware.Actor, id uuid.UUID) (*Transfer, error), message string) {
+	id, err := uuid.Parse(chi.URLParam(r, "transferID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid transfer ID format", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := middleware.ActorFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	transfer, err := resolve(r.Context(), actor, id)
+	if err != nil {
+		respondWithWriteError(w, r, err, message)
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: transfer})
+}
+
+// respondWithWriteError maps service errors to HTTP responses.
+func respondWithWriteError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	apiErr, status := writeError(err, message)
+	if status == http.StatusInternalServerError {
+		web.RespondWithServerError(w, r, err, message)
+		return
+	}
+	web.RespondWithError(w, apiErr.Code, apiErr.Message, status)
+}
+
+// writeError maps a service error to the API error and status code reported for it.
+func writeError(err error, message string) (*web.ApiError, int) {
+	switch {
+	case errors.Is(err, ErrNoProducts), errors.Is(err, ErrProductNotOwned), errors.Is(err, ErrSameUser):
+		return &web.ApiError{Code: "validation_error", Message: err.Error()}, http.StatusBadRequest
+	case errors.Is(err, ErrForbidden):
+		return &web.ApiError{Code: "forbidden", Message: err.Error()}, http.StatusForbidden
+	case errors.Is(err, ErrRecipientNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Recipient not found"}, http.StatusNotFound
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return &web.ApiError{Code: "not_found", Message: "Transfer not found"}, http.StatusNotFound
+	case errors.Is(err, ErrNotPending), errors.Is(err, ErrCodeConflict):
+		return &web.ApiError{Code: "conflict", Message: err.Error()}, http.StatusConflict
+	default:
+		return &web.ApiError{Code: "internal_error", Message: message}, http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,31 @@
+package transfers
+
+import (
+	"context"
+
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+)
+
+// TransferRepository defines the interface for product transfer data operations.
+type TransferRepository interface {
+	// Create inserts the transfer together with its items.
+	Create(ctx context.Context, transfer *Transfer) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Transfer, error)
+	// FindByIDForUpdate finds a transfer with its items and locks its row until the transaction ends.
+	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*Transfer, error)
+	// List returns the transfers matching the filter, newest first.
+	List(ctx context.Context, filter ListFilter) ([]Transfer, error)
+	// UpdateStatus saves the status and resolution of a transfer.
+	UpdateStatus(ctx context.Context, transfer *Transfer) error
+	// ListOwnedProducts returns the owner's products among ids, or all of them when ids is empty.
+	// Products in the trash and archived products are left out.
+	ListOwnedProducts(ctx context.Context, ownerID uuid.UUID, ids []uuid.UUID) ([]OwnedProduct, error)
+	UserExists(ctx context.Context, id uuid.UUID) (bool, error)
+	// MarkTransferred marks the items of the given products as transferred.
+	MarkTransferred(ctx context.Context, transferID uuid.UUID, productIDs []uuid.UUID) error
+	// Transaction runs fn in a database transaction shared by the transfer and product repositories
+	// it is given, so ownership changes commit or roll back together with the transfer.
+	Transaction(ctx context.Context, fn func(repo TransferRepository, products products.ProductRepository) error) error
+}
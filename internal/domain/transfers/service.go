@@ -0,0 +1,246 @@
+package transfers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+)
+
+// Service defines the product transfer service.
+type Service struct {
+	repo TransferRepository
+	now  func() time.Time
+}
+
+// NewService creates a new transfer service.
+func NewService(repo TransferRepository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// Create requests the transfer of products to another user. Users can only transfer their own
+// products; admins can transfer anyone's, and their transfers are accepted at once.
+// Products in the trash and archived products are not included when transferring all of the sender's products.
+func (s *Service) Create(ctx context.Context, actor products.Actor, input TransferInput) (*Transfer, error) {
+	fromUserID := actor.UserID
+	if input.FromUserID != nil {
+		fromUserID = *input.FromUserID
+	}
+	if fromUserID != actor.UserID && !actor.IsAdmin() {
+		return nil, ErrForbidden
+	}
+	if fromUserID == input.ToUserID {
+		return nil, ErrSameUser
+	}
+
+	ids := dedupe(input.ProductIDs)
+	transfer := &Transfer{
+		FromUserID:  &fromUserID,
+		ToUserID:    &input.ToUserID,
+		Status:      StatusPending,
+		RequestedBy: &actor.UserID,
+	}
+
+	err := s.repo.Transaction(ctx, func(tx TransferRepository, stock products.ProductRepository) error {
+		exists, err := tx.UserExists(ctx, input.ToUserID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrRecipientNotFound
+		}
+
+		owned, err := tx.ListOwnedProducts(ctx, fromUserID, ids)
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 && len(owned) != len(ids) {
+			return ErrProductNotOwned
+		}
+		if len(owned) == 0 {
+			return ErrNoProducts
+		}
+
+		for _, product := range owned {
+			productID := product.ID
+			transfer.Items = append(transfer.Items, Item{ProductID: &productID, ProductName: product.Name})
+		}
+		if err := tx.Create(ctx, transfer); err != nil {
+			return err
+		}
+
+		if actor.IsAdmin() {
+			return s.accept(ctx, tx, stock, transfer, actor.UserID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// List returns the transfers matching the filter, newest first.
+func (s *Service) List(ctx context.Context, filter ListFilter) ([]Transfer, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// FindByID finds a transfer. Users can only see the transfers they send or receive.
+func (s *Service) FindByID(ctx context.Context, actor products.Actor, id uuid.UUID) (*Transfer, error) {
+	transfer, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isParty(transfer, actor) {
+		return nil, ErrForbidden
+	}
+
+	return transfer, nil
+}
+
+// Accept gives the products of a pending transfer to its recipient. Only the recipient or an admin
+// can accept it. Products that no longer belong to the sender, are in the trash or are archived
+// are left out. Each change of owner is recorded as a revision of the product.
+func (s *Service) Accept(ctx context.Context, actor products.Actor, id uuid.UUID) (*Transfer, error) {
+	return s.resolve(ctx, id, func(tx TransferRepository, stock products.ProductRepository, transfer *Transfer) error {
+		if !isRecipient(transfer, actor) && !actor.IsAdmin() {
+			return ErrForbidden
+		}
+		return s.accept(ctx, tx, stock, transfer, actor.UserID)
+	})
+}
+
+// Decline turns down a pending transfer. Only the recipient or an admin can decline it.
+func (s *Service) Decline(ctx context.Context, actor products.Actor, id uuid.UUID) (*Transfer, error) {
+	return s.resolve(ctx, id, func(tx TransferRepository, _ products.ProductRepository, transfer *Transfer) error {
+		if !isRecipient(transfer, actor) && !actor.IsAdmin() {
+			return ErrForbidden
+		}
+		return s.close(ctx, tx, transfer, StatusDeclined, actor.UserID)
+	})
+}
+
+// Cancel withdraws a pending transfer. Only the sender, the user who requested it or an admin can cancel it.
+func (s *Service) Cancel(ctx context.Context, actor products.Actor, id uuid.UUID) (*Transfer, error) {
+	return s.resolve(ctx, id, func(tx TransferRepository, _ products.ProductRepository, transfer *Transfer) error {
+		if !isSender(transfer, actor) && !actor.IsAdmin() {
+			return ErrForbidden
+		}
+		return s.close(ctx, tx, transfer, StatusCancelled, actor.UserID)
+	})
+}
+
+// resolve locks a pending transfer and lets change resolve it.
+// Users who are not party to the transfer get ErrForbidden before its status is checked.
+func (s *Service) resolve(ctx context.Context, id uuid.UUID, change func(tx TransferRepository, stock products.ProductRepository, transfer *Transfer) error) (*Transfer, error) {
+	var transfer *Transfer
+	err := s.repo.Transaction(ctx, func(tx TransferRepository, stock products.ProductRepository) error {
+		var err error
+		transfer, err = tx.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		return change(tx, stock, transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// accept moves the transfer's products through the products domain, which records each change
+// of owner as a revision, and records the transfer as accepted by actorID.
+func (s *Service) accept(ctx context.Context, tx TransferRepository, stock products.ProductRepository, transfer *Transfer, actorID uuid.UUID) error {
+	if transfer.Status != StatusPending {
+		return ErrNotPending
+	}
+
+	var moved []uuid.UUID
+	if transfer.FromUserID != nil && transfer.ToUserID != nil {
+		ids := make([]uuid.UUID, 0, len(transfer.Items))
+		for _, item := range transfer.Items {
+			if item.ProductID != nil {
+				ids = append(ids, *item.ProductID)
+			}
+		}
+		// Lock the products in a stable order so concurrent transfers cannot deadlock.
+		slices.SortFunc(ids, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+
+		for _, id := range ids {
+			ok, err := products.TransferOwnership(ctx, stock, id, *transfer.FromUserID, *transfer.ToUserID, actorID)
+			if errors.Is(err, products.ErrDuplicateCode) {
+				return ErrCodeConflict
+			}
+			if err != nil {
+				return err
+			}
+			if ok {
+				moved = append(moved, id)
+			}
+		}
+		if len(moved) > 0 {
+			if err := tx.MarkTransferred(ctx, transfer.ID, moved); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range transfer.Items {
+		item := &transfer.Items[i]
+		item.Transferred = item.ProductID != nil && slices.Contains(moved, *item.ProductID)
+	}
+
+	return s.close(ctx, tx, transfer, StatusAccepted, actorID)
+}
+
+// close records the resolution of a pending transfer.
+func (s *Service) close(ctx context.Context, tx TransferRepository, transfer *Transfer, status string, actorID uuid.UUID) error {
+	if transfer.Status != StatusPending {
+		return ErrNotPending
+	}
+
+	now := s.now()
+	transfer.Status = status
+	transfer.ResolvedBy = &actorID
+	transfer.ResolvedAt = &now
+	return tx.UpdateStatus(ctx, transfer)
+}
+
+// isParty reports whether the actor may see the transfer.
+func isParty(transfer *Transfer, actor products.Actor) bool {
+	return actor.IsAdmin() || isSender(transfer, actor) || isRecipient(transfer, actor)
+}
+
+// isSender reports whether the actor sends the transfer or requested it.
+func isSender(transfer *Transfer, actor products.Actor) bool {
+	return isUser(transfer.FromUserID, actor) || isUser(transfer.RequestedBy, actor)
+}
+
+// isRecipient reports whether the actor receives the transfer.
+func isRecipient(transfer *Transfer, actor products.Actor) bool {
+	return isUser(transfer.ToUserID, actor)
+}
+
+func isUser(id *uuid.UUID, actor products.Actor) bool {
+	return id != nil && *id == actor.UserID
+}
+
+// dedupe removes repeated IDs, keeping their order.
+func dedupe(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
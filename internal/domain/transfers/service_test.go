@@ -0,0 +1,288 @@
+package transfers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockTransferRepository is a mock implementation of TransferRepository.
+// Its transactions hand out the mock itself and its Products mock.
+type MockTransferRepository struct {
+	mock.Mock
+	Products *MockProductRepository
+}
+
+func (m *MockTransferRepository) Create(ctx context.Context, transfer *Transfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) FindByID(ctx context.Context, id uuid.UUID) (*Transfer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Transfer), args.Error(1)
+}
+
+func (m *MockTransferRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*Transfer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Transfer), args.Error(1)
+}
+
+func (m *MockTransferRepository) List(ctx context.Context, filter ListFilter) ([]Transfer, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Transfer), args.Error(1)
+}
+
+func (m *MockTransferRepository) UpdateStatus(ctx context.Context, transfer *Transfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) ListOwnedProducts(ctx context.Context, ownerID uuid.UUID, ids []uuid.UUID) ([]OwnedProduct, error) {
+	args := m.Called(ctx, ownerID, ids)
+	return args.Get(0).([]OwnedProduct), args.Error(1)
+}
+
+func (m *MockTransferRepository) UserExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTransferRepository) MarkTransferred(ctx context.Context, transferID uuid.UUID, productIDs []uuid.UUID) error {
+	args := m.Called(ctx, transferID, productIDs)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) Transaction(ctx context.Context, fn func(repo TransferRepository, products products.ProductRepository) error) error {
+	return fn(m, m.Products)
+}
+
+// MockProductRepository mocks the product repository methods used to change a product's owner.
+// Any other method panics through the nil embedded interface.
+type MockProductRepository struct {
+	products.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*products.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*products.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*products.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*products.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdateFields(ctx context.Context, product *products.Product, fields []string) error {
+	args := m.Called(ctx, product, fields)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) CreateRevision(ctx context.Context, revision *products.Revision) error {
+	args := m.Called(ctx, revision)
+	return args.Error(0)
+}
+
+// expectOwner sets up the product reads made while moving a product, returning it with the given owner.
+func (m *MockProductRepository) expectOwner(ctx context.Context, id, ownerID uuid.UUID, status string) *products.Product {
+	product := &products.Product{ID: id, OwnerID: ownerID, Status: status}
+	m.On("FindByIDForUpdate", ctx, id).Return(product, nil).Once()
+	m.On("FindByID", ctx, id).Return(product, nil).Once()
+	return product
+}
+
+func newMocks() (*MockTransferRepository, *MockProductRepository) {
+	stock := new(MockProductRepository)
+	return &MockTransferRepository{Products: stock}, stock
+}
+
+func TestTransferService_Create(t *testing.T) {
+	ctx := context.Background()
+	owner := products.Actor{UserID: uuid.New(), Role: "user"}
+	admin := products.Actor{UserID: uuid.New(), Role: "admin"}
+	recipientID := uuid.New()
+	mugID, lampID := uuid.New(), uuid.New()
+	owned := []OwnedProduct{{ID: mugID, Name: "Mug"}, {ID: lampID, Name: "Lamp"}}
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	// Test case 1: A user's transfer waits for the recipient
+	mockRepo, stock := newMocks()
+	service := NewService(mockRepo)
+	service.now = func() time.Time { return now }
+	mockRepo.On("UserExists", ctx, recipientID).Return(true, nil).Once()
+	mockRepo.On("ListOwnedProducts", ctx, owner.UserID, []uuid.UUID{mugID, lampID}).Return(owned, nil).Once()
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*transfers.Transfer")).Return(nil).Once()
+	transfer, err := service.Create(ctx, owner, TransferInput{ToUserID: recipientID, ProductIDs: []uuid.UUID{mugID, lampID, mugID}})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, transfer.Status)
+	assert.Equal(t, owner.UserID, *transfer.FromUserID)
+	assert.Equal(t, owner.UserID, *transfer.RequestedBy)
+	assert.Len(t, transfer.Items, 2)
+	assert.Equal(t, "Mug", transfer.Items[0].ProductName)
+	mockRepo.AssertNotCalled(t, "MarkTransferred", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+
+	// Test case 2: An admin's transfer of all of a user's products is accepted at once,
+	// recording a revision for each product that changes owner
+	mockRepo.On("UserExists", ctx, recipientID).Return(true, nil).Once()
+	mockRepo.On("ListOwnedProducts", ctx, owner.UserID, []uuid.UUID{}).Return(owned, nil).Once()
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*transfers.Transfer")).Return(nil).Once()
+	stock.expectOwner(ctx, mugID, uuid.New(), products.StatusActive)
+	lamp := stock.expectOwner(ctx, lampID, owner.UserID, products.StatusActive)
+	stock.On("UpdateFields", ctx, lamp, []string{"owner_id"}).Return(nil).Once()
+	stock.On("CreateRevision", ctx, mock.MatchedBy(func(rev *products.Revision) bool {
+		return rev.ProductID == lampID && rev.Action == products.RevisionUpdate && *rev.ActorID == admin.UserID
+	})).Return(nil).Once()
+	mockRepo.On("MarkTransferred", ctx, uuid.Nil, []uuid.UUID{lampID}).Return(nil).Once()
+	mockRepo.On("UpdateStatus", ctx, mock.AnythingOfType("*transfers.Transfer")).Return(nil).Once()
+	transfer, err = service.Create(ctx, admin, TransferInput{FromUserID: &owner.UserID, ToUserID: recipientID})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusAccepted, transfer.Status)
+	assert.Equal(t, admin.UserID, *transfer.ResolvedBy)
+	assert.Equal(t, now, *transfer.ResolvedAt)
+	assert.False(t, transfer.Items[0].Transferred)
+	assert.True(t, transfer.Items[1].Transferred)
+	assert.Equal(t, recipientID, lamp.OwnerID)
+	mockRepo.AssertExpectations(t)
+	stock.AssertExpectations(t)
+
+	// Test case 3: Products that do not belong to the sender
+	mockRepo.On("UserExists", ctx, recipientID).Return(true, nil).Once()
+	mockRepo.On("ListOwnedProducts", ctx, owner.UserID, []uuid.UUID{mugID, lampID}).Return(owned[:1], nil).Once()
+	_, err = service.Create(ctx, owner, TransferInput{ToUserID: recipientID, ProductIDs: []uuid.UUID{mugID, lampID}})
+	assert.ErrorIs(t, err, ErrProductNotOwned)
+
+	// Test case 4: A sender without products
+	mockRepo.On("UserExists", ctx, recipientID).Return(true, nil).Once()
+	mockRepo.On("ListOwnedProducts", ctx, owner.UserID, []uuid.UUID{}).Return([]OwnedProduct{}, nil).Once()
+	_, err = service.Create(ctx, owner, TransferInput{ToUserID: recipientID})
+	assert.ErrorIs(t, err, ErrNoProducts)
+
+	// Test case 5: Unknown recipient
+	missingID := uuid.New()
+	mockRepo.On("UserExists", ctx, missingID).Return(false, nil).Once()
+	_, err = service.Create(ctx, owner, TransferInput{ToUserID: missingID})
+	assert.ErrorIs(t, err, ErrRecipientNotFound)
+
+	// Test case 6: Users cannot transfer someone else's products, nor to themselves
+	_, err = service.Create(ctx, owner, TransferInput{FromUserID: &admin.UserID, ToUserID: recipientID})
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.Create(ctx, owner, TransferInput{ToUserID: owner.UserID})
+	assert.ErrorIs(t, err, ErrSameUser)
+	mockRepo.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestTransferService_Resolve(t *testing.T) {
+	ctx := context.Background()
+	sender := products.Actor{UserID: uuid.New(), Role: "user"}
+	recipient := products.Actor{UserID: uuid.New(), Role: "user"}
+	stranger := products.Actor{UserID: uuid.New(), Role: "user"}
+	transferID := uuid.New()
+	productID := uuid.New()
+	pending := func() *Transfer {
+		return &Transfer{
+			ID:          transferID,
+			FromUserID:  &sender.UserID,
+			ToUserID:    &recipient.UserID,
+			RequestedBy: &sender.UserID,
+			Status:      StatusPending,
+			Items:       []Item{{ProductID: &productID, ProductName: "Mug"}},
+		}
+	}
+
+	mockRepo, stock := newMocks()
+	service := NewService(mockRepo)
+
+	// Test case 1: The recipient accepts the transfer
+	mockRepo.On("FindByIDForUpdate", ctx, transferID).Return(pending(), nil).Once()
+	product := stock.expectOwner(ctx, productID, sender.UserID, products.StatusActive)
+	stock.On("UpdateFields", ctx, product, []string{"owner_id"}).Return(nil).Once()
+	stock.On("CreateRevision", ctx, mock.AnythingOfType("*products.Revision")).Return(nil).Once()
+	mockRepo.On("MarkTransferred", ctx, transferID, []uuid.UUID{productID}).Return(nil).Once()
+	mockRepo.On("UpdateStatus", ctx, mock.AnythingOfType("*transfers.Transfer")).Return(nil).Once()
+	transfer, err := service.Accept(ctx, recipient, transferID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusAccepted, transfer.Status)
+	assert.Equal(t, recipient.UserID, *transfer.ResolvedBy)
+	assert.True(t, transfer.Items[0].Transferred)
+	mockRepo.AssertExpectations(t)
+	stock.AssertExpectations(t)
+
+	// Test case 2: The sender cannot accept, and others cannot decline or cancel
+	mockRepo.On("FindByIDForUpdate", ctx, transferID).Return(pending(), nil).Times(3)
+	_, err = service.Accept(ctx, sender, transferID)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.Decline(ctx, stranger, transferID)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.Cancel(ctx, recipient, transferID)
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNumberOfCalls(t, "UpdateStatus", 1)
+
+	// Test case 3: The recipient declines and the sender cancels without moving products
+	mockRepo.On("FindByIDForUpdate", ctx, transferID).Return(pending(), nil).Once()
+	mockRepo.On("FindByIDForUpdate", ctx, transferID).Return(pending(), nil).Once()
+	mockRepo.On("UpdateStatus", ctx, mock.AnythingOfType("*transfers.Transfer")).Return(nil).Twice()
+	transfer, err = service.Decline(ctx, recipient, transferID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusDeclined, transfer.Status)
+	transfer, err = service.Cancel(ctx, sender, transferID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCancelled, transfer.Status)
+	mockRepo.AssertNumberOfCalls(t, "MarkTransferred", 1)
+
+	// Test case 4: Resolved transfers cannot be resolved again
+	resolved := pending()
+	resolved.Status = StatusDeclined
+	mockRepo.On("FindByIDForUpdate", ctx, transferID).Return(resolved, nil).Once()
+	_, err = service.Accept(ctx, recipient, transferID)
+	assert.ErrorIs(t, err, ErrNotPending)
+
+	// Test case 5: Transfer not found
+	mockRepo.On("FindByIDForUpdate", ctx, transferID).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.Cancel(ctx, sender, transferID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// Test case 6: Only parties to the transfer can see it
+	mockRepo.On("FindByID", ctx, transferID).Return(pending(), nil).Twice()
+	_, err = service.FindByID(ctx, recipient, transferID)
+	assert.NoError(t, err)
+	_, err = service.FindByID(ctx, stranger, transferID)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	// Test case 7: Archived products are left with the sender
+	mockRepo.On("FindByIDForUpdate", ctx, transferID).Return(pending(), nil).Once()
+	stock.expectOwner(ctx, productID, sender.UserID, products.StatusArchived)
+	mockRepo.On("UpdateStatus", ctx, mock.AnythingOfType("*transfers.Transfer")).Return(nil).Once()
+	transfer, err = service.Accept(ctx, recipient, transferID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusAccepted, transfer.Status)
+	assert.False(t, transfer.Items[0].Transferred)
+	stock.AssertNumberOfCalls(t, "UpdateFields", 1)
+
+	// Test case 8: A product whose code the recipient already uses fails the whole transfer
+	mockRepo.On("FindByIDForUpdate", ctx, transferID).Return(pending(), nil).Once()
+	product = stock.expectOwner(ctx, productID, sender.UserID, products.StatusActive)
+	stock.On("UpdateFields", ctx, product, []string{"owner_id"}).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.Accept(ctx, recipient, transferID)
+	assert.ErrorIs(t, err, ErrCodeConflict)
+}
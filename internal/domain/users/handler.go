@@ -68,7 +68,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	user, err := h.service.Register(r.Context(), req.Name, req.Email, req.Password)
 	if err != nil {
 		// TODO: Handle specific errors, e.g., email already exists
-		web.RespondWithError(w, "internal_error", "Could not create user", http.StatusInternalServerError)
+		web.RespondWithServerError(w, r, err, "Could not create user")
 		return
 	}
 
@@ -118,17 +118,28 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 // @Tags Users
 // @Security BearerAuth
 // @Produce json
+// @Param fields query string false "Comma-separated fields to return; id is always included" example(name,email)
 // @Success 200 {object} web.Response{data=[]User} "List of users"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid field"
 // @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
 // @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
 // @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
 // @Router /v1/users [get]
 func (h *AuthHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	fields, err := web.ParseFields(r, userFields)
+	if err != nil {
+		web.RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	users, err := h.service.List(r.Context())
 	if err != nil {
-		web.RespondWithError(w, "internal_error", "Could not fetch users", http.StatusInternalServerError)
+		web.RespondWithServerError(w, r, err, "Could not fetch users")
 		return
 	}
 
-	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: users})
+	web.RespondWithFields(w, r, http.StatusOK, fields, users)
 }
+
+// userFields are the fields of a user that can be requested with the fields query parameter.
+var userFields = web.JSONFieldNames(User{})
@@ -0,0 +1,24 @@
+package warehouses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Warehouse is a location that holds product stock.
+type Warehouse struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Code      string    `gorm:"type:varchar(20);not null;unique" json:"code" example:"SP-01"`
+	Name      string    `gorm:"type:varchar(120);not null" json:"name" example:"São Paulo"`
+	Address   string    `gorm:"type:text;not null;default:''" json:"address"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// WarehouseInput holds the editable fields of a warehouse.
+type WarehouseInput struct {
+	Code    string
+	Name    string
+	Address string
+}
@@ -0,0 +1,12 @@
+package warehouses
+
+import "errors"
+
+var (
+	// ErrInvalidCode is returned when a code is not made of letters, digits and single hyphens.
+	ErrInvalidCode = errors.New("code must contain only letters, digits and single hyphens")
+	// ErrCodeTaken is returned when another warehouse already uses the code.
+	ErrCodeTaken = errors.New("code is already in use")
+	// ErrWarehouseHasStock is returned when deleting a warehouse that still holds stock.
+	ErrWarehouseHasStock = errors.New("warehouse still holds stock")
+)
@@ -0,0 +1,210 @@
+package warehouses
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go-crud-api/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WarehouseHandler handles warehouse-related requests.
+type WarehouseHandler struct {
+	service  *Service
+	validate *validator.Validate
+}
+
+// NewWarehouseHandler creates a new WarehouseHandler.
+func NewWarehouseHandler(service *Service) *WarehouseHandler {
+	return &WarehouseHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// WarehouseRequest is the request payload for creating or updating a warehouse.
+// Codes are stored in upper case.
+type WarehouseRequest struct {
+	Code    string `json:"code" validate:"required,max=20" example:"SP-01"`
+	Name    string `json:"name" validate:"required,min=2,max=120" example:"São Paulo"`
+	Address string `json:"address" validate:"max=500" example:"Rua Augusta, 100"`
+}
+
+// CreateWarehouse handles warehouse creation.
+// @Summary Create a warehouse
+// @Description Create a warehouse with a unique code (Admin only)
+// @Tags Warehouses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param warehouse body WarehouseRequest true "Warehouse data"
+// @Success 201 {object} web.Response{data=Warehouse} "Warehouse created successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Code already in use"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/warehouses [post]
+func (h *WarehouseHandler) CreateWarehouse(w http.ResponseWriter, r *http.Request) {
+	var req WarehouseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	warehouse, err := h.service.Create(r.Context(), WarehouseInput(req))
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not create warehouse")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusCreated, web.Response{Data: warehouse})
+}
+
+// ListWarehouses handles fetching all warehouses.
+// @Summary Get all warehouses
+// @Description Get all warehouses, ordered by code
+// @Tags Warehouses
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} web.Response{data=[]Warehouse} "List of warehouses"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/warehouses [get]
+func (h *WarehouseHandler) ListWarehouses(w http.ResponseWriter, r *http.Request) {
+	warehouses, err := h.service.List(r.Context())
+	if err != nil {
+		web.RespondWithServerError(w, r, err, "Could not fetch warehouses")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: warehouses})
+}
+
+// GetWarehouseByID handles fetching a warehouse by its ID.
+// @Summary Get warehouse by ID
+// @Description Get details of a specific warehouse
+// @Tags Warehouses
+// @Security BearerAuth
+// @Produce json
+// @Param warehouseID path string true "Warehouse ID"
+// @Success 200 {object} web.Response{data=Warehouse} "Warehouse details"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid warehouse ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Warehouse not found"
+// @Router /v1/warehouses/{warehouseID} [get]
+func (h *WarehouseHandler) GetWarehouseByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "warehouseID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid warehouse ID format", http.StatusBadRequest)
+		return
+	}
+
+	warehouse, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		web.RespondWithError(w, "not_found", "Warehouse not found", http.StatusNotFound)
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: warehouse})
+}
+
+// UpdateWarehouse handles updating a warehouse.
+// @Summary Update a warehouse
+// @Description Change a warehouse's code, name or address (Admin only)
+// @Tags Warehouses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param warehouseID path string true "Warehouse ID"
+// @Param warehouse body WarehouseRequest true "Warehouse data"
+// @Success 200 {object} web.Response{data=Warehouse} "Warehouse updated successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Bad request or validation error"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Warehouse not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Code already in use"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/warehouses/{warehouseID} [put]
+func (h *WarehouseHandler) UpdateWarehouse(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "warehouseID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid warehouse ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req WarehouseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	warehouse, err := h.service.Update(r.Context(), id, WarehouseInput(req))
+	if err != nil {
+		respondWithWriteError(w, r, err, "Could not update warehouse")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusOK, web.Response{Data: warehouse})
+}
+
+// DeleteWarehouse handles deleting a warehouse.
+// @Summary Delete a warehouse
+// @Description Delete a warehouse that no longer holds stock; move or count out its stock first (Admin only)
+// @Tags Warehouses
+// @Security BearerAuth
+// @Produce json
+// @Param warehouseID path string true "Warehouse ID"
+// @Success 204 "Warehouse deleted successfully"
+// @Failure 400 {object} web.Response{error=web.ApiError} "Invalid warehouse ID format"
+// @Failure 401 {object} web.Response{error=web.ApiError} "Unauthorized"
+// @Failure 403 {object} web.Response{error=web.ApiError} "Forbidden (not admin)"
+// @Failure 404 {object} web.Response{error=web.ApiError} "Warehouse not found"
+// @Failure 409 {object} web.Response{error=web.ApiError} "Warehouse still holds stock"
+// @Failure 500 {object} web.Response{error=web.ApiError} "Internal server error"
+// @Router /v1/warehouses/{warehouseID} [delete]
+func (h *WarehouseHandler) DeleteWarehouse(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "warehouseID"))
+	if err != nil {
+		web.RespondWithError(w, "bad_request", "Invalid warehouse ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		respondWithWriteError(w, r, err, "Could not delete warehouse")
+		return
+	}
+
+	web.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// respondWithWriteError maps errors from warehouse writes to the matching HTTP response.
+func respondWithWriteError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch {
+	case errors.Is(err, ErrInvalidCode):
+		web.RespondWithError(w, "validation_error", err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrCodeTaken):
+		web.RespondWithError(w, "conflict", err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrWarehouseHasStock):
+		web.RespondWithError(w, "conflict", "Move or count out the warehouse's stock first", http.StatusConflict)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		web.RespondWithError(w, "not_found", "Warehouse not found", http.StatusNotFound)
+	default:
+		web.RespondWithServerError(w, r, err, message)
+	}
+}
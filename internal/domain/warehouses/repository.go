@@ -0,0 +1,19 @@
+package warehouses
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// WarehouseRepository defines the interface for warehouse data operations.
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *Warehouse) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Warehouse, error)
+	// List returns every warehouse, ordered by code.
+	List(ctx context.Context) ([]Warehouse, error)
+	Update(ctx context.Context, warehouse *Warehouse) error
+	// Delete deletes a warehouse and its empty stock levels in one transaction. It fails with
+	// gorm.ErrForeignKeyViolated when any product, including those in the trash, has stock in it.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
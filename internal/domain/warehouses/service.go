@@ -0,0 +1,101 @@
+package warehouses
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// codePattern matches uppercase words of letters and digits joined by single hyphens.
+var codePattern = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)*$`)
+
+// Service defines the warehouse service.
+type Service struct {
+	repo WarehouseRepository
+}
+
+// NewService creates a new warehouse service.
+func NewService(repo WarehouseRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Create creates a new warehouse.
+func (s *Service) Create(ctx context.Context, input WarehouseInput) (*Warehouse, error) {
+	warehouse := &Warehouse{}
+	if err := apply(warehouse, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, warehouse); err != nil {
+		return nil, translateError(err)
+	}
+
+	return warehouse, nil
+}
+
+// FindByID finds a warehouse by its ID.
+func (s *Service) FindByID(ctx context.Context, id uuid.UUID) (*Warehouse, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// List returns every warehouse, ordered by code.
+func (s *Service) List(ctx context.Context) ([]Warehouse, error) {
+	return s.repo.List(ctx)
+}
+
+// Update updates a warehouse.
+func (s *Service) Update(ctx context.Context, id uuid.UUID, input WarehouseInput) (*Warehouse, error) {
+	warehouse, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(warehouse, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, warehouse); err != nil {
+		return nil, translateError(err)
+	}
+
+	return warehouse, nil
+}
+
+// Delete deletes a warehouse that no longer holds stock, together with its empty stock levels.
+// Stock movements and transfers keep their history without the warehouse.
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	err := s.repo.Delete(ctx, id)
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrWarehouseHasStock
+	}
+	return err
+}
+
+// apply validates input and copies it onto warehouse. Codes are stored in upper case.
+func apply(warehouse *Warehouse, input WarehouseInput) error {
+	code := strings.ToUpper(strings.TrimSpace(input.Code))
+	if !codePattern.MatchString(code) {
+		return ErrInvalidCode
+	}
+
+	warehouse.Code = code
+	warehouse.Name = input.Name
+	warehouse.Address = input.Address
+	return nil
+}
+
+// translateError maps a unique violation on the code to ErrCodeTaken.
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrCodeTaken
+	}
+	return err
+}
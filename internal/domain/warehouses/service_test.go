@@ -0,0 +1,116 @@
+package warehouses
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockWarehouseRepository is a mock implementation of WarehouseRepository.
+type MockWarehouseRepository struct {
+	mock.Mock
+}
+
+func (m *MockWarehouseRepository) Create(ctx context.Context, warehouse *Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) FindByID(ctx context.Context, id uuid.UUID) (*Warehouse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) List(ctx context.Context) ([]Warehouse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) Update(ctx context.Context, warehouse *Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestWarehouseService_Create(t *testing.T) {
+	repo := new(MockWarehouseRepository)
+	service := NewService(repo)
+
+	ctx := context.Background()
+
+	// Test case 1: Code is stored in upper case
+	repo.On("Create", ctx, mock.AnythingOfType("*warehouses.Warehouse")).Return(nil).Once()
+	warehouse, err := service.Create(ctx, WarehouseInput{Code: " sp-01 ", Name: "São Paulo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SP-01", warehouse.Code)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Invalid code
+	_, err = service.Create(ctx, WarehouseInput{Code: "SP_01", Name: "São Paulo"})
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	// Test case 3: Code already in use
+	repo.On("Create", ctx, mock.AnythingOfType("*warehouses.Warehouse")).Return(gorm.ErrDuplicatedKey).Once()
+	_, err = service.Create(ctx, WarehouseInput{Code: "SP-01", Name: "São Paulo"})
+	assert.ErrorIs(t, err, ErrCodeTaken)
+	repo.AssertExpectations(t)
+}
+
+func TestWarehouseService_Update(t *testing.T) {
+	repo := new(MockWarehouseRepository)
+	service := NewService(repo)
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	// Test case 1: Successful update
+	repo.On("FindByID", ctx, id).Return(&Warehouse{ID: id, Code: "SP-01", Name: "São Paulo"}, nil).Once()
+	repo.On("Update", ctx, mock.AnythingOfType("*warehouses.Warehouse")).Return(nil).Once()
+	warehouse, err := service.Update(ctx, id, WarehouseInput{Code: "SP-02", Name: "Guarulhos", Address: "Rod. Dutra, km 225"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SP-02", warehouse.Code)
+	assert.Equal(t, "Guarulhos", warehouse.Name)
+	repo.AssertExpectations(t)
+
+	// Test case 2: Warehouse not found
+	repo.On("FindByID", ctx, id).Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = service.Update(ctx, id, WarehouseInput{Code: "SP-02", Name: "Guarulhos"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestWarehouseService_Delete(t *testing.T) {
+	repo := new(MockWarehouseRepository)
+	service := NewService(repo)
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	// Test case 1: Successful delete
+	repo.On("FindByID", ctx, id).Return(&Warehouse{ID: id}, nil).Once()
+	repo.On("Delete", ctx, id).Return(nil).Once()
+	assert.NoError(t, service.Delete(ctx, id))
+	repo.AssertExpectations(t)
+
+	// Test case 2: Warehouse still holds stock
+	repo.On("FindByID", ctx, id).Return(&Warehouse{ID: id}, nil).Once()
+	repo.On("Delete", ctx, id).Return(gorm.ErrForeignKeyViolated).Once()
+	assert.ErrorIs(t, service.Delete(ctx, id), ErrWarehouseHasStock)
+	repo.AssertExpectations(t)
+
+	// Test case 3: Warehouse not found
+	repo.On("FindByID", ctx, id).Return(nil, gorm.ErrRecordNotFound).Once()
+	assert.ErrorIs(t, service.Delete(ctx, id), gorm.ErrRecordNotFound)
+	repo.AssertNumberOfCalls(t, "Delete", 2)
+}
@@ -1,11 +1,11 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
 
 	"go-crud-api/pkg/web"
 
-	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
@@ -15,14 +15,12 @@ func HealthCheckHandler(db *gorm.DB) http.HandlerFunc {
 		// Ping the database
 		sqlDB, err := db.DB()
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to get underlying DB from GORM")
-			web.RespondWithError(w, "db_error", "Database connection error", http.StatusInternalServerError)
+			web.RespondWithServerError(w, r, fmt.Errorf("get underlying DB from GORM: %w", err), "Database connection error")
 			return
 		}
 
 		if err := sqlDB.Ping(); err != nil {
-			log.Error().Err(err).Msg("Failed to ping database")
-			web.RespondWithError(w, "db_error", "Database connection error", http.StatusInternalServerError)
+			web.RespondWithServerError(w, r, fmt.Errorf("ping database: %w", err), "Database connection error")
 			return
 		}
 
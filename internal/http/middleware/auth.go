@@ -9,7 +9,8 @@ import (
 	customhttp "go-crud-api/pkg/web"
 	"go-crud-api/pkg/jwt"
 
-	"github.com/rs/zerolog/log"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
 )
 
 // contextKey is a type for context keys to avoid collisions.
@@ -20,6 +21,35 @@ const ( // Define context keys
 	ContextKeyRole   contextKey = "role"
 )
 
+// Actor is the authenticated user a request is made on behalf of.
+type Actor struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+// IsAdmin reports whether the actor has the admin role.
+func (a Actor) IsAdmin() bool {
+	return a.Role == "admin"
+}
+
+// ActorFromContext reads the authenticated user set by AuthMiddleware from the request context.
+// It writes a 401 response and returns false when the user is missing.
+func ActorFromContext(w http.ResponseWriter, r *http.Request) (Actor, bool) {
+	userID, ok := r.Context().Value(ContextKeyUserID).(uuid.UUID)
+	if !ok {
+		customhttp.RespondWithError(w, "unauthorized", "User ID not found in context", http.StatusUnauthorized)
+		return Actor{}, false
+	}
+
+	userRole, ok := r.Context().Value(ContextKeyRole).(string)
+	if !ok {
+		customhttp.RespondWithError(w, "unauthorized", "User role not found in context", http.StatusUnauthorized)
+		return Actor{}, false
+	}
+
+	return Actor{UserID: userID, Role: userRole}, true
+}
+
 // AuthMiddleware validates JWT tokens and adds user info to context.
 func AuthMiddleware(cfg config.Config) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -39,11 +69,13 @@ func AuthMiddleware(cfg config.Config) func(next http.Handler) http.Handler {
 			tokenString := parts[1]
 			claims, err := jwt.ValidateToken(tokenString, cfg.JWTSecret)
 			if err != nil {
-				log.Error().Err(err).Msg("Invalid JWT token")
+				zerolog.Ctx(r.Context()).Error().Err(err).Msg("Invalid JWT token")
 				customhttp.RespondWithError(w, "unauthorized", "Invalid or expired token", http.StatusUnauthorized)
 				return
 			}
 
+			logUser(r, claims.UserID)
+
 			// Add user ID and role to context
 			ctx := context.WithValue(r.Context(), ContextKeyUserID, claims.UserID)
 			ctx = context.WithValue(ctx, ContextKeyRole, claims.Role)
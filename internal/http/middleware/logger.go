@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"go-crud-api/pkg/web"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// RequestLogger puts a logger carrying the request ID set by chi's RequestID middleware in the
+// request context, where handlers and services read it with zerolog.Ctx, and writes an access
+// log entry once the request is handled. The request ID is returned in the X-Request-ID header.
+func RequestLogger(base *zerolog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := chimiddleware.GetReqID(r.Context())
+			w.Header().Set("X-Request-ID", requestID)
+
+			ctx := base.With().Str("request_id", requestID).Logger().WithContext(r.Context())
+			logger := zerolog.Ctx(ctx)
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			start := time.Now()
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				event := logger.Info()
+				if status >= http.StatusInternalServerError {
+					event = logger.Error()
+				}
+				event.
+					Str("method", r.Method).
+					Str("route", chi.RouteContext(r.Context()).RoutePattern()).
+					Int("status", status).
+					Int("bytes", ww.BytesWritten()).
+					Dur("latency", time.Since(start)).
+					Msg("Request handled")
+			}()
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+		})
+	}
+}
+
+// Recoverer recovers from panics in later handlers, logs them with their stack trace against
+// the request and responds with a 500. It goes after RequestLogger, so the panic carries the
+// request ID and the access log records the 500.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rvr := recover()
+			if rvr == nil {
+				return
+			}
+			if rvr == http.ErrAbortHandler {
+				// The client went away; let net/http abort the response.
+				panic(rvr)
+			}
+
+			logger := zerolog.Ctx(r.Context()).With().
+				Interface("panic", rvr).
+				Bytes("stack", debug.Stack()).
+				Logger()
+			if r.Header.Get("Connection") == "Upgrade" {
+				logger.Error().Msg("Recovered from panic")
+				return
+			}
+			r = r.WithContext(logger.WithContext(r.Context()))
+			web.RespondWithServerError(w, r, fmt.Errorf("panic: %v", rvr), "Internal server error")
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// logUser adds the authenticated user to the request's logger, and so to the access log.
+// Requests without a logger of their own leave the default logger untouched.
+func logUser(r *http.Request, userID uuid.UUID) {
+	logger := zerolog.Ctx(r.Context())
+	if logger == zerolog.DefaultContextLogger {
+		return
+	}
+	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+		return c.Stringer("user_id", userID)
+	})
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-crud-api/internal/config"
+	"go-crud-api/pkg/jwt"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logEntries decodes the JSON lines written to buf.
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var entries []map[string]any
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	cfg := config.Config{JWTSecret: "secret"}
+	userID := uuid.New()
+	token, _, err := jwt.GenerateTokens(userID, "user", cfg.JWTSecret, time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	router.Use(chimiddleware.RequestID)
+	router.Use(RequestLogger(&logger))
+	router.Use(Recoverer)
+	router.With(AuthMiddleware(cfg)).Get("/v1/products/{productID}", func(w http.ResponseWriter, r *http.Request) {
+		zerolog.Ctx(r.Context()).Info().Msg("Fetching product")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	})
+	router.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	// Test case 1: The request ID is echoed and shared by the handler's logs and the access log
+	req := httptest.NewRequest(http.MethodGet, "/v1/products/42", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(chimiddleware.RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "req-1", w.Header().Get("X-Request-ID"))
+
+	entries := logEntries(t, &buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Fetching product", entries[0]["message"])
+	assert.Equal(t, "req-1", entries[0]["request_id"])
+	assert.Equal(t, userID.String(), entries[0]["user_id"])
+	access := entries[1]
+	assert.Equal(t, "info", access["level"])
+	assert.Equal(t, "req-1", access["request_id"])
+	assert.Equal(t, http.MethodGet, access["method"])
+	assert.Equal(t, "/v1/products/{productID}", access["route"])
+	assert.EqualValues(t, http.StatusCreated, access["status"])
+	assert.EqualValues(t, 5, access["bytes"])
+	assert.Equal(t, userID.String(), access["user_id"])
+	assert.Contains(t, access, "latency")
+
+	// Test case 2: Without an incoming ID one is generated; anonymous requests have no user
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/products/42", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
+
+	entries = logEntries(t, &buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), entries[0]["request_id"])
+	assert.EqualValues(t, http.StatusUnauthorized, entries[0]["status"])
+	assert.NotContains(t, entries[0], "user_id")
+
+	// Test case 3: Panics are logged against the request and answered with a 500
+	req = httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(chimiddleware.RequestIDHeader, "req-3")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	entries = logEntries(t, &buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "boom", entries[0]["panic"])
+	assert.Equal(t, "panic: boom", entries[0]["error"])
+	assert.Equal(t, "req-3", entries[0]["request_id"])
+	assert.Contains(t, entries[0], "stack")
+	assert.Equal(t, "error", entries[1]["level"])
+	assert.Equal(t, "req-3", entries[1]["request_id"])
+	assert.EqualValues(t, http.StatusInternalServerError, entries[1]["status"])
+}
@@ -2,12 +2,19 @@ package http
 
 import (
 	"go-crud-api/internal/config"
+	"go-crud-api/internal/domain/carts"
+	"go-crud-api/internal/domain/categories"
+	"go-crud-api/internal/domain/orders"
 	"go-crud-api/internal/domain/products"
+	"go-crud-api/internal/domain/reviews"
+	"go-crud-api/internal/domain/transfers"
 	"go-crud-api/internal/domain/users"
+	"go-crud-api/internal/domain/warehouses"
 	"go-crud-api/internal/http/middleware"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
 	httpSwagger "github.com/swaggo/http-swagger/v2" // <- alias necessário
 	"gorm.io/gorm"
 
@@ -15,14 +22,14 @@ import (
 )
 
 // InitRouter initializes and returns a new chi router.
-func InitRouter(cfg config.Config, db *gorm.DB, authHandler *users.AuthHandler, productHandler *products.ProductHandler) *chi.Mux {
+func InitRouter(cfg config.Config, db *gorm.DB, authHandler *users.AuthHandler, productHandler *products.ProductHandler, categoryHandler *categories.CategoryHandler, orderHandler *orders.OrderHandler, cartHandler *carts.CartHandler, reviewHandler *reviews.ReviewHandler, transferHandler *transfers.TransferHandler, warehouseHandler *warehouses.WarehouseHandler) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Middlewares
 	r.Use(chimiddleware.RequestID)
 	r.Use(chimiddleware.RealIP)
-	// r.Use(chimiddleware.Logger) // We'll use our custom zerolog logger
-	r.Use(chimiddleware.Recoverer)
+	r.Use(middleware.RequestLogger(&log.Logger))
+	r.Use(middleware.Recoverer)
 
 	// Health check endpoint
 	r.Get("/healthz", HealthCheckHandler(db))
@@ -36,6 +43,15 @@ func InitRouter(cfg config.Config, db *gorm.DB, authHandler *users.AuthHandler,
 		r.Post("/login", authHandler.Login)
 	})
 
+	// Public catalogue, limited to products on sale
+	r.Route("/public/v1/products", func(r chi.Router) {
+		r.Get("/", productHandler.ListPublicProducts)
+		r.Get("/{productID}", productHandler.GetPublicProduct)
+	})
+
+	// Image files, authorised by the signature in their URL
+	r.Get("/v1/images/*", productHandler.ServeImage)
+
 	// Protected routes
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware(cfg))
@@ -50,9 +66,121 @@ func InitRouter(cfg config.Config, db *gorm.DB, authHandler *users.AuthHandler,
 		r.Route("/v1/products", func(r chi.Router) {
 			r.Get("/", productHandler.ListProducts)
 			r.Post("/", productHandler.CreateProduct)
+			r.Get("/trash", productHandler.ListTrash)
+			r.Get("/low-stock", productHandler.ListLowStock)
+			r.Post("/import", productHandler.ImportProducts)
+			r.Get("/export", productHandler.ExportProducts)
+			r.Get("/by-sku/{sku}", productHandler.GetProductBySKU)
+			r.Get("/by-barcode/{code}", productHandler.GetProductByBarcode)
 			r.Get("/{productID}", productHandler.GetProductByID)
 			r.Put("/{productID}", productHandler.UpdateProduct)
+			r.Patch("/{productID}", productHandler.PatchProduct)
 			r.Delete("/{productID}", productHandler.DeleteProduct)
+			r.Post("/{productID}/restore", productHandler.RestoreProduct)
+			r.Post("/{productID}/stock/adjustments", productHandler.AdjustStock)
+			r.Get("/{productID}/stock/movements", productHandler.ListStockMovements)
+			r.Put("/{productID}/stock/threshold", productHandler.SetReorderThreshold)
+			r.Get("/{productID}/stock/levels", productHandler.ListStockLevels)
+			r.Put("/{productID}/stock/levels/{warehouseID}", productHandler.SetStockLevel)
+			r.Delete("/{productID}/stock/levels/{warehouseID}", productHandler.RemoveStockLevel)
+			r.Post("/{productID}/stock/transfers", productHandler.TransferStock)
+			r.Get("/{productID}/stock/transfers", productHandler.ListStockTransfers)
+			r.Post("/{productID}/activate", productHandler.ActivateProduct)
+			r.Post("/{productID}/discontinue", productHandler.DiscontinueProduct)
+			r.Post("/{productID}/archive", productHandler.ArchiveProduct)
+			r.Get("/{productID}/revisions", productHandler.ListProductRevisions)
+			r.Get("/{productID}/revisions/diff", productHandler.DiffProductRevisions)
+			r.Post("/{productID}/revisions/{revision}/rollback", productHandler.RollbackProduct)
+			r.Get("/{productID}/prices/schedules", productHandler.ListPriceSchedules)
+			r.Post("/{productID}/prices/schedules", productHandler.CreatePriceSchedule)
+			r.Delete("/{productID}/prices/schedules/{scheduleID}", productHandler.CancelPriceSchedule)
+			r.Get("/{productID}/prices/history", productHandler.ListPriceHistory)
+			r.Get("/{productID}/collaborators", productHandler.ListCollaborators)
+			r.Put("/{productID}/collaborators/{userID}", productHandler.SetCollaborator)
+			r.Delete("/{productID}/collaborators/{userID}", productHandler.RemoveCollaborator)
+			r.Put("/{productID}/category", productHandler.SetProductCategory)
+			r.Put("/{productID}/tags", productHandler.SetProductTags)
+			r.Get("/{productID}/images", productHandler.ListProductImages)
+			r.Post("/{productID}/images", productHandler.UploadProductImage)
+			r.Put("/{productID}/images/order", productHandler.ReorderProductImages)
+			r.Delete("/{productID}/images/{imageID}", productHandler.DeleteProductImage)
+			r.Get("/{productID}/variants", productHandler.ListVariants)
+			r.Post("/{productID}/variants", productHandler.CreateVariant)
+			r.Get("/{productID}/variants/{variantID}", productHandler.GetVariant)
+			r.Put("/{productID}/variants/{variantID}", productHandler.UpdateVariant)
+			r.Delete("/{productID}/variants/{variantID}", productHandler.DeleteVariant)
+			r.Get("/{productID}/reviews", reviewHandler.ListReviews)
+			r.Post("/{productID}/reviews", reviewHandler.CreateReview)
+			r.Put("/{productID}/reviews/{reviewID}", reviewHandler.UpdateReview)
+			r.Delete("/{productID}/reviews/{reviewID}", reviewHandler.DeleteReview)
+
+			// Review moderation (Admin only)
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.HasRoleMiddleware("admin"))
+				r.Post("/{productID}/reviews/{reviewID}/hide", reviewHandler.HideReview)
+				r.Post("/{productID}/reviews/{reviewID}/unhide", reviewHandler.UnhideReview)
+			})
+		})
+		r.Post("/v1/products:batch", productHandler.BatchProducts)
+
+		// Category routes (writes are admin only)
+		r.Route("/v1/categories", func(r chi.Router) {
+			r.Get("/", categoryHandler.ListCategories)
+			r.Get("/{categoryID}", categoryHandler.GetCategoryByID)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.HasRoleMiddleware("admin"))
+				r.Post("/", categoryHandler.CreateCategory)
+				r.Put("/{categoryID}", categoryHandler.UpdateCategory)
+				r.Delete("/{categoryID}", categoryHandler.DeleteCategory)
+			})
+		})
+
+		// Warehouse routes (writes are admin only)
+		r.Route("/v1/warehouses", func(r chi.Router) {
+			r.Get("/", warehouseHandler.ListWarehouses)
+			r.Get("/{warehouseID}", warehouseHandler.GetWarehouseByID)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.HasRoleMiddleware("admin"))
+				r.Post("/", warehouseHandler.CreateWarehouse)
+				r.Put("/{warehouseID}", warehouseHandler.UpdateWarehouse)
+				r.Delete("/{warehouseID}", warehouseHandler.DeleteWarehouse)
+			})
+		})
+
+		// Order routes (payment and shipping are admin only)
+		r.Route("/v1/orders", func(r chi.Router) {
+			r.Get("/", orderHandler.ListOrders)
+			r.Post("/", orderHandler.PlaceOrder)
+			r.Get("/{orderID}", orderHandler.GetOrderByID)
+			r.Post("/{orderID}/cancel", orderHandler.CancelOrder)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.HasRoleMiddleware("admin"))
+				r.Post("/{orderID}/pay", orderHandler.PayOrder)
+				r.Post("/{orderID}/ship", orderHandler.ShipOrder)
+			})
+		})
+
+		// Cart routes (the authenticated user's cart)
+		r.Route("/v1/cart", func(r chi.Router) {
+			r.Get("/", cartHandler.GetCart)
+			r.Delete("/", cartHandler.ClearCart)
+			r.Post("/items", cartHandler.AddCartItem)
+			r.Put("/items/{productID}", cartHandler.UpdateCartItem)
+			r.Delete("/items/{productID}", cartHandler.RemoveCartItem)
+			r.Post("/checkout", cartHandler.Checkout)
+		})
+
+		// Product transfer routes
+		r.Route("/v1/transfers", func(r chi.Router) {
+			r.Get("/", transferHandler.ListTransfers)
+			r.Post("/", transferHandler.CreateTransfer)
+			r.Get("/{transferID}", transferHandler.GetTransferByID)
+			r.Post("/{transferID}/accept", transferHandler.AcceptTransfer)
+			r.Post("/{transferID}/decline", transferHandler.DeclineTransfer)
+			r.Post("/{transferID}/cancel", transferHandler.CancelTransfer)
 		})
 	})
 
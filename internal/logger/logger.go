@@ -18,4 +18,7 @@ func InitLogger(appEnv string) {
 	} else {
 		log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger()
 	}
+
+	// Code running outside a request, such as background jobs, logs through the global logger.
+	zerolog.DefaultContextLogger = &log.Logger
 }
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"go-crud-api/internal/domain/carts"
+	"go-crud-api/internal/domain/orders"
+	"go-crud-api/internal/domain/products"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormCartRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCartRepository creates a new GORM cart repository.
+func NewGormCartRepository(db *gorm.DB) carts.CartRepository {
+	return &gormCartRepository{db: db}
+}
+
+func (r *gormCartRepository) itemsQuery(ctx context.Context, userID uuid.UUID) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Select("cart_items.*, products.name AS product_name, products.price AS unit_price, products.currency AS currency").
+		Joins("JOIN products ON products.id = cart_items.product_id AND products.deleted_at IS NULL").
+		Where("cart_items.user_id = ?", userID).
+		Order("cart_items.created_at, cart_items.id")
+}
+
+func (r *gormCartRepository) ListItems(ctx context.Context, userID uuid.UUID) ([]carts.Item, error) {
+	var items []carts.Item
+	if err := r.itemsQuery(ctx, userID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *gormCartRepository) ListItemsForUpdate(ctx context.Context, userID uuid.UUID) ([]carts.Item, error) {
+	var items []carts.Item
+	// Only the cart rows are locked; products are locked in a fixed order when the order is placed.
+	err := r.itemsQuery(ctx, userID).
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "cart_items"}}).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *gormCartRepository) FindItem(ctx context.Context, userID, productID uuid.UUID) (*carts.Item, error) {
+	var item carts.Item
+	err := r.db.WithContext(ctx).Where("user_id = ? AND product_id = ?", userID, productID).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *gormCartRepository) SaveItem(ctx context.Context, item *carts.Item) error {
+	item.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"quantity", "reserved_until", "updated_at"}),
+		}).
+		Create(item).Error
+}
+
+func (r *gormCartRepository) DeleteItem(ctx context.Context, userID, productID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND product_id = ?", userID, productID).Delete(&carts.Item{}).Error
+}
+
+func (r *gormCartRepository) DeleteItems(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&carts.Item{}).Error
+}
+
+func (r *gormCartRepository) ReleaseExpiredReservations(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&carts.Item{}).
+		Where("reserved_until <= ?", now).
+		UpdateColumn("reserved_until", nil)
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormCartRepository) Transaction(ctx context.Context, fn func(repo carts.CartRepository, orderRepo orders.OrderRepository, stock products.ProductRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormCartRepository{db: tx}, &gormOrderRepository{db: tx}, &gormProductRepository{db: tx})
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"go-crud-api/internal/domain/categories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type gormCategoryRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCategoryRepository creates a new GORM category repository.
+func NewGormCategoryRepository(db *gorm.DB) categories.CategoryRepository {
+	return &gormCategoryRepository{db: db}
+}
+
+// categorySubtreeSQL returns a query selecting the IDs of the categories matching
+// rootCondition and of all their subcategories, at any depth.
+func categorySubtreeSQL(rootCondition string) string {
+	return `WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE ` + rootCondition + `
+	UNION ALL
+	SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+) SELECT id FROM subtree`
+}
+
+func (r *gormCategoryRepository) Create(ctx context.Context, category *categories.Category) error {
+	return r.db.WithContext(ctx).Create(category).Error
+}
+
+func (r *gormCategoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*categories.Category, error) {
+	var category categories.Category
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *gormCategoryRepository) FindBySlug(ctx context.Context, slug string) (*categories.Category, error) {
+	var category categories.Category
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *gormCategoryRepository) Update(ctx context.Context, category *categories.Category) error {
+	return r.db.WithContext(ctx).Save(category).Error
+}
+
+func (r *gormCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&categories.Category{}).Error
+}
+
+func (r *gormCategoryRepository) ListWithCounts(ctx context.Context) ([]categories.CategoryWithCount, error) {
+	var cats []categories.CategoryWithCount
+	err := r.db.WithContext(ctx).Raw(`WITH RECURSIVE tree AS (
+		SELECT id AS root_id, id FROM categories
+		UNION ALL
+		SELECT t.root_id, c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+	)
+	SELECT c.*, COUNT(p.id) AS product_count
+	FROM categories c
+	JOIN tree t ON t.root_id = c.id
+	LEFT JOIN products p ON p.category_id = t.id AND p.deleted_at IS NULL
+	GROUP BY c.id
+	ORDER BY c.name`).Scan(&cats).Error
+	if err != nil {
+		return nil, err
+	}
+	return cats, nil
+}
+
+func (r *gormCategoryRepository) ListDescendantIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(categorySubtreeSQL("id = ?"), id).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// The subtree includes the category itself.
+	descendants := ids[:0]
+	for _, descendant := range ids {
+		if descendant != id {
+			descendants = append(descendants, descendant)
+		}
+	}
+	return descendants, nil
+}
+
+func (r *gormCategoryRepository) HasChildren(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&categories.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"go-crud-api/internal/domain/orders"
+	"go-crud-api/internal/domain/products"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewGormOrderRepository creates a new GORM order repository.
+func NewGormOrderRepository(db *gorm.DB) orders.OrderRepository {
+	return &gormOrderRepository{db: db}
+}
+
+func (r *gormOrderRepository) Create(ctx context.Context, order *orders.Order) error {
+	return r.db.WithContext(ctx).Create(order).Error
+}
+
+func (r *gormOrderRepository) FindByID(ctx context.Context, id uuid.UUID) (*orders.Order, error) {
+	var order orders.Order
+	err := r.db.WithContext(ctx).Preload("Items").Where("id = ?", id).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *gormOrderRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*orders.Order, error) {
+	var order orders.Order
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Where("order_id = ?", id).Find(&order.Items).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *gormOrderRepository) List(ctx context.Context, filter orders.ListFilter) ([]orders.Order, error) {
+	query := r.db.WithContext(ctx).Preload("Items")
+	if filter.CustomerID != nil {
+		query = query.Where("customer_id = ?", *filter.CustomerID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	var list []orders.Order
+	if err := query.Order("created_at DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *gormOrderRepository) UpdateStatus(ctx context.Context, order *orders.Order) error {
+	return r.db.WithContext(ctx).
+		Model(order).
+		Select("status", "paid_at", "shipped_at", "cancelled_at", "updated_at").
+		Updates(order).Error
+}
+
+func (r *gormOrderRepository) Transaction(ctx context.Context, fn func(repo orders.OrderRepository, stock products.ProductRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormOrderRepository{db: tx}, &gormProductRepository{db: tx})
+	})
+}
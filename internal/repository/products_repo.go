@@ -2,10 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"go-crud-api/internal/domain/products"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type gormProductRepository struct {
@@ -23,7 +28,43 @@ func (r *gormProductRepository) Create(ctx context.Context, product *products.Pr
 
 func (r *gormProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*products.Product, error) {
 	var product products.Product
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&product).Error
+	err := r.db.WithContext(ctx).Preload("Tags").Where("id = ?", id).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *gormProductRepository) FindByName(ctx context.Context, ownerID uuid.UUID, name string) (*products.Product, error) {
+	var product products.Product
+	err := r.db.WithContext(ctx).Where("owner_id = ? AND name = ?", ownerID, name).Order("created_at").First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *gormProductRepository) FindBySKU(ctx context.Context, ownerID uuid.UUID, sku string) (*products.Product, error) {
+	var product products.Product
+	err := r.db.WithContext(ctx).Preload("Tags").Where("owner_id = ? AND LOWER(sku) = LOWER(?)", ownerID, sku).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *gormProductRepository) FindByGTIN(ctx context.Context, ownerID uuid.UUID, gtin string) (*products.Product, error) {
+	var product products.Product
+	err := r.db.WithContext(ctx).Preload("Tags").Where("owner_id = ? AND LPAD(gtin, 14, '0') = ?", ownerID, gtin).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *gormProductRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*products.Product, error) {
+	var product products.Product
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&product).Error
 	if err != nil {
 		return nil, err
 	}
@@ -31,18 +72,627 @@ func (r *gormProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*pr
 }
 
 func (r *gormProductRepository) Update(ctx context.Context, product *products.Product) error {
-	return r.db.WithContext(ctx).Save(product).Error
+	return r.updateColumns(ctx, product, []string{"*"})
+}
+
+func (r *gormProductRepository) UpdateFields(ctx context.Context, product *products.Product, fields []string) error {
+	return r.updateColumns(ctx, product, append([]string{"updated_at"}, fields...))
+}
+
+// updateColumns writes the given columns and bumps the version, but only if the
+// row is still at the version the product was read with.
+func (r *gormProductRepository) updateColumns(ctx context.Context, product *products.Product, columns []string) error {
+	current := product.Version
+	product.Version++
+
+	result := r.db.WithContext(ctx).
+		Model(product).
+		Where("version = ?", current).
+		Select(append(columns, "version")).
+		Omit("id", "created_at", "deleted_at", clause.Associations).
+		Updates(product)
+	if result.Error != nil {
+		product.Version = current
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		product.Version = current
+		return products.ErrVersionMismatch
+	}
+
+	return nil
 }
 
-func (r *gormProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&products.Product{}, id).Error
+func (r *gormProductRepository) Delete(ctx context.Context, id uuid.UUID, version int) error {
+	query := r.db.WithContext(ctx).Where("id = ?", id)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&products.Product{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 && version > 0 {
+		return products.ErrVersionMismatch
+	}
+
+	return nil
 }
 
-func (r *gormProductRepository) List(ctx context.Context) ([]products.Product, error) {
+func (r *gormProductRepository) List(ctx context.Context, filter products.ListFilter) ([]products.Product, error) {
 	var prods []products.Product
-	err := r.db.WithContext(ctx).Find(&prods).Error
+	err := applyListFilter(r.db.WithContext(ctx), filter).Preload("Tags").Order(listOrder(filter)).Find(&prods).Error
 	if err != nil {
 		return nil, err
 	}
 	return prods, nil
 }
+
+func (r *gormProductRepository) Stream(ctx context.Context, filter products.ListFilter, fn func(product *products.Product) error) error {
+	db := r.db.WithContext(ctx)
+	rows, err := applyListFilter(db.Model(&products.Product{}), filter).Order(listOrder(filter)).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product products.Product
+		if err := db.ScanRows(rows, &product); err != nil {
+			return err
+		}
+		if err := fn(&product); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// applyListFilter adds the conditions of a product list filter to a query.
+func applyListFilter(db *gorm.DB, filter products.ListFilter) *gorm.DB {
+	if filter.OwnerID != nil {
+		db = db.Where("owner_id = ?", *filter.OwnerID)
+	}
+	if filter.Query != "" {
+		db = db.Where("name ILIKE ?", "%"+likeEscaper.Replace(filter.Query)+"%")
+	}
+	if filter.Currency != "" {
+		db = db.Where("currency = ?", filter.Currency)
+	}
+	if filter.MinPrice != nil {
+		db = db.Where("price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		db = db.Where("price <= ?", *filter.MaxPrice)
+	}
+	if filter.InStock {
+		db = db.Where("stock > 0")
+	}
+	if len(filter.Statuses) > 0 {
+		db = db.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Category != "" {
+		// The category may be given by ID or slug; an unknown category matches nothing.
+		root := "slug = ?"
+		if _, err := uuid.Parse(filter.Category); err == nil {
+			root = "id = ?"
+		}
+		subtree := db.Session(&gorm.Session{NewDB: true}).Raw(categorySubtreeSQL(root), filter.Category)
+		db = db.Where("category_id IN (?)", subtree)
+	}
+	if len(filter.Tags) > 0 {
+		db = db.Where(`id IN (SELECT pt.product_id FROM product_tags pt JOIN tags t ON t.id = pt.tag_id
+			WHERE t.name IN ? GROUP BY pt.product_id HAVING COUNT(*) = ?)`, filter.Tags, len(filter.Tags))
+	}
+	if filter.WarehouseID != nil {
+		db = db.Where("id IN (SELECT product_id FROM product_stock_levels WHERE warehouse_id = ? AND quantity > 0)", *filter.WarehouseID)
+	}
+	return db
+}
+
+// listOrder returns the ORDER BY clause of a product list.
+func listOrder(filter products.ListFilter) string {
+	if filter.Sort == products.SortRating {
+		return "rating_average DESC, rating_count DESC, created_at, id"
+	}
+	return "created_at, id"
+}
+
+// productTag is a row of the product_tags join table.
+type productTag struct {
+	ProductID uuid.UUID
+	TagID     uuid.UUID
+}
+
+func (r *gormProductRepository) ReplaceTags(ctx context.Context, product *products.Product, names []string) error {
+	db := r.db.WithContext(ctx)
+
+	tags := make([]products.Tag, 0, len(names))
+	if len(names) > 0 {
+		for _, name := range names {
+			tags = append(tags, products.Tag{Name: name})
+		}
+		if err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&tags).Error; err != nil {
+			return err
+		}
+		// Tags that already existed come back without an ID from the insert.
+		tags = tags[:0]
+		if err := db.Where("name IN ?", names).Order("name").Find(&tags).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Table("product_tags").Where("product_id = ?", product.ID).Delete(&productTag{}).Error; err != nil {
+		return err
+	}
+	if len(tags) > 0 {
+		links := make([]productTag, 0, len(tags))
+		for _, tag := range tags {
+			links = append(links, productTag{ProductID: product.ID, TagID: tag.ID})
+		}
+		if err := db.Table("product_tags").Create(&links).Error; err != nil {
+			return err
+		}
+	}
+
+	product.Tags = tags
+	return nil
+}
+
+func (r *gormProductRepository) CategoryExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("categories").Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormProductRepository) WarehouseExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("warehouses").Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormProductRepository) ListOwners(ctx context.Context, ids []uuid.UUID) ([]products.Owner, error) {
+	var owners []products.Owner
+	err := r.db.WithContext(ctx).Table("users").Select("id, name").Where("id IN ?", ids).Scan(&owners).Error
+	if err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+func (r *gormProductRepository) ListCollaborators(ctx context.Context, productID uuid.UUID) ([]products.Collaborator, error) {
+	var collaborators []products.Collaborator
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("created_at, user_id").Find(&collaborators).Error
+	if err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+func (r *gormProductRepository) FindCollaborator(ctx context.Context, productID, userID uuid.UUID) (*products.Collaborator, error) {
+	var collaborator products.Collaborator
+	err := r.db.WithContext(ctx).Where("product_id = ? AND user_id = ?", productID, userID).First(&collaborator).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+func (r *gormProductRepository) SaveCollaborator(ctx context.Context, collaborator *products.Collaborator) error {
+	collaborator.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "granted_by", "updated_at"}),
+		}).
+		Create(collaborator).Error
+}
+
+func (r *gormProductRepository) DeleteCollaborator(ctx context.Context, productID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("product_id = ? AND user_id = ?", productID, userID).Delete(&products.Collaborator{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormProductRepository) ListStockLevels(ctx context.Context, productID uuid.UUID) ([]products.StockLevel, error) {
+	var levels []products.StockLevel
+	err := r.db.WithContext(ctx).
+		Select("product_stock_levels.*, warehouses.code AS warehouse_code, warehouses.name AS warehouse_name").
+		Joins("JOIN warehouses ON warehouses.id = product_stock_levels.warehouse_id").
+		Where("product_stock_levels.product_id = ?", productID).
+		Order("product_stock_levels.quantity DESC, warehouses.code").
+		Find(&levels).Error
+	if err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+func (r *gormProductRepository) SaveStockLevel(ctx context.Context, level *products.StockLevel) error {
+	level.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "warehouse_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+		}).
+		Create(level).Error
+}
+
+func (r *gormProductRepository) DeleteStockLevel(ctx context.Context, productID, warehouseID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("product_id = ? AND warehouse_id = ?", productID, warehouseID).Delete(&products.StockLevel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormProductRepository) CreateStockTransfer(ctx context.Context, transfer *products.StockTransfer) error {
+	return r.db.WithContext(ctx).Create(transfer).Error
+}
+
+func (r *gormProductRepository) ListStockTransfers(ctx context.Context, productID uuid.UUID) ([]products.StockTransfer, error) {
+	var transfers []products.StockTransfer
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("created_at DESC").Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (r *gormProductRepository) CreateVariant(ctx context.Context, variant *products.Variant) error {
+	return r.db.WithContext(ctx).Create(variant).Error
+}
+
+func (r *gormProductRepository) FindVariant(ctx context.Context, productID, id uuid.UUID) (*products.Variant, error) {
+	var variant products.Variant
+	err := r.db.WithContext(ctx).Where("id = ? AND product_id = ?", id, productID).First(&variant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (r *gormProductRepository) UpdateVariant(ctx context.Context, variant *products.Variant) error {
+	current := variant.Version
+	variant.Version++
+
+	result := r.db.WithContext(ctx).
+		Model(variant).
+		Where("version = ?", current).
+		Select("sku", "options", "price", "stock", "version", "updated_at").
+		Updates(variant)
+	if result.Error != nil {
+		variant.Version = current
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		variant.Version = current
+		return products.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+func (r *gormProductRepository) DeleteVariant(ctx context.Context, productID, id uuid.UUID, version int) error {
+	query := r.db.WithContext(ctx).Where("id = ? AND product_id = ?", id, productID)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&products.Variant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if version > 0 {
+			return products.ErrVersionMismatch
+		}
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormProductRepository) ListVariants(ctx context.Context, productID uuid.UUID) ([]products.Variant, error) {
+	var variants []products.Variant
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("sku").Find(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+func (r *gormProductRepository) SumVariantStock(ctx context.Context, productID uuid.UUID) (int, int64, error) {
+	var result struct {
+		Total int
+		Count int64
+	}
+	err := r.db.WithContext(ctx).Model(&products.Variant{}).
+		Select("COALESCE(SUM(stock), 0) AS total, COUNT(*) AS count").
+		Where("product_id = ?", productID).
+		Scan(&result).Error
+	return result.Total, result.Count, err
+}
+
+func (r *gormProductRepository) SumReservedStock(ctx context.Context, productID, exceptUserID uuid.UUID) (int, error) {
+	var total int
+	err := r.db.WithContext(ctx).Table("cart_items").
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("product_id = ? AND user_id <> ? AND reserved_until > CURRENT_TIMESTAMP", productID, exceptUserID).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *gormProductRepository) CreateImage(ctx context.Context, image *products.Image) error {
+	return r.db.WithContext(ctx).Create(image).Error
+}
+
+func (r *gormProductRepository) FindImage(ctx context.Context, productID, id uuid.UUID) (*products.Image, error) {
+	var image products.Image
+	err := r.db.WithContext(ctx).Where("id = ? AND product_id = ?", id, productID).First(&image).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *gormProductRepository) ListImages(ctx context.Context, productID uuid.UUID) ([]products.Image, error) {
+	var images []products.Image
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("position").Find(&images).Error
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *gormProductRepository) DeleteImage(ctx context.Context, productID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND product_id = ?", id, productID).Delete(&products.Image{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormProductRepository) SetImagePositions(ctx context.Context, productID uuid.UUID, ids []uuid.UUID) error {
+	db := r.db.WithContext(ctx)
+	for position, id := range ids {
+		err := db.Model(&products.Image{}).
+			Where("id = ? AND product_id = ?", id, productID).
+			Update("position", position).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *gormProductRepository) ListImagesDeletedBefore(ctx context.Context, cutoff time.Time) ([]products.Image, error) {
+	var images []products.Image
+	err := r.db.WithContext(ctx).
+		Joins("JOIN products ON products.id = product_images.product_id").
+		Where("products.deleted_at < ?", cutoff).
+		Find(&images).Error
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *gormProductRepository) CreateRevision(ctx context.Context, revision *products.Revision) error {
+	db := r.db.WithContext(ctx)
+
+	var latest int
+	err := db.Model(&products.Revision{}).
+		Select("COALESCE(MAX(revision), 0)").
+		Where("product_id = ?", revision.ProductID).
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+
+	revision.Revision = latest + 1
+	return db.Create(revision).Error
+}
+
+func (r *gormProductRepository) ListRevisions(ctx context.Context, productID uuid.UUID) ([]products.Revision, error) {
+	var revisions []products.Revision
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("revision DESC").Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *gormProductRepository) FindRevision(ctx context.Context, productID uuid.UUID, revision int) (*products.Revision, error) {
+	var rev products.Revision
+	err := r.db.WithContext(ctx).Where("product_id = ? AND revision = ?", productID, revision).First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// exclusionViolation is the SQLSTATE of an exclusion constraint violation, which gorm does not translate.
+const exclusionViolation = "23P01"
+
+func (r *gormProductRepository) CreatePriceSchedule(ctx context.Context, schedule *products.PriceSchedule) error {
+	err := r.db.WithContext(ctx).Create(schedule).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+		return products.ErrScheduleOverlap
+	}
+	return err
+}
+
+func (r *gormProductRepository) FindPriceSchedule(ctx context.Context, productID, id uuid.UUID) (*products.PriceSchedule, error) {
+	var schedule products.PriceSchedule
+	err := r.db.WithContext(ctx).Where("id = ? AND product_id = ?", id, productID).First(&schedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *gormProductRepository) ListPriceSchedules(ctx context.Context, productID uuid.UUID) ([]products.PriceSchedule, error) {
+	var schedules []products.PriceSchedule
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("effective_from").Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *gormProductRepository) UpdatePriceSchedule(ctx context.Context, schedule *products.PriceSchedule) error {
+	return r.db.WithContext(ctx).
+		Model(schedule).
+		Select("status", "previous_price", "applied_at", "ended_at", "updated_at").
+		Updates(schedule).Error
+}
+
+func (r *gormProductRepository) ListDuePriceSchedules(ctx context.Context, now time.Time) ([]products.PriceSchedule, error) {
+	var schedules []products.PriceSchedule
+	// Schedules of products in the trash wait until the product is restored or purged.
+	err := r.db.WithContext(ctx).
+		Joins("JOIN products ON products.id = price_schedules.product_id AND products.deleted_at IS NULL").
+		Where("(price_schedules.status = ? AND price_schedules.effective_from <= ?) OR (price_schedules.status = ? AND price_schedules.effective_to <= ?)",
+			products.ScheduleStatusPending, now, products.ScheduleStatusActive, now).
+		Order("price_schedules.effective_from").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *gormProductRepository) CreatePriceChange(ctx context.Context, change *products.PriceChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+func (r *gormProductRepository) ListPriceChanges(ctx context.Context, productID uuid.UUID) ([]products.PriceChange, error) {
+	var changes []products.PriceChange
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("created_at DESC").Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// likeEscaper escapes the LIKE wildcards in user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *gormProductRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*products.Product, error) {
+	var product products.Product
+	err := r.db.WithContext(ctx).Unscoped().Preload("Tags").Where("id = ? AND deleted_at IS NOT NULL", id).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *gormProductRepository) ListDeleted(ctx context.Context, ownerID *uuid.UUID) ([]products.Product, error) {
+	query := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL")
+	if ownerID != nil {
+		query = query.Where("owner_id = ?", *ownerID)
+	}
+
+	var prods []products.Product
+	if err := query.Order("deleted_at DESC").Find(&prods).Error; err != nil {
+		return nil, err
+	}
+	return prods, nil
+}
+
+func (r *gormProductRepository) Restore(ctx context.Context, product *products.Product) error {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(product).
+		Where("deleted_at IS NOT NULL").
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	product.DeletedAt = gorm.DeletedAt{}
+	product.Version++
+	return nil
+}
+
+func (r *gormProductRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", cutoff).Delete(&products.Product{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormProductRepository) CreateMovement(ctx context.Context, movement *products.StockMovement) error {
+	return r.db.WithContext(ctx).Create(movement).Error
+}
+
+func (r *gormProductRepository) ListMovements(ctx context.Context, productID uuid.UUID) ([]products.StockMovement, error) {
+	var movements []products.StockMovement
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("created_at DESC").Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+func (r *gormProductRepository) CreateStockAlert(ctx context.Context, alert *products.StockAlert) error {
+	return r.db.WithContext(ctx).Create(alert).Error
+}
+
+func (r *gormProductRepository) ListPendingStockAlerts(ctx context.Context, maxAttempts, limit int) ([]products.StockAlert, error) {
+	var alerts []products.StockAlert
+	err := r.db.WithContext(ctx).
+		Select("stock_alerts.*, users.email AS owner_email").
+		Joins("JOIN users ON users.id = stock_alerts.owner_id").
+		Where("stock_alerts.delivered_at IS NULL AND stock_alerts.attempts < ?", maxAttempts).
+		Order("stock_alerts.created_at").
+		Limit(limit).
+		Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (r *gormProductRepository) UpdateStockAlert(ctx context.Context, alert *products.StockAlert) error {
+	return r.db.WithContext(ctx).
+		Model(alert).
+		Select("attempts", "last_error", "delivered_at").
+		Updates(alert).Error
+}
+
+func (r *gormProductRepository) ListLowStock(ctx context.Context, ownerID *uuid.UUID) ([]products.Product, error) {
+	query := r.db.WithContext(ctx).Preload("Tags").Where("reorder_threshold IS NOT NULL AND stock <= reorder_threshold")
+	if ownerID != nil {
+		query = query.Where("owner_id = ?", *ownerID)
+	}
+
+	var prods []products.Product
+	if err := query.Order("stock, name").Find(&prods).Error; err != nil {
+		return nil, err
+	}
+	return prods, nil
+}
+
+func (r *gormProductRepository) Transaction(ctx context.Context, fn func(repo products.ProductRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormProductRepository{db: tx})
+	})
+}
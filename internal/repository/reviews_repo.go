@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"go-crud-api/internal/domain/products"
+	"go-crud-api/internal/domain/reviews"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewGormReviewRepository creates a new GORM review repository.
+func NewGormReviewRepository(db *gorm.DB) reviews.ReviewRepository {
+	return &gormReviewRepository{db: db}
+}
+
+func (r *gormReviewRepository) Create(ctx context.Context, review *reviews.Review) error {
+	return r.db.WithContext(ctx).Create(review).Error
+}
+
+func (r *gormReviewRepository) FindByID(ctx context.Context, productID, id uuid.UUID) (*reviews.Review, error) {
+	var review reviews.Review
+	err := r.db.WithContext(ctx).Where("id = ? AND product_id = ?", id, productID).First(&review).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *gormReviewRepository) Update(ctx context.Context, review *reviews.Review) error {
+	return r.db.WithContext(ctx).
+		Model(review).
+		Select("rating", "title", "body", "hidden_at", "hidden_by", "updated_at").
+		Updates(review).Error
+}
+
+func (r *gormReviewRepository) Delete(ctx context.Context, productID, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ? AND product_id = ?", id, productID).Delete(&reviews.Review{}).Error
+}
+
+func (r *gormReviewRepository) List(ctx context.Context, productID uuid.UUID, includeHidden bool) ([]reviews.Review, error) {
+	query := r.db.WithContext(ctx).Where("product_id = ?", productID)
+	if !includeHidden {
+		query = query.Where("hidden_at IS NULL")
+	}
+
+	var list []reviews.Review
+	if err := query.Order("created_at DESC, id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *gormReviewRepository) LockProduct(ctx context.Context, productID uuid.UUID) error {
+	var product products.Product
+	return r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", productID).
+		First(&product).Error
+}
+
+func (r *gormReviewRepository) RefreshProductRating(ctx context.Context, productID uuid.UUID) error {
+	// Written directly so the product's version and updated_at are left alone; ratings are not product edits.
+	return r.db.WithContext(ctx).Exec(`UPDATE products SET
+	rating_average = COALESCE((SELECT ROUND(AVG(rating), 2) FROM product_reviews WHERE product_id = @id AND hidden_at IS NULL), 0),
+	rating_count = (SELECT COUNT(*) FROM product_reviews WHERE product_id = @id AND hidden_at IS NULL)
+WHERE id = @id`, map[string]any{"id": productID}).Error
+}
+
+func (r *gormReviewRepository) Transaction(ctx context.Context, fn func(repo reviews.ReviewRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormReviewRepository{db: tx})
+	})
+}
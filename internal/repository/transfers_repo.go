@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"go-crud-api/internal/domain/products"
+	"go-crud-api/internal/domain/transfers"
+	"go-crud-api/internal/domain/users"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormTransferRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTransferRepository creates a new GORM product transfer repository.
+func NewGormTransferRepository(db *gorm.DB) transfers.TransferRepository {
+	return &gormTransferRepository{db: db}
+}
+
+func (r *gormTransferRepository) Create(ctx context.Context, transfer *transfers.Transfer) error {
+	return r.db.WithContext(ctx).Create(transfer).Error
+}
+
+func (r *gormTransferRepository) FindByID(ctx context.Context, id uuid.UUID) (*transfers.Transfer, error) {
+	var transfer transfers.Transfer
+	err := r.db.WithContext(ctx).Preload("Items", orderTransferItems).Where("id = ?", id).First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *gormTransferRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*transfers.Transfer, error) {
+	var transfer transfers.Transfer
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := orderTransferItems(r.db.WithContext(ctx)).Where("transfer_id = ?", id).Find(&transfer.Items).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *gormTransferRepository) List(ctx context.Context, filter transfers.ListFilter) ([]transfers.Transfer, error) {
+	query := r.db.WithContext(ctx).Preload("Items", orderTransferItems)
+	if filter.UserID != nil {
+		query = query.Where("from_user_id = ? OR to_user_id = ? OR requested_by = ?", *filter.UserID, *filter.UserID, *filter.UserID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	var list []transfers.Transfer
+	if err := query.Order("created_at DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *gormTransferRepository) UpdateStatus(ctx context.Context, transfer *transfers.Transfer) error {
+	return r.db.WithContext(ctx).
+		Model(transfer).
+		Select("status", "resolved_by", "resolved_at", "updated_at").
+		Updates(transfer).Error
+}
+
+func (r *gormTransferRepository) ListOwnedProducts(ctx context.Context, ownerID uuid.UUID, ids []uuid.UUID) ([]transfers.OwnedProduct, error) {
+	query := r.db.WithContext(ctx).Table("products").
+		Select("id, name").
+		Where("owner_id = ? AND status <> ? AND deleted_at IS NULL", ownerID, products.StatusArchived)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	var owned []transfers.OwnedProduct
+	if err := query.Order("created_at, id").Scan(&owned).Error; err != nil {
+		return nil, err
+	}
+	return owned, nil
+}
+
+func (r *gormTransferRepository) UserExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&users.User{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormTransferRepository) MarkTransferred(ctx context.Context, transferID uuid.UUID, productIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&transfers.Item{}).
+		Where("transfer_id = ? AND product_id IN ?", transferID, productIDs).
+		Update("transferred", true).Error
+}
+
+func (r *gormTransferRepository) Transaction(ctx context.Context, fn func(repo transfers.TransferRepository, products products.ProductRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormTransferRepository{db: tx}, &gormProductRepository{db: tx})
+	})
+}
+
+// orderTransferItems lists transfer items by product name.
+func orderTransferItems(db *gorm.DB) *gorm.DB {
+	return db.Order("product_name, id")
+}
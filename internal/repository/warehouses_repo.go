@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"go-crud-api/internal/domain/warehouses"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type gormWarehouseRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWarehouseRepository creates a new GORM warehouse repository.
+func NewGormWarehouseRepository(db *gorm.DB) warehouses.WarehouseRepository {
+	return &gormWarehouseRepository{db: db}
+}
+
+func (r *gormWarehouseRepository) Create(ctx context.Context, warehouse *warehouses.Warehouse) error {
+	return r.db.WithContext(ctx).Create(warehouse).Error
+}
+
+func (r *gormWarehouseRepository) FindByID(ctx context.Context, id uuid.UUID) (*warehouses.Warehouse, error) {
+	var warehouse warehouses.Warehouse
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&warehouse).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *gormWarehouseRepository) List(ctx context.Context) ([]warehouses.Warehouse, error) {
+	var list []warehouses.Warehouse
+	err := r.db.WithContext(ctx).Order("code").Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *gormWarehouseRepository) Update(ctx context.Context, warehouse *warehouses.Warehouse) error {
+	return r.db.WithContext(ctx).Save(warehouse).Error
+}
+
+func (r *gormWarehouseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("product_stock_levels").Where("warehouse_id = ? AND quantity = 0", id).Delete(nil).Error
+		if err != nil {
+			return err
+		}
+		// Levels still holding stock make the foreign key reject the delete.
+		return tx.Where("id = ?", id).Delete(&warehouses.Warehouse{}).Error
+	})
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Job is a unit of background work run periodically inside the API process.
+type Job func(ctx context.Context) error
+
+// RunEvery runs job immediately and then once per interval until ctx is cancelled.
+// Errors are logged and do not stop the schedule.
+func RunEvery(ctx context.Context, name string, interval time.Duration, job Job) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Info().Str("job", name).Dur("interval", interval).Msg("Background job started")
+
+	for {
+		if err := job(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Str("job", name).Msg("Background job failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info().Str("job", name).Msg("Background job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ParseInterval parses a duration setting, falling back to def when it is unset or invalid.
+func ParseInterval(value string, def time.Duration) time.Duration {
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return def
+	}
+	return interval
+}
@@ -0,0 +1,4 @@
+ALTER TABLE products ALTER COLUMN price TYPE NUMERIC(19, 4);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE products ADD CONSTRAINT chk_products_currency CHECK (currency ~ '^[A-Z]{3}$');
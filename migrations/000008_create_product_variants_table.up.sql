@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS product_variants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL,
+    sku VARCHAR(64) NOT NULL,
+    options JSONB NOT NULL DEFAULT '{}',
+    -- A NULL price means the variant is sold at the product's price.
+    price NUMERIC(19, 4) CHECK (price >= 0),
+    stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_product_variants_sku UNIQUE (sku),
+    CONSTRAINT chk_product_variants_options CHECK (jsonb_typeof(options) = 'object'),
+    CONSTRAINT fk_product FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Two variants of the same product cannot share an option combination.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_variants_options ON product_variants(product_id, options);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS product_images (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    content_type VARCHAR(50) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    -- Keys of the original and the thumbnail in the blob store.
+    blob_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_product FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id ON product_images(product_id, position);
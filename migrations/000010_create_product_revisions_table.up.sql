@@ -0,0 +1,13 @@
+-- Revisions are append-only: rows are never updated, and only removed when their product is purged.
+CREATE TABLE IF NOT EXISTS product_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL,
+    revision INTEGER NOT NULL CHECK (revision > 0),
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'rollback')),
+    snapshot JSONB NOT NULL,
+    actor_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_product_revisions_revision UNIQUE (product_id, revision),
+    CONSTRAINT fk_product FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_actor FOREIGN KEY(actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,70 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TYPE price_schedule_status AS ENUM ('pending', 'active', 'completed', 'cancelled', 'skipped');
+
+CREATE TABLE IF NOT EXISTS price_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL,
+    price NUMERIC(19, 4) NOT NULL CHECK (price >= 0),
+    currency CHAR(3) NOT NULL,
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- A NULL end makes the change permanent.
+    effective_to TIMESTAMP WITH TIME ZONE,
+    status price_schedule_status NOT NULL DEFAULT 'pending',
+    -- The price the product had when the schedule started, restored when it ends.
+    previous_price NUMERIC(19, 4),
+    created_by UUID,
+    applied_at TIMESTAMP WITH TIME ZONE,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_price_schedules_window CHECK (effective_to IS NULL OR effective_to > effective_from),
+    CONSTRAINT fk_product FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE SET NULL,
+    -- Open schedules of a product cannot overlap. A permanent change occupies only its start instant.
+    CONSTRAINT excl_price_schedules_overlap EXCLUDE USING gist (
+        product_id WITH =,
+        tstzrange(effective_from, COALESCE(effective_to, effective_from), CASE WHEN effective_to IS NULL THEN '[]' ELSE '[)' END) WITH &&
+    ) WHERE (status IN ('pending', 'active'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_schedules_product_id ON price_schedules(product_id, effective_from);
+CREATE INDEX IF NOT EXISTS idx_price_schedules_open ON price_schedules(effective_from) WHERE status IN ('pending', 'active');
+
+CREATE TABLE IF NOT EXISTS price_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL,
+    price NUMERIC(19, 4) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    -- Set when the change was made by a price schedule starting or ending.
+    schedule_id UUID,
+    actor_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_product FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_schedule FOREIGN KEY(schedule_id) REFERENCES price_schedules(id) ON DELETE SET NULL,
+    CONSTRAINT fk_actor FOREIGN KEY(actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_changes_product_id ON price_changes(product_id, created_at);
+
+-- Price history and product revisions are append-only, like the stock ledger.
+-- Changes cascaded from products, users or schedules run as nested triggers and are let through.
+CREATE OR REPLACE FUNCTION append_only() RETURNS trigger AS $$
+BEGIN
+    IF pg_trigger_depth() > 1 THEN
+        IF TG_OP = 'DELETE' THEN
+            RETURN OLD;
+        END IF;
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_price_changes_append_only
+    BEFORE UPDATE OR DELETE ON price_changes
+    FOR EACH ROW EXECUTE FUNCTION append_only();
+
+CREATE TRIGGER trg_product_revisions_append_only
+    BEFORE UPDATE OR DELETE ON product_revisions
+    FOR EACH ROW EXECUTE FUNCTION append_only();
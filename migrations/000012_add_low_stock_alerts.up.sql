@@ -0,0 +1,23 @@
+-- A NULL threshold disables low-stock alerts for the product.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_threshold INTEGER CHECK (reorder_threshold >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_products_low_stock ON products(owner_id, stock) WHERE reorder_threshold IS NOT NULL AND stock <= reorder_threshold AND deleted_at IS NULL;
+
+-- Low-stock alerts are written in the transaction that changes the stock and
+-- delivered afterwards by a background job, so rolled-back changes raise none.
+CREATE TABLE IF NOT EXISTS stock_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL,
+    owner_id UUID NOT NULL,
+    product_name VARCHAR(120) NOT NULL,
+    stock INTEGER NOT NULL,
+    threshold INTEGER NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_product FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_owner FOREIGN KEY(owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_alerts_pending ON stock_alerts(created_at) WHERE delivered_at IS NULL;
@@ -0,0 +1,35 @@
+CREATE TYPE order_status AS ENUM ('pending', 'paid', 'shipped', 'cancelled');
+
+CREATE TABLE IF NOT EXISTS orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL,
+    status order_status NOT NULL DEFAULT 'pending',
+    currency CHAR(3) NOT NULL,
+    total NUMERIC(19,4) NOT NULL CHECK (total >= 0),
+    paid_at TIMESTAMP WITH TIME ZONE,
+    shipped_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_customer FOREIGN KEY(customer_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders(customer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders(status, created_at);
+
+-- Line items keep a snapshot of the product name and price at the time of the order,
+-- so they outlive later price changes and the purge of the product.
+CREATE TABLE IF NOT EXISTS order_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL,
+    product_id UUID,
+    product_name VARCHAR(120) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price NUMERIC(19,4) NOT NULL CHECK (unit_price >= 0),
+    line_total NUMERIC(19,4) NOT NULL CHECK (line_total >= 0),
+    CONSTRAINT fk_order FOREIGN KEY(order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    CONSTRAINT fk_product FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items(order_id);
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items(product_id);
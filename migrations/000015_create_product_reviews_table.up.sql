@@ -0,0 +1,25 @@
+-- Aggregate rating of the visible reviews, refreshed in the same transaction as every review write.
+ALTER TABLE products ADD COLUMN rating_average NUMERIC(3,2) NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_products_rating ON products(rating_average DESC, rating_count DESC) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS product_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title VARCHAR(120) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    -- Reviews hidden by a moderator are kept but left out of listings and the aggregate rating.
+    hidden_at TIMESTAMP WITH TIME ZONE,
+    hidden_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_product_reviews_product_user UNIQUE (product_id, user_id),
+    CONSTRAINT fk_product FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_hidden_by FOREIGN KEY(hidden_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_reviews_product_id ON product_reviews(product_id, created_at);
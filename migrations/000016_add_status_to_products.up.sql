@@ -0,0 +1,8 @@
+CREATE TYPE product_status AS ENUM ('draft', 'published', 'archived');
+
+-- Existing products were already shown on the website, so they start out published;
+-- new products are drafts until they are published.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status product_status NOT NULL DEFAULT 'published';
+ALTER TABLE products ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE INDEX IF NOT EXISTS idx_products_published ON products(created_at, id) WHERE status = 'published' AND deleted_at IS NULL;
@@ -0,0 +1,7 @@
+-- Products move through draft -> active -> discontinued -> archived. Published products
+-- become active; discontinued ones keep selling their remaining stock.
+ALTER TYPE product_status RENAME VALUE 'published' TO 'active';
+ALTER TYPE product_status ADD VALUE IF NOT EXISTS 'discontinued' BEFORE 'archived';
+
+-- The new value cannot be used in this transaction, so the catalogue index is rebuilt by the next migration.
+DROP INDEX IF EXISTS idx_products_published;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_products_listed ON products(created_at, id) WHERE status IN ('active', 'discontinued') AND deleted_at IS NULL;
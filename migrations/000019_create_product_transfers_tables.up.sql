@@ -0,0 +1,34 @@
+CREATE TYPE product_transfer_status AS ENUM ('pending', 'accepted', 'declined', 'cancelled');
+
+-- Transfers are kept once resolved, as the audit trail of product ownership changes.
+CREATE TABLE IF NOT EXISTS product_transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    from_user_id UUID,
+    to_user_id UUID,
+    status product_transfer_status NOT NULL DEFAULT 'pending',
+    requested_by UUID,
+    resolved_by UUID,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_from_user FOREIGN KEY(from_user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_to_user FOREIGN KEY(to_user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_requested_by FOREIGN KEY(requested_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_resolved_by FOREIGN KEY(resolved_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_transfers_from_user ON product_transfers(from_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_product_transfers_to_user ON product_transfers(to_user_id, created_at);
+
+-- transferred records whether the product still belonged to the sender when the transfer was accepted.
+CREATE TABLE IF NOT EXISTS product_transfer_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_id UUID NOT NULL,
+    product_id UUID,
+    product_name VARCHAR(120) NOT NULL,
+    transferred BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT fk_transfer FOREIGN KEY(transfer_id) REFERENCES product_transfers(id) ON DELETE CASCADE,
+    CONSTRAINT fk_product FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_transfer_items_transfer ON product_transfer_items(transfer_id);
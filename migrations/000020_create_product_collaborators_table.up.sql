@@ -0,0 +1,14 @@
+CREATE TYPE product_collaborator_role AS ENUM ('viewer', 'editor');
+
+-- Collaborators share access to a product with its owner.
+CREATE TABLE IF NOT EXISTS product_collaborators (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role product_collaborator_role NOT NULL,
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_collaborators_user_id ON product_collaborators(user_id);
@@ -0,0 +1,9 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS sku VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS gtin VARCHAR(14) CHECK (gtin ~ '^([0-9]{8}|[0-9]{12,14})$');
+
+-- SKUs and barcodes are unique among an owner's products outside the trash. SKUs are
+-- compared case-insensitively and barcodes as GTIN-14, so a UPC-A code matches the
+-- same code stored as EAN-13.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_products_owner_sku ON products(owner_id, LOWER(sku)) WHERE sku IS NOT NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_products_owner_gtin ON products(owner_id, LPAD(gtin, 14, '0')) WHERE gtin IS NOT NULL AND deleted_at IS NULL;
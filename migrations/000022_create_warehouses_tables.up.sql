@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS warehouses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_warehouses_code UNIQUE (code),
+    CONSTRAINT chk_warehouses_code CHECK (code ~ '^[A-Z0-9]+(-[A-Z0-9]+)*$')
+);
+
+-- Once a product has stock levels, its stock is the sum of their quantities.
+-- Warehouses still holding stock cannot be deleted: their empty levels are deleted with the
+-- warehouse in one transaction, and any level left makes the foreign key reject the delete.
+CREATE TABLE IF NOT EXISTS product_stock_levels (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, warehouse_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_stock_levels_warehouse_id ON product_stock_levels(warehouse_id);
+
+-- Stock moved between warehouses; the product's total stock does not change.
+-- Like stock_movements, rows are only ever inserted.
+CREATE TABLE IF NOT EXISTS stock_transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    from_warehouse_id UUID REFERENCES warehouses(id) ON DELETE SET NULL,
+    to_warehouse_id UUID REFERENCES warehouses(id) ON DELETE SET NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_product_id ON stock_transfers(product_id, created_at);
+
+-- The warehouse a movement was taken from or added to; NULL for products without
+-- stock levels and for sales drawn from several warehouses.
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS warehouse_id UUID REFERENCES warehouses(id) ON DELETE SET NULL;
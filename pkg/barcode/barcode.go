@@ -0,0 +1,58 @@
+package barcode
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// GTINLength is the length of a GTIN-14, the longest GTIN format. Shorter codes
+// are the same number with leading zeros dropped.
+const GTINLength = 14
+
+// Valid reports whether code is a GTIN-8, UPC-A (GTIN-12), EAN-13 (GTIN-13) or
+// GTIN-14 with a correct check digit.
+func Valid(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	// Weights alternate 3, 1, ... from the digit left of the check digit.
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	check := code[len(code)-1]
+	if check < '0' || check > '9' {
+		return false
+	}
+	return (10-sum%10)%10 == int(check-'0')
+}
+
+// Normalize pads a valid code with leading zeros to its GTIN-14 form, so that the
+// same product scanned as UPC-A or EAN-13 compares equal.
+func Normalize(code string) string {
+	if len(code) >= GTINLength {
+		return code
+	}
+	return strings.Repeat("0", GTINLength-len(code)) + code
+}
+
+// RegisterValidations registers the "gtin" validation tag, which accepts strings
+// and string pointers holding a code accepted by Valid.
+func RegisterValidations(v *validator.Validate) {
+	_ = v.RegisterValidation("gtin", func(fl validator.FieldLevel) bool {
+		return Valid(fl.Field().String())
+	})
+}
@@ -0,0 +1,43 @@
+package barcode
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	assert.True(t, Valid("4006381333931"))  // EAN-13
+	assert.True(t, Valid("036000291452"))   // UPC-A
+	assert.True(t, Valid("96385074"))       // GTIN-8
+	assert.True(t, Valid("10012345678902")) // GTIN-14
+
+	assert.False(t, Valid("4006381333932"))
+	assert.False(t, Valid("036000291453"))
+	assert.False(t, Valid("40063813339a1"))
+	assert.False(t, Valid("400638133393"))
+	assert.False(t, Valid(""))
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "00036000291452", Normalize("036000291452"))
+	assert.Equal(t, Normalize("036000291452"), Normalize("0036000291452"))
+	assert.Equal(t, "10012345678902", Normalize("10012345678902"))
+}
+
+func TestGTINValidation(t *testing.T) {
+	type request struct {
+		GTIN *string `validate:"omitempty,gtin"`
+	}
+
+	v := validator.New()
+	RegisterValidations(v)
+
+	valid := "4006381333931"
+	assert.NoError(t, v.Struct(request{GTIN: &valid}))
+	assert.NoError(t, v.Struct(request{}))
+
+	invalid := "4006381333932"
+	assert.Error(t, v.Struct(request{GTIN: &invalid}))
+}
@@ -0,0 +1,53 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidKey(t *testing.T) {
+	assert.True(t, ValidKey("products/1/image.jpg"))
+	assert.True(t, ValidKey("image.jpg"))
+
+	for _, key := range []string{"", "/abs", "a//b", "a/../b", "./a", "a/", `a\b`, "a\x00b"} {
+		assert.False(t, ValidKey(key), key)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	// Test case 1: Put then Get returns the stored bytes
+	require.NoError(t, store.Put(ctx, "products/1/a.txt", strings.NewReader("hello"), 5, "text/plain"))
+
+	rc, err := store.Get(ctx, "products/1/a.txt")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "hello", string(body))
+
+	// Test case 2: Put overwrites an existing blob
+	require.NoError(t, store.Put(ctx, "products/1/a.txt", strings.NewReader("bye"), 3, "text/plain"))
+	rc, err = store.Get(ctx, "products/1/a.txt")
+	require.NoError(t, err)
+	body, _ = io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "bye", string(body))
+
+	// Test case 3: Delete removes the blob and is idempotent
+	require.NoError(t, store.Delete(ctx, "products/1/a.txt"))
+	require.NoError(t, store.Delete(ctx, "products/1/a.txt"))
+	_, err = store.Get(ctx, "products/1/a.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Test case 4: Keys escaping the root are rejected
+	err = store.Put(ctx, "../escape.txt", strings.NewReader("x"), 1, "text/plain")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -0,0 +1,72 @@
+package blobstore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrURLExpired is returned when a signed URL is used after its expiry.
+	ErrURLExpired = errors.New("signed url has expired")
+	// ErrInvalidSignature is returned when a signed URL was not issued by the signer or was altered.
+	ErrInvalidSignature = errors.New("invalid url signature")
+)
+
+// URLSigner issues and checks expiring URLs for blobs, signed with HMAC-SHA256.
+// Signed URLs have the form prefix/key?expires=<unix seconds>&signature=<hex>.
+type URLSigner struct {
+	secret []byte
+	prefix string
+	now    func() time.Time
+}
+
+// NewURLSigner creates a URLSigner issuing URLs under prefix, such as "/v1/images".
+func NewURLSigner(secret, prefix string) *URLSigner {
+	return &URLSigner{secret: []byte(secret), prefix: prefix, now: time.Now}
+}
+
+// Sign returns a URL for key that is valid for ttl.
+func (s *URLSigner) Sign(key string, ttl time.Duration) string {
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.signature(key, expires))
+
+	return s.prefix + "/" + escapePath(key) + "?" + query.Encode()
+}
+
+// Verify checks the expires and signature parameters of a URL issued for key.
+// It returns the expiry time when the URL is valid.
+func (s *URLSigner) Verify(key, expires, signature string) (time.Time, error) {
+	expected, err := hex.DecodeString(s.signature(key, expires))
+	if err != nil {
+		return time.Time{}, err
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, got) {
+		return time.Time{}, ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidSignature
+	}
+	expiry := time.Unix(unix, 0)
+	if !s.now().Before(expiry) {
+		return time.Time{}, ErrURLExpired
+	}
+
+	return expiry, nil
+}
+
+func (s *URLSigner) signature(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,46 @@
+package blobstore
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLSigner(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer := NewURLSigner("secret", "/v1/images")
+	signer.now = func() time.Time { return now }
+
+	signed := signer.Sign("products/1/a.jpg", 15*time.Minute)
+	require.True(t, strings.HasPrefix(signed, "/v1/images/products/1/a.jpg?"))
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	// Test case 1: A fresh URL verifies and reports its expiry
+	expiry, err := signer.Verify("products/1/a.jpg", expires, signature)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute).Unix(), expiry.Unix())
+
+	// Test case 2: The signature does not cover other keys or expiries
+	_, err = signer.Verify("products/1/b.jpg", expires, signature)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = signer.Verify("products/1/a.jpg", "99999999999", signature)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = signer.Verify("products/1/a.jpg", expires, "not-hex")
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Test case 3: URLs signed with another secret are rejected
+	_, err = NewURLSigner("other", "/v1/images").Verify("products/1/a.jpg", expires, signature)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Test case 4: Expired URLs are rejected
+	signer.now = func() time.Time { return now.Add(time.Hour) }
+	_, err = signer.Verify("products/1/a.jpg", expires, signature)
+	assert.ErrorIs(t, err, ErrURLExpired)
+}
@@ -0,0 +1,36 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbnail(t *testing.T) {
+	// Test case 1: Wide images are scaled to the requested width, keeping the aspect ratio
+	wide := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	assert.Equal(t, image.Rect(0, 0, 100, 50), Thumbnail(wide, 100).Bounds())
+
+	// Test case 2: Tall images are scaled to the requested height
+	tall := image.NewRGBA(image.Rect(0, 0, 50, 300))
+	assert.Equal(t, image.Rect(0, 0, 16, 100), Thumbnail(tall, 100).Bounds())
+
+	// Test case 3: Images that already fit are returned unchanged
+	small := image.NewRGBA(image.Rect(0, 0, 80, 60))
+	assert.Same(t, small, Thumbnail(small, 100))
+
+	// Test case 4: Pixels are averaged over the area they cover
+	stripes := image.NewGray(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			if x%2 == 0 {
+				stripes.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	thumb := Thumbnail(stripes, 2)
+	gray := color.GrayModel.Convert(thumb.At(0, 0)).(color.Gray)
+	assert.InDelta(t, 127, gray.Y, 1)
+}
@@ -0,0 +1,58 @@
+package money
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// MaxAmount is the largest amount that fits the NUMERIC(19,4) money columns.
+var MaxAmount = decimal.RequireFromString("999999999999999.9999")
+
+// defaultMinorUnits is the number of decimal places used by most currencies.
+const defaultMinorUnits = 2
+
+// minorUnits lists ISO 4217 currencies whose minor unit is not two decimal places.
+var minorUnits = map[string]int32{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// MinorUnits returns the number of decimal places of a currency's minor unit.
+func MinorUnits(currency string) int32 {
+	if units, ok := minorUnits[currency]; ok {
+		return units
+	}
+	return defaultMinorUnits
+}
+
+// FitsCurrency reports whether amount has no more decimal places than the currency's minor unit allows.
+func FitsCurrency(amount decimal.Decimal, currency string) bool {
+	return amount.Equal(amount.Round(MinorUnits(currency)))
+}
+
+// ParseCurrencies parses a comma-separated list of currency codes, normalising them to upper case.
+func ParseCurrencies(list string) []string {
+	var codes []string
+	for _, code := range strings.Split(list, ",") {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// RegisterValidations registers the "money" validation tag, which accepts
+// non-negative decimal.Decimal values no larger than MaxAmount.
+func RegisterValidations(v *validator.Validate) {
+	_ = v.RegisterValidation("money", func(fl validator.FieldLevel) bool {
+		amount, ok := fl.Field().Interface().(decimal.Decimal)
+		if !ok {
+			return false
+		}
+		return !amount.IsNegative() && amount.LessThanOrEqual(MaxAmount)
+	})
+}
@@ -0,0 +1,56 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinorUnits(t *testing.T) {
+	assert.Equal(t, int32(2), MinorUnits("USD"))
+	assert.Equal(t, int32(0), MinorUnits("JPY"))
+	assert.Equal(t, int32(3), MinorUnits("KWD"))
+}
+
+func TestFitsCurrency(t *testing.T) {
+	assert.True(t, FitsCurrency(decimal.RequireFromString("10.50"), "USD"))
+	assert.False(t, FitsCurrency(decimal.RequireFromString("10.505"), "USD"))
+	assert.True(t, FitsCurrency(decimal.RequireFromString("10.505"), "KWD"))
+	assert.False(t, FitsCurrency(decimal.RequireFromString("100.5"), "JPY"))
+	assert.True(t, FitsCurrency(decimal.RequireFromString("100"), "JPY"))
+}
+
+func TestExactArithmetic(t *testing.T) {
+	sum := decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2"))
+	assert.True(t, sum.Equal(decimal.RequireFromString("0.3")))
+}
+
+func TestParseCurrencies(t *testing.T) {
+	assert.Equal(t, []string{"USD", "EUR", "BRL"}, ParseCurrencies(" usd, EUR,,brl "))
+	assert.Empty(t, ParseCurrencies(""))
+}
+
+func TestMoneyValidation(t *testing.T) {
+	type request struct {
+		Price *decimal.Decimal `validate:"required,money"`
+	}
+
+	v := validator.New()
+	RegisterValidations(v)
+
+	valid := decimal.RequireFromString("99999999999.99")
+	assert.NoError(t, v.Struct(request{Price: &valid}))
+
+	zero := decimal.Zero
+	assert.NoError(t, v.Struct(request{Price: &zero}))
+
+	negative := decimal.RequireFromString("-0.01")
+	assert.Error(t, v.Struct(request{Price: &negative}))
+
+	tooLarge := MaxAmount.Add(decimal.RequireFromString("0.0001"))
+	assert.Error(t, v.Struct(request{Price: &tooLarge}))
+
+	assert.Error(t, v.Struct(request{}))
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailNotifier(t *testing.T) {
+	ctx := context.Background()
+	notifier := NewEmailNotifier(SMTPConfig{Host: "smtp.example.com", Port: "587", Username: "user", Password: "pass", From: "alerts@example.com"})
+
+	var addr, from string
+	var to []string
+	var data []byte
+	var auth smtp.Auth
+	notifier.send = func(a string, au smtp.Auth, f string, t []string, msg []byte) error {
+		addr, auth, from, to, data = a, au, f, t, msg
+		return nil
+	}
+
+	// Test case 1: The message is sent to the recipient as a plain-text email
+	err := notifier.Notify(ctx, Message{
+		Recipient:  "owner@example.com",
+		Subject:    "Estoque baixo: Lâmpada",
+		Body:       "Stock is 2.\nThreshold is 5.",
+		OccurredAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.NotNil(t, auth)
+	assert.Equal(t, "alerts@example.com", from)
+	assert.Equal(t, []string{"owner@example.com"}, to)
+
+	email := string(data)
+	assert.Contains(t, email, "To: owner@example.com\r\n")
+	assert.Contains(t, email, "Subject: =?utf-8?q?Estoque_baixo:_L=C3=A2mpada?=\r\n")
+	assert.Contains(t, email, "Date: Wed, 01 Jan 2025 12:00:00 +0000\r\n")
+	assert.True(t, strings.HasSuffix(email, "\r\n\r\nStock is 2.\r\nThreshold is 5.\r\n"))
+
+	// Test case 2: Messages without a recipient are not sent
+	data = nil
+	require.NoError(t, notifier.Notify(ctx, Message{Subject: "No one"}))
+	assert.Nil(t, data)
+
+	// Test case 3: Send errors are returned
+	notifier.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+	assert.ErrorContains(t, notifier.Notify(ctx, Message{Recipient: "owner@example.com"}), "connection refused")
+}
@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LogNotifier writes messages to the application log. It is useful in development
+// and as a record of notifications sent through other channels.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a LogNotifier.
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify logs msg.
+func (n *LogNotifier) Notify(_ context.Context, msg Message) error {
+	log.Info().
+		Str("event", msg.Event).
+		Str("recipient", msg.Recipient).
+		Str("subject", msg.Subject).
+		Msg(msg.Body)
+	return nil
+}
@@ -0,0 +1,37 @@
+// Package notify delivers notifications to users through pluggable channels.
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Message is a notification about an event. Channels use the parts they need:
+// email sends Subject and Body to Recipient, webhooks post Event and Data as JSON.
+type Message struct {
+	// Event is a machine-readable event name, such as "product.low_stock".
+	Event      string
+	Recipient  string
+	Subject    string
+	Body       string
+	Data       any
+	OccurredAt time.Time
+}
+
+// Notifier delivers messages through a channel.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Multi delivers each message through every notifier in it.
+type Multi []Notifier
+
+// Notify sends msg through every notifier, even if some fail, and returns their errors joined.
+func (m Multi) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, n := range m {
+		errs = append(errs, n.Notify(ctx, msg))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type notifierFunc func(ctx context.Context, msg Message) error
+
+func (f notifierFunc) Notify(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+func TestMulti(t *testing.T) {
+	failed := errors.New("channel down")
+	var delivered []string
+	record := func(name string, err error) Notifier {
+		return notifierFunc(func(_ context.Context, msg Message) error {
+			delivered = append(delivered, name+":"+msg.Event)
+			return err
+		})
+	}
+
+	// Test case 1: Every channel is tried, even after one fails, and the failure is returned
+	err := Multi{record("log", nil), record("webhook", failed), record("email", nil)}.Notify(context.Background(), Message{Event: "product.low_stock"})
+	assert.ErrorIs(t, err, failed)
+	assert.Equal(t, []string{"log:product.low_stock", "webhook:product.low_stock", "email:product.low_stock"}, delivered)
+
+	// Test case 2: No channels is not an error
+	assert.NoError(t, Multi{}.Notify(context.Background(), Message{}))
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	ctx := context.Background()
+	var body []byte
+	var signature string
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	msg := Message{
+		Event:      "product.low_stock",
+		Recipient:  "owner@example.com",
+		Data:       map[string]int{"stock": 2},
+		OccurredAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	// Test case 1: The message is posted as JSON, signed with the secret
+	require.NoError(t, NewWebhookNotifier(server.URL, "secret").Notify(ctx, msg))
+	assert.Equal(t, "sha256="+Sign([]byte("secret"), body), signature)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "product.low_stock", payload["event"])
+	assert.Equal(t, "2025-01-01T12:00:00Z", payload["occurred_at"])
+	assert.Equal(t, map[string]any{"stock": float64(2)}, payload["data"])
+
+	// Test case 2: Without a secret, bodies are not signed
+	require.NoError(t, NewWebhookNotifier(server.URL, "").Notify(ctx, msg))
+	assert.Empty(t, signature)
+
+	// Test case 3: A non-2xx response is an error
+	status = http.StatusInternalServerError
+	assert.Error(t, NewWebhookNotifier(server.URL, "secret").Notify(ctx, msg))
+}
@@ -0,0 +1,36 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ETag formats a resource version as a strong entity tag.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// SetETag sets the ETag header for a resource version.
+func SetETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", ETag(version))
+}
+
+// CheckIfMatch reports whether the If-Match header of the request allows
+// writing a resource currently at the given version.
+// A missing header or "*" always matches; weak tags never match (RFC 9110, section 13.1.1).
+func CheckIfMatch(r *http.Request, version int) bool {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return true
+	}
+
+	current := ETag(version)
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimSpace(tag) == current {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,42 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, `"3"`, ETag(3))
+
+	w := httptest.NewRecorder()
+	SetETag(w, 7)
+	assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int
+		want    bool
+	}{
+		{"missing header", "", 3, true},
+		{"wildcard", "*", 3, true},
+		{"matching tag", `"3"`, 3, true},
+		{"stale tag", `"2"`, 3, false},
+		{"list containing current", `"1", "3"`, 3, true},
+		{"weak tag", `W/"3"`, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PUT", "/", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+			assert.Equal(t, tt.want, CheckIfMatch(r, tt.version))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Fields is a sparse fieldset: the JSON fields a client asked to receive.
+// The id field is always included. A nil Fields keeps every field.
+type Fields []string
+
+// ParseFields reads the comma-separated fields query parameter, rejecting names not in allowed.
+func ParseFields(r *http.Request, allowed []string) (Fields, error) {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil, nil
+	}
+
+	fields := Fields{"id"}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(fields, name) {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		fields = append(fields, name)
+	}
+
+	return fields, nil
+}
+
+// ParseExpand reads the comma-separated expand query parameter, rejecting relations not in allowed.
+// It returns the set of relations to expand.
+func ParseExpand(r *http.Request, allowed ...string) (map[string]bool, error) {
+	expand := make(map[string]bool)
+	raw := r.URL.Query().Get("expand")
+	if raw == "" {
+		return expand, nil
+	}
+
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("unknown expansion %q", name)
+		}
+		expand[name] = true
+	}
+
+	return expand, nil
+}
+
+// JSONFieldNames returns the JSON names of the fields of the struct v, in declaration order.
+// Fields tagged json:"-" are left out.
+func JSONFieldNames(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Apply trims v, a JSON object or array of objects once marshalled, to the fields of the set,
+// in the order they were requested. Fields the object does not have are skipped.
+func (f Fields) Apply(v interface{}) (interface{}, error) {
+	if f == nil {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return v, nil
+	}
+
+	if data[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		for i, item := range items {
+			if items[i], err = f.trim(item); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+
+	return f.trim(data)
+}
+
+// trim keeps the fields of the set in a JSON object.
+func (f Fields) trim(object json.RawMessage) (json.RawMessage, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(object, &values); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, name := range f {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// RespondWithFields sends data, trimmed to fields, as the data of a JSON response.
+func RespondWithFields(w http.ResponseWriter, r *http.Request, statusCode int, fields Fields, data interface{}) {
+	trimmed, err := fields.Apply(data)
+	if err != nil {
+		RespondWithServerError(w, r, err, "Could not encode response")
+		return
+	}
+
+	RespondWithJSON(w, statusCode, Response{Data: trimmed})
+}
@@ -0,0 +1,91 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldsItem struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Price       string  `json:"price"`
+	Description string  `json:"description"`
+	Secret      string  `json:"-"`
+	Owner       *string `json:"owner,omitempty"`
+}
+
+func TestParseFields(t *testing.T) {
+	allowed := JSONFieldNames(fieldsItem{})
+	assert.Equal(t, []string{"id", "name", "price", "description", "owner"}, allowed)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    Fields
+		wantErr bool
+	}{
+		{"not requested", "", nil, false},
+		{"id comes first", "?fields=price,name", Fields{"id", "price", "name"}, false},
+		{"repeats and blanks are dropped", "?fields=name,,name,id", Fields{"id", "name"}, false},
+		{"unknown field", "?fields=name,email", nil, true},
+		{"hidden field", "?fields=Secret", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseFields(httptest.NewRequest("GET", "/"+tt.query, nil), allowed)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestParseExpand(t *testing.T) {
+	expand, err := ParseExpand(httptest.NewRequest("GET", "/?expand=owner", nil), "owner")
+	assert.NoError(t, err)
+	assert.True(t, expand["owner"])
+
+	expand, err = ParseExpand(httptest.NewRequest("GET", "/", nil), "owner")
+	assert.NoError(t, err)
+	assert.False(t, expand["owner"])
+
+	_, err = ParseExpand(httptest.NewRequest("GET", "/?expand=category", nil), "owner")
+	assert.Error(t, err)
+}
+
+func TestFields_Apply(t *testing.T) {
+	items := []fieldsItem{
+		{ID: "1", Name: "Mug", Price: "9.90", Description: "A long description"},
+		{ID: "2", Name: "Lamp", Price: "24.90"},
+	}
+
+	// Arrays are trimmed item by item, in the requested order
+	trimmed, err := Fields{"id", "price", "name"}.Apply(items)
+	assert.NoError(t, err)
+	data, _ := json.Marshal(trimmed)
+	assert.Equal(t, `[{"id":"1","price":"9.90","name":"Mug"},{"id":"2","price":"24.90","name":"Lamp"}]`, string(data))
+
+	// Single objects are trimmed; fields left out by omitempty are skipped
+	trimmed, err = Fields{"id", "owner", "name"}.Apply(&items[0])
+	assert.NoError(t, err)
+	data, _ = json.Marshal(trimmed)
+	assert.Equal(t, `{"id":"1","name":"Mug"}`, string(data))
+
+	// Empty and nil lists stay as they are
+	trimmed, err = Fields{"id", "name"}.Apply([]fieldsItem(nil))
+	assert.NoError(t, err)
+	data, _ = json.Marshal(trimmed)
+	assert.Equal(t, `null`, string(data))
+
+	// A nil set keeps the value as it is
+	trimmed, err = Fields(nil).Apply(items)
+	assert.NoError(t, err)
+	assert.Equal(t, items, trimmed)
+}
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// Content types accepted by PATCH endpoints.
+const (
+	ContentTypeMergePatch = "application/merge-patch+json"
+	ContentTypeJSONPatch  = "application/json-patch+json"
+)
+
+var (
+	// ErrUnsupportedPatchType is returned when the request body is neither a merge patch nor a JSON patch.
+	ErrUnsupportedPatchType = errors.New("unsupported patch content type")
+	// ErrInvalidPatch is returned when the patch document is malformed or cannot be applied.
+	ErrInvalidPatch = errors.New("invalid patch document")
+	// ErrPatchTestFailed is returned when a JSON patch "test" operation does not match.
+	ErrPatchTestFailed = errors.New("patch test operation failed")
+)
+
+// DecodePatch reads the body of a PATCH request and returns it as a JSON Merge Patch (RFC 7396).
+// JSON Patch (RFC 6902) bodies are applied to the JSON representation of original and converted
+// into the equivalent merge patch, so callers only have to handle a single format.
+// Plain application/json bodies are treated as merge patches.
+func DecodePatch(r *http.Request, original interface{}) (map[string]json.RawMessage, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, ErrUnsupportedPatchType
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var mergePatch []byte
+	switch mediaType {
+	case ContentTypeMergePatch, "application/json":
+		mergePatch = body
+	case ContentTypeJSONPatch:
+		mergePatch, err = jsonPatchToMergePatch(body, original)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnsupportedPatchType
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(mergePatch, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("%w: patch must be a JSON object", ErrInvalidPatch)
+	}
+
+	return fields, nil
+}
+
+// jsonPatchToMergePatch applies a JSON patch to original and diffs the result against it.
+func jsonPatchToMergePatch(body []byte, original interface{}) ([]byte, error) {
+	patch, err := jsonpatch.DecodePatch(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	doc, err := json.Marshal(original)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal original document: %w", err)
+	}
+
+	modified, err := patch.Apply(doc)
+	if err != nil {
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, fmt.Errorf("%w: %v", ErrPatchTestFailed, err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	mergePatch, err := jsonpatch.CreateMergePatch(doc, modified)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return mergePatch, nil
+}
+
+// RespondWithPatchError maps errors returned by DecodePatch to the matching HTTP response.
+func RespondWithPatchError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUnsupportedPatchType):
+		RespondWithError(w, "unsupported_media_type", "Content-Type must be "+ContentTypeMergePatch+" or "+ContentTypeJSONPatch, http.StatusUnsupportedMediaType)
+	case errors.Is(err, ErrPatchTestFailed):
+		RespondWithError(w, "conflict", err.Error(), http.StatusConflict)
+	default:
+		RespondWithError(w, "bad_request", err.Error(), http.StatusBadRequest)
+	}
+}
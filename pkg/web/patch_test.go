@@ -0,0 +1,66 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type patchTarget struct {
+	Name  string `json:"name"`
+	Stock int    `json:"stock"`
+}
+
+func TestDecodePatch_MergePatch(t *testing.T) {
+	r := httptest.NewRequest("PATCH", "/", strings.NewReader(`{"stock": 0, "description": null}`))
+	r.Header.Set("Content-Type", ContentTypeMergePatch)
+
+	fields, err := DecodePatch(r, patchTarget{Name: "Widget", Stock: 5})
+	assert.NoError(t, err)
+	assert.Len(t, fields, 2)
+	assert.Equal(t, json.RawMessage(`0`), fields["stock"])
+	assert.Equal(t, json.RawMessage(`null`), fields["description"])
+}
+
+func TestDecodePatch_JSONPatch(t *testing.T) {
+	body := `[{"op": "test", "path": "/name", "value": "Widget"}, {"op": "replace", "path": "/stock", "value": 0}]`
+	r := httptest.NewRequest("PATCH", "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", ContentTypeJSONPatch)
+
+	fields, err := DecodePatch(r, patchTarget{Name: "Widget", Stock: 5})
+	assert.NoError(t, err)
+	assert.Len(t, fields, 1)
+	assert.Equal(t, json.RawMessage(`0`), fields["stock"])
+}
+
+func TestDecodePatch_JSONPatchTestFailed(t *testing.T) {
+	body := `[{"op": "test", "path": "/name", "value": "Gadget"}, {"op": "replace", "path": "/stock", "value": 0}]`
+	r := httptest.NewRequest("PATCH", "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", ContentTypeJSONPatch)
+
+	_, err := DecodePatch(r, patchTarget{Name: "Widget", Stock: 5})
+	assert.ErrorIs(t, err, ErrPatchTestFailed)
+}
+
+func TestDecodePatch_Errors(t *testing.T) {
+	// Unsupported content type
+	r := httptest.NewRequest("PATCH", "/", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "text/plain")
+	_, err := DecodePatch(r, patchTarget{})
+	assert.ErrorIs(t, err, ErrUnsupportedPatchType)
+
+	// Merge patch that is not an object
+	r = httptest.NewRequest("PATCH", "/", strings.NewReader(`[1, 2]`))
+	r.Header.Set("Content-Type", ContentTypeMergePatch)
+	_, err = DecodePatch(r, patchTarget{})
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+
+	// Malformed JSON patch
+	r = httptest.NewRequest("PATCH", "/", strings.NewReader(`{"op": "replace"}`))
+	r.Header.Set("Content-Type", ContentTypeJSONPatch)
+	_, err = DecodePatch(r, patchTarget{})
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+}
@@ -3,6 +3,9 @@ package web
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 )
 
 // Response is the standard API response format.
@@ -17,8 +20,16 @@ type ApiError struct {
 	Message string `json:"message"`
 }
 
-// RespondWithError sends an error response.
+// RespondWithError sends an error response. Server errors go through RespondWithServerError,
+// which logs their cause against the request; a 5xx sent here is logged without one.
 func RespondWithError(w http.ResponseWriter, code string, message string, statusCode int) {
+	if statusCode >= http.StatusInternalServerError {
+		log.Error().Str("code", code).Int("status", statusCode).Msg(message)
+	}
+	respondWithError(w, code, message, statusCode)
+}
+
+func respondWithError(w http.ResponseWriter, code string, message string, statusCode int) {
 	RespondWithJSON(w, statusCode, Response{
 		Error: &ApiError{
 			Code:    code,
@@ -27,6 +38,13 @@ func RespondWithError(w http.ResponseWriter, code string, message string, status
 	})
 }
 
+// RespondWithServerError sends a 500 internal_error response with message and logs err,
+// the cause the client is not shown, with the logger of the request.
+func RespondWithServerError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	zerolog.Ctx(r.Context()).Error().Err(err).Msg(message)
+	respondWithError(w, "internal_error", message, http.StatusInternalServerError)
+}
+
 // RespondWithJSON sends a JSON response.
 func RespondWithJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
 	response, _ := json.Marshal(payload)
@@ -0,0 +1,61 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRespondWithError(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(logger zerolog.Logger) { log.Logger = logger }(log.Logger)
+	log.Logger = zerolog.New(&buf)
+
+	// Test case 1: Client errors are not logged
+	w := httptest.NewRecorder()
+	RespondWithError(w, "not_found", "Product not found", http.StatusNotFound)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, buf.String())
+
+	// Test case 2: Server errors sent without a cause are still logged
+	w = httptest.NewRecorder()
+	RespondWithError(w, "unavailable", "Service unavailable", http.StatusServiceUnavailable)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "unavailable", entry["code"])
+	assert.EqualValues(t, http.StatusServiceUnavailable, entry["status"])
+	assert.Equal(t, "Service unavailable", entry["message"])
+}
+
+func TestRespondWithServerError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).With().Str("request_id", "req-1").Logger()
+	r := httptest.NewRequest(http.MethodGet, "/v1/products", nil)
+	r = r.WithContext(logger.WithContext(r.Context()))
+	w := httptest.NewRecorder()
+
+	RespondWithServerError(w, r, errors.New("connection refused"), "Could not fetch products")
+
+	// The client gets the message, the log gets the cause
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var response Response
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, &ApiError{Code: "internal_error", Message: "Could not fetch products"}, response.Error)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "connection refused", entry["error"])
+	assert.Equal(t, "Could not fetch products", entry["message"])
+}